@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/daemon"
+)
+
+func runDaemon(args []string) error {
+	if hasFlag(args, "--help") || hasFlag(args, "-h") {
+		printDaemonUsage()
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if pos := positionalArgs(args); len(pos) > 0 && pos[0] == "query" {
+		return runDaemonQuery(daemon.SocketPath(cfg.CacheDir), pos[1:])
+	}
+
+	interval := 5 * time.Second
+	if v := getFlagValue(args, "--interval"); v != "" {
+		if interval, err = time.ParseDuration(v); err != nil || interval <= 0 {
+			return fmt.Errorf("invalid --interval %q", v)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := daemon.Run(ctx, cfg, interval); err != nil {
+		if errors.Is(err, daemon.ErrRunning) {
+			return fmt.Errorf("%w on %s", err, daemon.SocketPath(cfg.CacheDir))
+		}
+		return err
+	}
+	return nil
+}
+
+// runDaemonQuery calls one daemon method and prints the result as JSON.
+func runDaemonQuery(socket string, args []string) error {
+	if len(args) == 0 {
+		printDaemonUsage()
+		return fmt.Errorf("missing method")
+	}
+	var params any
+	if args[0] == "session" {
+		if len(args) < 2 {
+			return fmt.Errorf("usage: helm daemon query session <name>")
+		}
+		params = map[string]string{"name": args[1]}
+	}
+
+	var result json.RawMessage
+	if err := daemon.Call(socket, args[0], params, &result); err != nil {
+		return err
+	}
+	fmt.Println(string(result))
+	return nil
+}
+
+func printDaemonUsage() {
+	fmt.Println("Usage: helm daemon [--interval <duration>]")
+	fmt.Println("       helm daemon query <ping | state | session <name> | refresh>")
+	fmt.Println()
+	fmt.Println("Keeps sessions, git status and agent statuses in memory and serves")
+	fmt.Println("them as JSON-RPC 2.0 (one object per line) on the Unix socket")
+	fmt.Println("<cache_dir>/helm.sock. The TUI and `helm statusline` read from it")
+	fmt.Println("when it runs.")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --interval <duration>   Full refresh interval (default: 5s); agent")
+	fmt.Println("                          statuses also refresh on every status file change")
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/layout"
+)
+
+func runLayout(args []string) error {
+	pos := positionalArgs(args)
+	if len(pos) == 0 || pos[0] != "trust" || len(pos) > 2 {
+		printLayoutUsage()
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	dir := "."
+	if len(pos) == 2 {
+		dir = pos[1]
+	}
+	path, err := layout.Trust(cfg.CacheDir, dir)
+	if err != nil {
+		return fmt.Errorf("failed to trust project file: %w", err)
+	}
+	fmt.Printf("Trusted %s; its pane commands run in new sessions until it changes\n", path)
+	return nil
+}
+
+func printLayoutUsage() {
+	fmt.Println("Usage: helm layout trust [dir]")
+	fmt.Println()
+	fmt.Println("Allows the pane commands of a project's .helm.yml (default: the current")
+	fmt.Println("dir) to run. Until then, sessions get its windows and panes only.")
+	fmt.Println("Any change to the file needs trusting again.")
+}
@@ -1,16 +1,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strconv"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/layout"
 	"github.com/black-atom-industries/helm/internal/model"
+	"github.com/black-atom-industries/helm/internal/session"
 	"github.com/black-atom-industries/helm/internal/tmux"
 	"github.com/black-atom-industries/helm/internal/ui"
 )
@@ -56,6 +57,12 @@ func main() {
 				os.Exit(1)
 			}
 			return
+		case "next-waiting":
+			if err := runNextWaiting(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
 		case "tmux-bindings":
 			if err := printTmuxBindings(); err != nil {
 				fmt.Printf("Error: %v\n", err)
@@ -74,9 +81,51 @@ func main() {
 				os.Exit(1)
 			}
 			return
+		case "sessions":
+			if err := runSessions(remaining[1:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "snapshot":
+			if err := runSnapshot(remaining[1:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "reap":
+			if err := runReap(remaining[1:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "statusline":
+			if err := runStatusline(remaining[1:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "watch":
+			if err := runWatch(remaining[1:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "daemon":
+			if err := runDaemon(remaining[1:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "layout":
+			if err := runLayout(remaining[1:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
 		default:
 			fmt.Printf("Unknown command: %s\n", remaining[0])
-			fmt.Println("Usage: helm [--initial-view <mode>] [init | setup | repos | bookmark <N> | tmux-bindings]")
+			fmt.Println("Usage: helm [--initial-view <mode>] [init | setup | repos | sessions | snapshot | reap | watch | daemon | layout | statusline | next-waiting | bookmark <N> | tmux-bindings]")
 			os.Exit(1)
 		}
 	}
@@ -110,12 +159,23 @@ func main() {
 	m := model.New(currentSession, cfg, initialView)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	m.Close()
+	if err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// printLayoutError reports a layout.Apply error on stderr.
+func printLayoutError(err error) {
+	if errors.Is(err, layout.ErrUntrusted) {
+		fmt.Fprintf(os.Stderr, "helm: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "helm: layout failed: %v\n", err)
+}
+
 // runBookmark opens the bookmark at slot N (0-9)
 func runBookmark(slotStr string) error {
 	slot, err := strconv.Atoi(slotStr)
@@ -133,7 +193,7 @@ func runBookmark(slotStr string) error {
 	}
 
 	bookmark := cfg.Bookmarks[slot]
-	sessionName := config.ExtractSessionName(bookmark.Path, cfg.ProjectDirs, cfg.ProjectDepth)
+	sessionName := cfg.BookmarkSessionName(bookmark)
 
 	// Create session if it doesn't exist
 	if !tmux.SessionExists(sessionName) {
@@ -141,17 +201,9 @@ func runBookmark(slotStr string) error {
 			return fmt.Errorf("failed to create session: %w", err)
 		}
 
-		// Apply layout if configured
-		if cfg.EnableLayouts && cfg.Layout != "" && cfg.LayoutDir != "" {
-			layoutPath := filepath.Join(cfg.LayoutDir, cfg.Layout+".sh")
-			if _, err := os.Stat(layoutPath); err == nil {
-				cmd := exec.Command(layoutPath, sessionName, bookmark.Path)
-				cmd.Env = append(os.Environ(),
-					"TMUX_SESSION="+sessionName,
-					"TMUX_WORKING_DIR="+bookmark.Path,
-				)
-				_ = cmd.Run()
-			}
+		// Apply session template or layout script if configured
+		if err := layout.Apply(cfg, sessionName, bookmark.Path); err != nil {
+			printLayoutError(err)
 		}
 	}
 
@@ -159,6 +211,26 @@ func runBookmark(slotStr string) error {
 	return tmux.SwitchClient(sessionName)
 }
 
+// runNextWaiting switches to the pane of the longest-waiting agent,
+// cycling on repeated calls. Meant to be bound to a tmux key, so "nothing
+// waiting" is reported as a tmux message rather than run-shell output.
+func runNextWaiting() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	_, err = session.NextWaiting(cfg)
+	if errors.Is(err, session.ErrNoneWaiting) {
+		if os.Getenv("TMUX") != "" {
+			return tmux.ShowMessage("helm: no agent is waiting")
+		}
+		fmt.Println("No agent is waiting.")
+		return nil
+	}
+	return err
+}
+
 // printTmuxBindings outputs tmux bind commands for configured bookmarks
 // Uses Alt+Shift+number keybindings (M-) through M-()
 func printTmuxBindings() error {
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/reap"
+	"github.com/black-atom-industries/helm/internal/session"
+	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+// reapInfo is one session in the reap output.
+type reapInfo struct {
+	Name         string    `json:"name"`
+	LastActivity time.Time `json:"last_activity"`
+	Eligible     bool      `json:"eligible"`
+	Reason       string    `json:"reason,omitempty"` // Why it's kept
+	Killed       bool      `json:"killed,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+func printReapUsage() {
+	fmt.Println("Usage: helm reap [flags]")
+	fmt.Println()
+	fmt.Println("Lists the sessions eligible for cleanup by the reap policies of the config.")
+	fmt.Println("Nothing is killed without --kill.")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --kill                         Kill the eligible sessions, after confirmation")
+	fmt.Println("  --yes                          Don't ask for confirmation")
+	fmt.Println("  --no-snapshot                  Don't save the sessions to reaped.json first")
+	fmt.Println("  --idle <duration>              Override reap.idle_after (e.g. 12h)")
+	fmt.Println("  --all                          List the kept sessions too, with the reason")
+	fmt.Println("  --json                         Output JSON")
+}
+
+func runReap(args []string) error {
+	if hasFlag(args, "--help") || hasFlag(args, "-h") {
+		printReapUsage()
+		return nil
+	}
+	jsonOut := hasFlag(args, "--json")
+	kill := hasFlag(args, "--kill")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	policy := cfg.Reap
+	if idle := getFlagValue(args, "--idle"); idle != "" {
+		d, err := time.ParseDuration(idle)
+		if err != nil {
+			return fmt.Errorf("invalid --idle: %w", err)
+		}
+		policy.IdleAfter = d
+	}
+	save := policy.Snapshot && !hasFlag(args, "--no-snapshot")
+
+	// Never reap the session helm runs in
+	current := ""
+	if os.Getenv("TMUX") != "" {
+		current, _ = tmux.CurrentSession()
+	}
+	sessions, err := reap.Collect(cfg, current, session.LoadPins(cfg.CacheDir))
+	if err != nil {
+		return fmt.Errorf("%w (is tmux running?)", err)
+	}
+
+	now := time.Now()
+	var infos []reapInfo
+	var eligible []string
+	for _, s := range sessions {
+		reason := reap.Check(policy, s, now)
+		infos = append(infos, reapInfo{Name: s.Name, LastActivity: s.LastActivity, Eligible: reason == "", Reason: reason})
+		if reason == "" {
+			eligible = append(eligible, s.Name)
+		}
+	}
+
+	if !jsonOut {
+		printReapList(infos, hasFlag(args, "--all"))
+	}
+
+	if kill && len(eligible) > 0 {
+		if !hasFlag(args, "--yes") && !confirm(fmt.Sprintf("Kill %d sessions?", len(eligible))) {
+			if jsonOut {
+				printJSON(map[string]any{"sessions": orEmpty(infos)})
+			}
+			return nil
+		}
+		saved := false
+		for _, r := range reap.Kill(cfg.CacheDir, eligible, save) {
+			saved = saved || r.Saved
+			for i := range infos {
+				if infos[i].Name != r.Session {
+					continue
+				}
+				infos[i].Killed = r.Err == nil
+				if r.Err != nil {
+					infos[i].Error = r.Err.Error()
+				}
+			}
+			if jsonOut {
+				continue
+			}
+			if r.Err != nil {
+				fmt.Printf("  ✗ %s: %v\n", r.Session, r.Err)
+			} else {
+				fmt.Printf("  ✓ killed %s\n", r.Session)
+			}
+		}
+		if saved && !jsonOut {
+			fmt.Printf("\nSaved to %s (restore with 'helm snapshot restore --file %[1]s')\n", reap.SnapshotPath(cfg.CacheDir))
+		}
+	}
+
+	if jsonOut {
+		printJSON(map[string]any{"sessions": orEmpty(infos)})
+	} else if !kill && len(eligible) > 0 {
+		fmt.Println("\nDry run: pass --kill to kill them.")
+	}
+	return nil
+}
+
+// printReapList prints the eligible sessions, and with all the kept ones.
+func printReapList(infos []reapInfo, all bool) {
+	eligible := 0
+	for _, s := range infos {
+		if s.Eligible {
+			eligible++
+			fmt.Printf("  %s  %s\n", s.Name, ui.FormatTimeAgo(s.LastActivity))
+		}
+	}
+	if eligible == 0 {
+		fmt.Println("No sessions to reap.")
+	}
+	if !all {
+		return
+	}
+	for _, s := range infos {
+		if !s.Eligible {
+			fmt.Printf("  %s  %s  (kept: %s)\n", s.Name, ui.FormatTimeAgo(s.LastActivity), s.Reason)
+		}
+	}
+}
+
+// confirm asks a yes/no question on the terminal; anything but y is no.
+// The question goes to stderr, so it never mixes with --json output.
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
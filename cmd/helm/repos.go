@@ -99,12 +99,11 @@ func collectRepoStatuses(repos []config.RepoInfo) []repoStatus {
 			defer func() { <-sem }()
 
 			sync := git.GetSyncStatus(r.Path)
-			branch, _ := git.GetBranch(r.Path)
 
 			results[idx] = repoStatus{
 				Name:   r.Name,
 				State:  string(sync.State),
-				Branch: branch,
+				Branch: sync.Branch,
 				Ahead:  sync.Ahead,
 				Behind: sync.Behind,
 				Dirty:  sync.Dirty,
@@ -149,7 +148,7 @@ func runReposAdd(args []string) error {
 	}
 
 	fmt.Printf("  → Cloning %s...\n", ownerRepo)
-	if err := giturl.CloneRepo(args[0], destPath); err != nil {
+	if err := giturl.CloneRepo(args[0], destPath, giturl.CloneOptions{}); err != nil {
 		return fmt.Errorf("failed to clone %s: %w", ownerRepo, err)
 	}
 
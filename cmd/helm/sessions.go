@@ -0,0 +1,475 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/layout"
+	"github.com/black-atom-industries/helm/internal/session"
+	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+// sessionInfo is one session in the list output: the same session →
+// window → pane tree the TUI renders, plus git and agent status.
+type sessionInfo struct {
+	Name         string       `json:"name"`
+	Current      bool         `json:"current"`
+	LastActivity time.Time    `json:"last_activity"`
+	Path         string       `json:"path,omitempty"`
+	Group        string       `json:"group,omitempty"`
+	Pinned       bool         `json:"pinned,omitempty"`
+	Git          *gitInfo     `json:"git,omitempty"`
+	Agents       []agentInfo  `json:"agents"`
+	Windows      []windowInfo `json:"windows"`
+}
+
+type windowInfo struct {
+	Index  int        `json:"index"`
+	Name   string     `json:"name"`
+	Active bool       `json:"active"`
+	Panes  []paneInfo `json:"panes"`
+}
+
+type paneInfo struct {
+	Index   int    `json:"index"`
+	Command string `json:"command"`
+	Path    string `json:"path"`
+	Active  bool   `json:"active"`
+	Agent   string `json:"agent,omitempty"` // Agent kind running in the pane
+}
+
+type gitInfo struct {
+	Dirty     int `json:"dirty"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+type agentInfo struct {
+	Kind      string    `json:"kind"`
+	State     string    `json:"state"`
+	Since     time.Time `json:"since"`
+	Tool      string    `json:"tool,omitempty"`
+	Cwd       string    `json:"cwd,omitempty"`
+	SessionID string    `json:"session_id,omitempty"`
+}
+
+// errSessionsUsage is returned after printing the usage for invalid
+// arguments, so helm exits non-zero.
+var errSessionsUsage = errors.New("invalid arguments")
+
+func runSessions(args []string) error {
+	if len(args) == 0 {
+		printSessionsUsage()
+		return errSessionsUsage
+	}
+
+	switch args[0] {
+	case "--help", "-h":
+		printSessionsUsage()
+		return nil
+	case "list":
+		return runSessionsList(args[1:])
+	case "switch":
+		return runSessionsSwitch(args[1:])
+	case "kill":
+		return runSessionsKill(args[1:])
+	case "new":
+		return runSessionsNew(args[1:])
+	case "rename":
+		return runSessionsRename(args[1:])
+	case "group":
+		return runSessionsGroup(args[1:])
+	case "pin":
+		return runSessionsPin(args[1:], true)
+	case "unpin":
+		return runSessionsPin(args[1:], false)
+	default:
+		fmt.Printf("Unknown sessions command: %s\n", args[0])
+		printSessionsUsage()
+		return errSessionsUsage
+	}
+}
+
+func printSessionsUsage() {
+	fmt.Println("Usage: helm sessions <command> [flags]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list   [--json]                List sessions with windows, panes, git and agent status")
+	fmt.Println("  switch <session[:window]>      Switch the client to a session")
+	fmt.Println("  kill   <session> [--json]      Kill a session")
+	fmt.Println("  new    <name> [--dir <path>]   Create a session (and apply its layout)")
+	fmt.Println("  rename <old> <new> [--json]    Rename a session")
+	fmt.Println("  group  <session> [group]       Tag a session with a group (no group: untag)")
+	fmt.Println("  pin    <session>               Pin a session to the next fixed slot at the top")
+	fmt.Println("  unpin  <session>               Release a session's slot")
+}
+
+// positionalArgs returns args without flags and their values.
+func positionalArgs(args []string, valueFlags ...string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if len(a) > 1 && a[0] == '-' {
+			for _, f := range valueFlags {
+				if a == f {
+					i++ // skip value
+				}
+			}
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// printJSON prints v as a single line of JSON.
+func printJSON(v any) {
+	data, _ := json.Marshal(v)
+	fmt.Println(string(data))
+}
+
+// --- list ---
+
+func runSessionsList(args []string) error {
+	jsonOut := hasFlag(args, "--json")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sessions, err := tmux.ListSessions("")
+	if err != nil {
+		return fmt.Errorf("failed to list sessions (is tmux running?): %w", err)
+	}
+
+	infos := collectSessionInfos(cfg, sessions)
+
+	if jsonOut {
+		printJSON(struct {
+			Sessions []sessionInfo `json:"sessions"`
+		}{Sessions: infos})
+		return nil
+	}
+
+	if len(infos) == 0 {
+		fmt.Println("No sessions.")
+		return nil
+	}
+	for _, s := range infos {
+		marker := " "
+		if s.Current {
+			marker = "*"
+		}
+		line := fmt.Sprintf("%s %s  %s", marker, s.Name, ui.FormatTimeAgo(s.LastActivity))
+		if s.Pinned {
+			line += "  pinned"
+		}
+		if s.Group != "" {
+			line += "  @" + s.Group
+		}
+		if s.Git != nil {
+			line += fmt.Sprintf("  ~%d +%d -%d", s.Git.Dirty, s.Git.Additions, s.Git.Deletions)
+		}
+		for _, a := range s.Agents {
+			line += fmt.Sprintf("  [%s: %s]", a.Kind, a.State)
+		}
+		fmt.Println(line)
+		for _, w := range s.Windows {
+			fmt.Printf("    %d: %s\n", w.Index, w.Name)
+			for _, p := range w.Panes {
+				agentNote := ""
+				if p.Agent != "" {
+					agentNote = "  (" + p.Agent + ")"
+				}
+				fmt.Printf("      %d: %s  %s%s\n", p.Index, p.Command, p.Path, agentNote)
+			}
+		}
+	}
+	return nil
+}
+
+// collectSessionInfos builds the full tree for all sessions. Git status is
+// fetched in parallel; agent statuses go through the same liveness check
+// as the TUI.
+func collectSessionInfos(cfg config.Config, sessions []tmux.Session) []sessionInfo {
+	current := ""
+	if os.Getenv("TMUX") != "" {
+		current, _ = tmux.CurrentSession()
+	}
+
+	pins := session.LoadPins(cfg.CacheDir)
+	infos := make([]sessionInfo, len(sessions))
+	names := make([]string, len(sessions))
+	for i, s := range sessions {
+		names[i] = s.Name
+		infos[i] = sessionInfo{
+			Name:         s.Name,
+			Current:      s.Name == current,
+			LastActivity: s.LastActivity,
+			Group:        session.Group(cfg, s),
+			Pinned:       slices.Contains(pins, s.Name),
+			Agents:       []agentInfo{},
+			Windows:      []windowInfo{},
+		}
+	}
+
+	var wg sync.WaitGroup
+	const maxParallel = 8
+	sem := make(chan struct{}, maxParallel)
+	for i := range infos {
+		wg.Add(1)
+		go func(info *sessionInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			info.Path, _ = git.GetSessionPath(info.Name)
+			if cfg.GitStatusEnabled && info.Path != "" {
+				if status := git.GetStatus(info.Path); status.IsRepo {
+					info.Git = &gitInfo{Dirty: status.Dirty, Additions: status.Additions, Deletions: status.Deletions}
+				}
+			}
+		}(&infos[i])
+	}
+	wg.Wait()
+
+	// Agent statuses, with dead agents dropped like in the TUI
+	var live agent.Liveness
+	statuses := make(map[string]map[string][]agent.Status) // kind → session → instances
+	agents := agent.NewRegistry(cfg)
+	kinds := agents.Enabled()
+	if len(kinds) > 0 {
+		if panePIDs, err := tmux.PanePIDs(); err == nil {
+			live, _ = agents.CheckLiveness(panePIDs)
+		}
+		for _, kind := range kinds {
+			byKind := agent.ReadAll(kind, names, cfg.CacheDir)
+			agent.DropDead(kind, byKind, live, cfg.CacheDir)
+			statuses[kind.Name] = byKind
+		}
+	}
+
+	for i := range infos {
+		info := &infos[i]
+		for _, kind := range kinds {
+			for _, s := range statuses[kind.Name][info.Name] {
+				info.Agents = append(info.Agents, agentInfo{
+					Kind:      kind.Name,
+					State:     s.State,
+					Since:     s.Timestamp,
+					Tool:      s.Tool,
+					Cwd:       s.Cwd,
+					SessionID: s.SessionID,
+				})
+			}
+		}
+
+		windows, err := tmux.ListWindows(info.Name)
+		if err != nil {
+			continue // session vanished mid-listing
+		}
+		panesByWindow, _ := tmux.ListSessionPanes(info.Name)
+		for _, w := range windows {
+			window := windowInfo{Index: w.Index, Name: w.Name, Active: w.Active, Panes: []paneInfo{}}
+			for _, p := range panesByWindow[w.Index] {
+				window.Panes = append(window.Panes, paneInfo{
+					Index:   p.Index,
+					Command: p.Command,
+					Path:    p.Path,
+					Active:  p.Active,
+					Agent:   live.PaneAgent(p.PID),
+				})
+			}
+			info.Windows = append(info.Windows, window)
+		}
+	}
+	return infos
+}
+
+// --- switch ---
+
+func runSessionsSwitch(args []string) error {
+	pos := positionalArgs(args)
+	if len(pos) != 1 {
+		fmt.Println("Usage: helm sessions switch <session[:window]>")
+		return errSessionsUsage
+	}
+	if err := tmux.SwitchClient(pos[0]); err != nil {
+		return fmt.Errorf("failed to switch to %s: %w", pos[0], err)
+	}
+	return nil
+}
+
+// --- kill ---
+
+func runSessionsKill(args []string) error {
+	pos := positionalArgs(args)
+	if len(pos) != 1 {
+		fmt.Println("Usage: helm sessions kill <session> [--json]")
+		return errSessionsUsage
+	}
+	name := pos[0]
+
+	if !tmux.SessionExists(name) {
+		return fmt.Errorf("no session named %s", name)
+	}
+	if err := tmux.KillSession(name); err != nil {
+		return fmt.Errorf("failed to kill %s: %w", name, err)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(map[string]string{"killed": name})
+	} else {
+		fmt.Printf("  ✓ killed %s\n", name)
+	}
+	return nil
+}
+
+// --- new ---
+
+func runSessionsNew(args []string) error {
+	pos := positionalArgs(args, "--dir")
+	if len(pos) != 1 {
+		fmt.Println("Usage: helm sessions new <name> [--dir <path>] [--json]")
+		return errSessionsUsage
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	name := config.SanitizeSessionName(pos[0])
+	dir := getFlagValue(args, "--dir")
+	if dir == "" {
+		dir = cfg.DefaultSessionDir
+	}
+
+	if tmux.SessionExists(name) {
+		return fmt.Errorf("session %s already exists", name)
+	}
+	if err := tmux.CreateSession(name, dir); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	if err := layout.Apply(cfg, name, dir); err != nil {
+		printLayoutError(err)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(map[string]string{"created": name, "dir": dir})
+	} else {
+		fmt.Printf("  ✓ created %s in %s\n", name, dir)
+	}
+	return nil
+}
+
+// --- rename ---
+
+func runSessionsRename(args []string) error {
+	pos := positionalArgs(args)
+	if len(pos) != 2 {
+		fmt.Println("Usage: helm sessions rename <old> <new> [--json]")
+		return errSessionsUsage
+	}
+	oldName, newName := pos[0], config.SanitizeSessionName(pos[1])
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if !tmux.SessionExists(oldName) {
+		return fmt.Errorf("no session named %s", oldName)
+	}
+	// Also migrates agent status files, bookmarks, the pin and the session cache
+	if err := session.Rename(&cfg, oldName, newName); err != nil {
+		return err
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(map[string]string{"old": oldName, "new": newName})
+	} else {
+		fmt.Printf("  ✓ renamed %s → %s\n", oldName, newName)
+	}
+	return nil
+}
+
+// --- group ---
+
+func runSessionsGroup(args []string) error {
+	pos := positionalArgs(args)
+	if len(pos) < 1 || len(pos) > 2 {
+		fmt.Println("Usage: helm sessions group <session> [group] [--json]")
+		return errSessionsUsage
+	}
+	name, group := pos[0], ""
+	if len(pos) == 2 {
+		group = strings.TrimSpace(pos[1])
+	}
+
+	if !tmux.SessionExists(name) {
+		return fmt.Errorf("no session named %s", name)
+	}
+	if err := tmux.SetSessionGroup(name, group); err != nil {
+		return fmt.Errorf("failed to tag %s: %w", name, err)
+	}
+
+	switch {
+	case hasFlag(args, "--json"):
+		printJSON(map[string]string{"session": name, "group": group})
+	case group == "":
+		fmt.Printf("  ✓ untagged %s\n", name)
+	default:
+		fmt.Printf("  ✓ %s → @%s\n", name, group)
+	}
+	return nil
+}
+
+// --- pin ---
+
+func runSessionsPin(args []string, pinned bool) error {
+	pos := positionalArgs(args)
+	if len(pos) != 1 {
+		if pinned {
+			fmt.Println("Usage: helm sessions pin <session> [--json]")
+		} else {
+			fmt.Println("Usage: helm sessions unpin <session> [--json]")
+		}
+		return errSessionsUsage
+	}
+	name := pos[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Unpinning works on pins of sessions that are gone, too
+	if pinned && !tmux.SessionExists(name) {
+		return fmt.Errorf("no session named %s", name)
+	}
+	if err := session.SetPinned(cfg.CacheDir, name, pinned); err != nil {
+		return fmt.Errorf("failed to save pins: %w", err)
+	}
+
+	switch {
+	case hasFlag(args, "--json"):
+		printJSON(map[string]any{"session": name, "pinned": pinned})
+	case pinned:
+		fmt.Printf("  ✓ pinned %s\n", name)
+	default:
+		fmt.Printf("  ✓ unpinned %s\n", name)
+	}
+	return nil
+}
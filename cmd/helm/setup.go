@@ -43,12 +43,12 @@ func runSetup() error {
 	fmt.Printf("Clone target: %s\n", cloneDir)
 
 	// Expand all entries to concrete URLs
-	urls, postCloneMap, err := expandEntries(cfg.EnsureCloned, cfg.GitProviders)
+	repos, postCloneMap, err := expandEntries(cfg.EnsureCloned, cfg.GitProviders)
 	if err != nil {
 		return err
 	}
 
-	if len(urls) == 0 {
+	if len(repos) == 0 {
 		fmt.Println("No repositories to clone after expansion.")
 		return nil
 	}
@@ -60,7 +60,7 @@ func runSetup() error {
 		clonedSet[r] = true
 	}
 
-	fmt.Printf("Found %d repositories to process\n\n", len(urls))
+	fmt.Printf("Found %d repositories to process\n\n", len(repos))
 
 	// Clone in parallel (max 4 concurrent)
 	const maxParallel = 4
@@ -69,11 +69,11 @@ func runSetup() error {
 	var results []setupResult
 
 	var wg sync.WaitGroup
-	for _, url := range urls {
-		ownerRepo, err := giturl.ResolveOwnerRepo(url, cfg.GitProviders)
+	for _, entry := range repos {
+		ownerRepo, err := giturl.ResolveOwnerRepo(entry.URL, cfg.GitProviders)
 		if err != nil {
 			mu.Lock()
-			results = append(results, setupResult{repo: url, status: "failed", err: fmt.Errorf("could not parse URL")})
+			results = append(results, setupResult{repo: entry.URL, status: "failed", err: fmt.Errorf("could not parse URL")})
 			mu.Unlock()
 			continue
 		}
@@ -86,7 +86,7 @@ func runSetup() error {
 		}
 
 		wg.Add(1)
-		go func(gitURL, repo string) {
+		go func(entry config.EnsureClonedEntry, repo string) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
@@ -94,7 +94,7 @@ func runSetup() error {
 			destPath := filepath.Join(cloneDir, repo)
 			result := setupResult{repo: repo}
 
-			if err := giturl.CloneRepo(gitURL, destPath); err != nil {
+			if err := giturl.CloneRepo(entry.URL, destPath, cloneOptions(entry)); err != nil {
 				result.status = "failed"
 				result.err = err
 			} else {
@@ -104,7 +104,7 @@ func runSetup() error {
 			mu.Lock()
 			results = append(results, result)
 			mu.Unlock()
-		}(url, ownerRepo)
+		}(entry, ownerRepo)
 	}
 	wg.Wait()
 
@@ -175,9 +175,10 @@ func resolveCloneDir(dirs []string) (string, error) {
 }
 
 // expandEntries resolves all ensure_cloned entries to concrete git URLs.
-// Returns the URL list and a map of owner/repo -> post_clone command.
-func expandEntries(entries []config.EnsureClonedEntry, providers map[string]string) ([]string, map[string]string, error) {
-	var urls []string
+// Returns one entry per repo, with the clone options of the entry it came
+// from, and a map of owner/repo -> post_clone command.
+func expandEntries(entries []config.EnsureClonedEntry, providers map[string]string) ([]config.EnsureClonedEntry, map[string]string, error) {
+	var repos []config.EnsureClonedEntry
 	postCloneMap := make(map[string]string)
 
 	for _, entry := range entries {
@@ -193,11 +194,16 @@ func expandEntries(entries []config.EnsureClonedEntry, providers map[string]stri
 				fmt.Printf("  ⚠ Failed to expand %s: %v\n", url, err)
 				continue
 			}
-			urls = append(urls, expanded...)
+			for _, u := range expanded {
+				repo := entry
+				repo.URL = u
+				repo.PostClone = ""
+				repos = append(repos, repo)
+			}
 			continue
 		}
 
-		urls = append(urls, url)
+		repos = append(repos, entry)
 
 		// Track post_clone command by resolved directory name
 		if entry.PostClone != "" {
@@ -207,7 +213,19 @@ func expandEntries(entries []config.EnsureClonedEntry, providers map[string]stri
 		}
 	}
 
-	return urls, postCloneMap, nil
+	return repos, postCloneMap, nil
+}
+
+// cloneOptions returns the clone options of an ensure_cloned entry.
+func cloneOptions(e config.EnsureClonedEntry) giturl.CloneOptions {
+	return giturl.CloneOptions{
+		Depth:             e.Depth,
+		Filter:            e.Filter,
+		Branch:            e.Branch,
+		RecurseSubmodules: e.RecurseSubmodules,
+		Sparse:            len(e.Sparse) > 0,
+		SparsePaths:       e.Sparse,
+	}
 }
 
 // isWildcard checks if a URL contains an org/* wildcard pattern
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/snapshot"
+)
+
+func runSnapshot(args []string) error {
+	if len(args) == 0 {
+		printSnapshotUsage()
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	path := getFlagValue(args, "--file")
+	if path == "" {
+		path = snapshot.Path(cfg.CacheDir)
+	}
+
+	switch args[0] {
+	case "save":
+		return runSnapshotSave(path)
+	case "restore":
+		return runSnapshotRestore(path, cfg.Snapshot.RestoreCommands)
+	default:
+		fmt.Printf("Unknown snapshot command: %s\n", args[0])
+		printSnapshotUsage()
+		return nil
+	}
+}
+
+func printSnapshotUsage() {
+	fmt.Println("Usage: helm snapshot <command> [--file <path>]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  save                           Save all sessions, windows and panes")
+	fmt.Println("  restore                        Recreate saved sessions that aren't running")
+}
+
+// --- save ---
+
+func runSnapshotSave(path string) error {
+	snap, err := snapshot.Capture()
+	if err != nil {
+		return err
+	}
+	if err := snapshot.Save(snap, path); err != nil {
+		return err
+	}
+
+	windows := 0
+	for _, s := range snap.Sessions {
+		windows += len(s.Windows)
+	}
+	fmt.Printf("Saved %d sessions (%d windows) to %s\n", len(snap.Sessions), windows, path)
+	return nil
+}
+
+// --- restore ---
+
+func runSnapshotRestore(path string, restoreCommands []string) error {
+	snap, err := snapshot.Load(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no snapshot at %s (run 'helm snapshot save' first)", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	var restoredCount, skippedCount, failedCount int
+	for _, r := range snapshot.Restore(snap, restoreCommands) {
+		switch r.Status {
+		case "restored":
+			fmt.Printf("  ✓ %s\n", r.Session)
+			restoredCount++
+		case "skipped":
+			skippedCount++
+		case "failed":
+			fmt.Printf("  ✗ %s: %v\n", r.Session, r.Err)
+			failedCount++
+		}
+	}
+
+	fmt.Printf("\nDone: %d restored, %d skipped, %d failed (snapshot from %s)\n",
+		restoredCount, skippedCount, failedCount, snap.SavedAt.Format("2006-01-02 15:04"))
+	return nil
+}
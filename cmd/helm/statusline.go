@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/daemon"
+	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/statusline"
+	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+func runStatusline(args []string) error {
+	if hasFlag(args, "--help") || hasFlag(args, "-h") {
+		printStatuslineUsage()
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	ui.InitColors(string(cfg.Appearance), cfg.Theme)
+
+	session := getFlagValue(args, "--session")
+	if session == "" && os.Getenv("TMUX") != "" {
+		session, _ = tmux.CurrentSession()
+	}
+
+	path := statusline.Path(cfg.CacheDir)
+	now := time.Now()
+	summary, fresh := statusline.Load(path, statusline.MaxAge, now)
+	if !fresh || hasFlag(args, "--refresh") {
+		summary = collectStatusline(cfg)
+		// Errors go to stderr: stdout ends up in the status bar
+		if err := statusline.Save(summary, path); err != nil {
+			fmt.Fprintf(os.Stderr, "helm statusline: %v\n", err)
+		}
+	}
+
+	fmt.Println(statusline.Render(summary, session, now))
+	return nil
+}
+
+func printStatuslineUsage() {
+	fmt.Println("Usage: helm statusline [--session <name>] [--refresh]")
+	fmt.Println()
+	fmt.Println("Prints agent and git state for the tmux status bar:")
+	fmt.Println()
+	fmt.Println("  set -g status-right '#(helm statusline --session \"#S\")'")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --session <name>   Session whose git state is shown (default: current)")
+	fmt.Println("  --refresh          Ignore the cache and collect now")
+}
+
+// collectStatusline gathers agent statuses and the git state of every
+// session — from `helm daemon` when it runs, otherwise directly, with git
+// status fetched in parallel like in `helm sessions list`.
+func collectStatusline(cfg config.Config) statusline.Summary {
+	if snap, err := daemon.State(daemon.SocketPath(cfg.CacheDir)); err == nil {
+		return summaryFromDaemon(snap)
+	}
+
+	summary := statusline.Summary{UpdatedAt: time.Now(), Git: map[string]statusline.Git{}}
+
+	for _, sessions := range pollAgentStatuses(cfg, agent.NewRegistry(cfg)) {
+		for name, instances := range sessions {
+			for _, s := range instances {
+				summary.Agents = append(summary.Agents, statusline.Agent{Session: name, State: s.State, Since: s.Timestamp})
+			}
+		}
+	}
+
+	if !cfg.GitStatusEnabled {
+		return summary
+	}
+	sessions, err := tmux.ListSessions("")
+	if err != nil {
+		return summary
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	const maxParallel = 8
+	sem := make(chan struct{}, maxParallel)
+	for _, s := range sessions {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			path, err := git.GetSessionPath(name)
+			if err != nil || path == "" {
+				return
+			}
+			status := git.GetStatus(path)
+			if !status.IsRepo {
+				return
+			}
+			mu.Lock()
+			summary.Git[name] = statusline.Git{Branch: status.Branch, Dirty: status.Dirty, Additions: status.Additions, Deletions: status.Deletions}
+			mu.Unlock()
+		}(s.Name)
+	}
+	wg.Wait()
+	return summary
+}
+
+// summaryFromDaemon converts a daemon snapshot to a statusline summary.
+func summaryFromDaemon(snap daemon.Snapshot) statusline.Summary {
+	summary := statusline.Summary{UpdatedAt: time.Now(), Git: map[string]statusline.Git{}}
+	for _, s := range snap.Sessions {
+		for _, a := range s.Agents {
+			summary.Agents = append(summary.Agents, statusline.Agent{Session: s.Name, State: a.State, Since: a.Since})
+		}
+		if s.Git != nil {
+			summary.Git[s.Name] = statusline.Git{Branch: s.Git.Branch, Dirty: s.Git.Dirty, Additions: s.Git.Additions, Deletions: s.Git.Deletions}
+		}
+	}
+	return summary
+}
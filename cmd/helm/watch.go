@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/watch"
+)
+
+func runWatch(args []string) error {
+	if hasFlag(args, "--help") || hasFlag(args, "-h") {
+		printWatchUsage()
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	interval := cfg.Notifications.PollInterval
+	if v := getFlagValue(args, "--interval"); v != "" {
+		if interval, err = time.ParseDuration(v); err != nil {
+			return fmt.Errorf("invalid --interval %q: %w", v, err)
+		}
+	}
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	agents := agent.NewRegistry(cfg)
+	if len(agents.Enabled()) == 0 {
+		fmt.Fprintln(os.Stderr, "helm watch: no agent status tracking enabled (claude_status_enabled / pi_status_enabled / agents)")
+	}
+
+	tracker := watch.NewTracker(cfg.Notifications.WaitingThreshold)
+	poll := func() {
+		now := time.Now()
+		for _, e := range tracker.Update(now, pollAgentStatuses(cfg, agents)) {
+			if err := watch.Notify(cfg.Notifications, e, now); err != nil {
+				fmt.Fprintf(os.Stderr, "helm watch: %v\n", err)
+			}
+		}
+	}
+
+	poll()
+	if hasFlag(args, "--once") {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+func printWatchUsage() {
+	fmt.Println("Usage: helm watch [--interval <duration>] [--once]")
+	fmt.Println()
+	fmt.Println("Watches agent status and notifies when an agent starts waiting")
+	fmt.Println("for input or has been waiting longer than notifications.waiting_threshold.")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --interval <duration>   Poll interval (default: notifications.poll_interval)")
+	fmt.Println("  --once                  Poll once and exit")
+}
+
+// pollAgentStatuses reads the agent statuses of all sessions, with dead
+// agents dropped like in the TUI. Returns kind → session → instances.
+func pollAgentStatuses(cfg config.Config, agents agent.Registry) map[string]map[string][]agent.Status {
+	kinds := agents.Enabled()
+	statuses := make(map[string]map[string][]agent.Status)
+	if len(kinds) == 0 {
+		return statuses
+	}
+
+	sessions, err := tmux.ListSessions("")
+	if err != nil {
+		return statuses // tmux server not running
+	}
+	names := make([]string, len(sessions))
+	for i, s := range sessions {
+		names[i] = s.Name
+	}
+
+	var live agent.Liveness
+	if panePIDs, err := tmux.PanePIDs(); err == nil {
+		live, _ = agents.CheckLiveness(panePIDs)
+	}
+	for _, kind := range kinds {
+		byKind := agent.ReadAll(kind, names, cfg.CacheDir)
+		agent.DropDead(kind, byKind, live, cfg.CacheDir)
+		statuses[kind.Name] = byKind
+	}
+	return statuses
+}
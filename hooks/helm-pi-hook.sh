@@ -15,18 +15,31 @@ TMUX_SESSION=$(tmux display-message -p '#{session_name}' 2>/dev/null)
 STATUS_FILE="$STATUS_DIR/${TMUX_SESSION}.pi-status"
 TIMESTAMP=$(date +%s)
 
-# Event from Pi extension
+# Event from Pi extension, plus the session file and cwd when available
 EVENT="$1"
+TRANSCRIPT="$2"
+AGENT_CWD="$3"
+
+# write_status <state> — JSON status with the transcript path for helm's
+# preview. Falls back to the legacy "state:timestamp" format without jq.
+write_status() {
+    local state="$1"
+    jq -nc --arg state "$state" --argjson ts "$TIMESTAMP" \
+        --arg transcript "$TRANSCRIPT" --arg cwd "$AGENT_CWD" \
+        '{state: $state, ts: $ts, transcript: $transcript, cwd: $cwd}' \
+        >"$STATUS_FILE" 2>/dev/null ||
+        echo "$state:$TIMESTAMP" >"$STATUS_FILE"
+}
 
 case "$EVENT" in
     "start")
-        echo "new:$TIMESTAMP" > "$STATUS_FILE"
+        write_status "new"
         ;;
     "working")
-        echo "working:$TIMESTAMP" > "$STATUS_FILE"
+        write_status "working"
         ;;
     "waiting")
-        echo "waiting:$TIMESTAMP" > "$STATUS_FILE"
+        write_status "waiting"
         ;;
     "end")
         rm -f "$STATUS_FILE"
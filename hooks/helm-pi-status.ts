@@ -2,7 +2,8 @@
  * Helm Pi Status Extension
  *
  * Writes Pi status to ~/.cache/helm/<session>.pi-status
- * so helm can display it in the session list.
+ * so helm can display it in the session list. The session file and cwd
+ * are passed along so helm can preview the transcript in its AGENTS panel.
  *
  * Events: session_start, agent_start, agent_end, session_shutdown
  *
@@ -22,32 +23,36 @@ import type { ExtensionAPI } from "@mariozechner/pi-coding-agent";
 export default function (pi: ExtensionAPI) {
   const hookScript = `${process.env.HOME}/.local/bin/helm-pi-hook.sh`;
 
-  function callHook(event: string) {
+  function callHook(event: string, ctx?: any) {
     try {
-      const { execSync } = require("child_process");
-      execSync(`"${hookScript}" ${event}`, { stdio: "ignore" });
+      const { execFileSync } = require("child_process");
+      // Older Pi versions don't expose the session file; the hook then
+      // writes the status without a transcript
+      const transcript = ctx?.sessionManager?.getSessionFile?.() ?? "";
+      const cwd = ctx?.cwd ?? process.cwd();
+      execFileSync(hookScript, [event, transcript, cwd], { stdio: "ignore" });
     } catch {
       // Non-fatal - hook script may not exist
     }
   }
 
   // Session starts - new agent context
-  pi.on("session_start", async () => {
-    callHook("start");
+  pi.on("session_start", async (_event, ctx) => {
+    callHook("start", ctx);
   });
 
   // Agent begins processing - working state
-  pi.on("agent_start", async () => {
-    callHook("working");
+  pi.on("agent_start", async (_event, ctx) => {
+    callHook("working", ctx);
   });
 
   // Agent finishes (idle, waiting for user)
-  pi.on("agent_end", async () => {
-    callHook("waiting");
+  pi.on("agent_end", async (_event, ctx) => {
+    callHook("waiting", ctx);
   });
 
   // Session ends - clean up status file
-  pi.on("session_shutdown", async () => {
-    callHook("end");
+  pi.on("session_shutdown", async (_event, ctx) => {
+    callHook("end", ctx);
   });
 }
\ No newline at end of file
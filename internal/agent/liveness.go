@@ -40,15 +40,15 @@ type process struct {
 }
 
 // CheckLiveness takes one snapshot of the process table and reports, for
-// each agent kind, the sessions with a matching process running beneath any
-// of their pane PIDs. Status hooks don't fire on crash or SIGKILL, so this
+// each known agent kind, the sessions with a matching process running
+// beneath any of their pane PIDs. Status hooks don't fire on crash or SIGKILL, so this
 // is the ground truth behind a "working" status file.
-func CheckLiveness(panePIDs map[string][]int) (Liveness, error) {
+func (r Registry) CheckLiveness(panePIDs map[string][]int) (Liveness, error) {
 	procs, err := processSnapshot()
 	if err != nil {
 		return Liveness{}, err
 	}
-	return liveness(panePIDs, procs), nil
+	return liveness(r.kinds, panePIDs, procs), nil
 }
 
 // processSnapshot reads the full process table in one ps call.
@@ -77,7 +77,7 @@ func processSnapshot() ([]process, error) {
 // liveness walks the process tree beneath each pane's shell PID and matches
 // descendants against each kind's binary names. Each pane is walked
 // separately so an agent can be attributed to the exact pane it runs in.
-func liveness(panePIDs map[string][]int, procs []process) Liveness {
+func liveness(kinds []Kind, panePIDs map[string][]int, procs []process) Liveness {
 	children := make(map[int][]int, len(procs))
 	commands := make(map[int]string, len(procs))
 	for _, p := range procs {
@@ -86,10 +86,10 @@ func liveness(panePIDs map[string][]int, procs []process) Liveness {
 	}
 
 	result := Liveness{
-		sessions: make(map[string]map[string]bool, len(Kinds)),
+		sessions: make(map[string]map[string]bool, len(kinds)),
 		panes:    make(map[int]string),
 	}
-	for _, kind := range Kinds {
+	for _, kind := range kinds {
 		result.sessions[kind.Name] = make(map[string]bool)
 	}
 
@@ -107,7 +107,7 @@ func liveness(panePIDs map[string][]int, procs []process) Liveness {
 				seen[pid] = true
 				queue = append(queue, children[pid]...)
 
-				for _, kind := range Kinds {
+				for _, kind := range kinds {
 					if commandMatches(commands[pid], kind.BinaryNames) {
 						result.sessions[kind.Name][session] = true
 						if _, taken := result.panes[panePID]; !taken {
@@ -28,7 +28,7 @@ func TestLiveness(t *testing.T) {
 		"pi-sess":  {130},
 	}
 
-	live := liveness(panePIDs, procs)
+	live := liveness([]Kind{Claude, Pi}, panePIDs, procs)
 
 	tests := []struct {
 		kind    Kind
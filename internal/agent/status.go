@@ -1,6 +1,7 @@
-// Package agent tracks per-session status of LLM agent clients
-// (Claude Code, Pi). Each client writes "state:timestamp" status files
-// into the cache dir via its hook script; the TUI reads them here.
+// Package agent tracks per-session status of LLM agent clients (Claude
+// Code, Pi, and any declared in the config). Each client writes
+// "state:timestamp" status files into the cache dir via its hook script;
+// the TUI reads them here.
 package agent
 
 import (
@@ -23,38 +24,108 @@ const StaleThreshold = 2 * time.Minute
 // Safety net — the TUI handles visual progression (? → ! → Z) before this kicks in.
 const WaitingStaleThreshold = 30 * time.Minute
 
+// WriteDebounce is how long watchers of the cache dir wait after a status
+// file event before re-reading: one hook write produces a burst of events
+// (create, write, rename).
+const WriteDebounce = 100 * time.Millisecond
+
 // Kind describes one supported agent client.
 type Kind struct {
 	Name        string   // display name, e.g. "claude"
 	FileExt     string   // status-file extension in the cache dir
 	BinaryNames []string // process names used for liveness checks
+	Header      string   // 2-cell status column header, e.g. "CC"
+	Glyph       string   // 1-cell icon while working; "" uses the spinner
+	Color       string   // header and working icon color (hex or ANSI index); "" uses the theme's
+
+	longerExts []string // other kinds' extensions ending in FileExt, see ownsFile
 }
 
 var (
 	// Claude is the Claude Code client.
-	Claude = Kind{Name: "claude", FileExt: config.StatusFileExt, BinaryNames: []string{"claude"}}
+	Claude = Kind{Name: "claude", FileExt: config.StatusFileExt, BinaryNames: []string{"claude"}, Header: "CC"}
 	// Pi is the Pi client.
-	Pi = Kind{Name: "pi", FileExt: config.PiStatusFileExt, BinaryNames: []string{"pi"}}
-
-	// Kinds lists all supported agent clients.
-	Kinds = []Kind{Claude, Pi}
+	Pi = Kind{Name: "pi", FileExt: config.PiStatusFileExt, BinaryNames: []string{"pi"}, Header: "Pi"}
 )
 
+// Registry is the set of agent clients helm knows for one config: the
+// built-in ones, followed by those declared in the config. Status files
+// and processes are told apart against the whole set, tracked or not.
+type Registry struct {
+	kinds   []Kind
+	enabled []Kind
+}
+
+// NewRegistry returns the agent clients known with cfg.
+func NewRegistry(cfg config.Config) Registry {
+	kinds := []Kind{Claude, Pi}
+	for _, a := range cfg.Agents {
+		kinds = append(kinds, Kind{Name: a.Name, FileExt: a.FileExt, BinaryNames: a.Binaries, Header: a.Header, Glyph: a.Glyph, Color: a.Color})
+	}
+	for i := range kinds {
+		for _, other := range kinds {
+			if len(other.FileExt) > len(kinds[i].FileExt) && strings.HasSuffix(other.FileExt, kinds[i].FileExt) {
+				kinds[i].longerExts = append(kinds[i].longerExts, other.FileExt)
+			}
+		}
+	}
+
+	r := Registry{kinds: kinds}
+	for _, kind := range kinds {
+		switch kind.Name {
+		case Claude.Name:
+			if !cfg.ClaudeStatusEnabled {
+				continue
+			}
+		case Pi.Name:
+			if !cfg.PiStatusEnabled {
+				continue
+			}
+		}
+		r.enabled = append(r.enabled, kind)
+	}
+	return r
+}
+
+// Kinds returns all known kinds: the built-in ones, then the declared ones.
+func (r Registry) Kinds() []Kind {
+	return r.kinds
+}
+
+// Enabled returns the kinds with status tracking enabled: Claude and Pi
+// per their config switches, declared agents always.
+func (r Registry) Enabled() []Kind {
+	return r.enabled
+}
+
 // ownsFile reports whether a status file belongs to this kind. A plain
-// suffix check is not enough: ".pi-status" also ends in ".status", so the
-// longest matching extension across all kinds wins.
+// suffix check is not enough when another kind's extension ends in this
+// one's, so the longest matching extension wins. Only kinds from a
+// Registry know the other kinds' extensions.
 func (k Kind) ownsFile(name string) bool {
 	if !strings.HasSuffix(name, k.FileExt) {
 		return false
 	}
-	for _, other := range Kinds {
-		if len(other.FileExt) > len(k.FileExt) && strings.HasSuffix(name, other.FileExt) {
+	for _, ext := range k.longerExts {
+		if strings.HasSuffix(name, ext) {
 			return false
 		}
 	}
 	return true
 }
 
+// IsStatusFile reports whether a path in the cache dir is a status file of
+// any known kind.
+func (r Registry) IsStatusFile(path string) bool {
+	name := filepath.Base(path)
+	for _, kind := range r.kinds {
+		if kind.ownsFile(name) {
+			return true
+		}
+	}
+	return false
+}
+
 // Status represents an agent's status for a session
 type Status struct {
 	State     string    // "new", "working", "waiting", or ""
@@ -115,6 +186,29 @@ func GetStatuses(kind Kind, sessionName, cacheDir string) []Status {
 	return statuses
 }
 
+// ReadAll reads the status instances of one kind for every given session.
+// Sessions without a live status are omitted from the result.
+func ReadAll(kind Kind, sessionNames []string, cacheDir string) map[string][]Status {
+	statuses := make(map[string][]Status)
+	for _, name := range sessionNames {
+		if instances := GetStatuses(kind, name, cacheDir); len(instances) > 0 {
+			statuses[name] = instances
+		}
+	}
+	return statuses
+}
+
+// DropDead removes statuses (and their files) for sessions where no
+// matching agent process is running.
+func DropDead(kind Kind, statuses map[string][]Status, live Liveness, cacheDir string) {
+	for name := range statuses {
+		if !live.Alive(kind, name) {
+			delete(statuses, name)
+			RemoveStatuses(kind, name, cacheDir)
+		}
+	}
+}
+
 // statePriority orders states by how "active" they are.
 func statePriority(state string) int {
 	switch state {
@@ -211,6 +305,32 @@ func RemoveStatuses(kind Kind, sessionName, cacheDir string) {
 	}
 }
 
+// RenameStatuses moves a session's status files for a kind — legacy and
+// per-instance — to a new session name, so agent indicators survive a
+// session rename. Hooks write under the new name from then on.
+func RenameStatuses(kind Kind, oldName, newName, cacheDir string) error {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	legacy := oldName + kind.FileExt
+	prefix := oldName + "."
+	for _, entry := range entries {
+		name := entry.Name()
+		if !kind.ownsFile(name) || (name != legacy && !strings.HasPrefix(name, prefix)) {
+			continue
+		}
+		renamed := newName + strings.TrimPrefix(name, oldName)
+		if err := os.Rename(filepath.Join(cacheDir, name), filepath.Join(cacheDir, renamed)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CleanupStale removes status files for sessions that no longer exist
 func CleanupStale(kind Kind, cacheDir string, activeSessions []string) {
 	entries, err := os.ReadDir(cacheDir)
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/black-atom-industries/helm/internal/config"
 )
 
 func TestGetStatus(t *testing.T) {
@@ -290,6 +292,62 @@ func TestCleanupStaleDoesNotCrossKinds(t *testing.T) {
 	}
 }
 
+func TestReadAllAndDropDead(t *testing.T) {
+	tmpDir := t.TempDir()
+	ts := fmt.Sprintf("%d", time.Now().Unix())
+	writeFile(t, tmpDir, "alive.status", "working:"+ts)
+	writeFile(t, tmpDir, "dead.status", "waiting:"+ts)
+
+	statuses := ReadAll(Claude, []string{"alive", "dead", "idle"}, tmpDir)
+	if len(statuses) != 2 {
+		t.Fatalf("ReadAll() returned %d sessions, want 2 (idle has no status)", len(statuses))
+	}
+
+	live := Liveness{sessions: map[string]map[string]bool{"claude": {"alive": true}}}
+	DropDead(Claude, statuses, live, tmpDir)
+
+	if _, ok := statuses["dead"]; ok {
+		t.Error("dead session should be dropped")
+	}
+	if _, ok := statuses["alive"]; !ok {
+		t.Error("alive session should be kept")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "dead.status")); !os.IsNotExist(err) {
+		t.Error("dead.status should be deleted")
+	}
+}
+
+func TestRenameStatuses(t *testing.T) {
+	tmpDir := t.TempDir()
+	ts := fmt.Sprintf("%d", time.Now().Unix())
+	writeFile(t, tmpDir, "old.status", "working:"+ts)
+	writeFile(t, tmpDir, "old.uuid-1.status", "waiting:"+ts)
+	writeFile(t, tmpDir, "old.pi-status", "working:"+ts)
+	writeFile(t, tmpDir, "older.status", "working:"+ts)
+
+	for _, kind := range NewRegistry(config.Config{}).Kinds() {
+		if err := RenameStatuses(kind, "old", "new", tmpDir); err != nil {
+			t.Fatalf("RenameStatuses(%s) error = %v", kind.Name, err)
+		}
+	}
+
+	for _, name := range []string{"new.status", "new.uuid-1.status", "new.pi-status", "older.status"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, name)); err != nil {
+			t.Errorf("%s should exist: %v", name, err)
+		}
+	}
+	if got := len(GetStatuses(Claude, "new", tmpDir)); got != 2 {
+		t.Errorf("GetStatuses(new) = %d instances, want 2", got)
+	}
+	if got := len(GetStatuses(Claude, "old", tmpDir)); got != 0 {
+		t.Errorf("GetStatuses(old) = %d instances, want 0", got)
+	}
+	// A missing cache dir is not an error
+	if err := RenameStatuses(Claude, "a", "b", filepath.Join(tmpDir, "missing")); err != nil {
+		t.Errorf("RenameStatuses() on missing dir error = %v", err)
+	}
+}
+
 // primaryStatus returns the most-active status instance, or zero Status.
 func primaryStatus(kind Kind, sessionName, cacheDir string) Status {
 	statuses := GetStatuses(kind, sessionName, cacheDir)
@@ -305,3 +363,66 @@ func writeFile(t *testing.T, dir, name, content string) {
 		t.Fatalf("Failed to write %s: %v", name, err)
 	}
 }
+
+func TestNewRegistry(t *testing.T) {
+	cfg := config.Config{
+		PiStatusEnabled: true,
+		Agents: []config.AgentConfig{
+			{Name: "codex", FileExt: ".codex-status", Binaries: []string{"codex"}, Header: "Co"},
+			{Name: "aider", FileExt: ".aider.status", Binaries: []string{"aider"}, Header: "Ai"},
+		},
+	}
+	r := NewRegistry(cfg)
+
+	kinds := r.Kinds()
+	if len(kinds) != 4 || kinds[2].Name != "codex" || kinds[2].Header != "Co" {
+		t.Fatalf("Kinds() = %+v", kinds)
+	}
+	enabled := r.Enabled()
+	if len(enabled) != 3 || enabled[0].Name != "pi" || enabled[1].Name != "codex" {
+		t.Errorf("Enabled() = %+v, want pi, codex and aider", enabled)
+	}
+
+	// Custom kinds read their own files and show up in liveness
+	tmpDir := t.TempDir()
+	ts := fmt.Sprintf("%d", time.Now().Unix())
+	writeFile(t, tmpDir, "sess.codex-status", "waiting:"+ts)
+	writeFile(t, tmpDir, "sess.status", "working:"+ts)
+	if got := primaryStatus(kinds[2], "sess", tmpDir).State; got != "waiting" {
+		t.Errorf("codex state = %q, want %q", got, "waiting")
+	}
+
+	// ".aider.status" ends in Claude's ".status": the longer one wins
+	writeFile(t, tmpDir, "other.aider.status", "waiting:"+ts)
+	if got := len(GetStatuses(kinds[0], "other", tmpDir)); got != 0 {
+		t.Errorf("claude claims %d aider statuses, want 0", got)
+	}
+	if got := primaryStatus(kinds[3], "other", tmpDir).State; got != "waiting" {
+		t.Errorf("aider state = %q, want %q", got, "waiting")
+	}
+
+	live := liveness(kinds, map[string][]int{"sess": {10}}, []process{
+		{pid: 10, ppid: 1, command: "-zsh"},
+		{pid: 11, ppid: 10, command: "node /opt/bin/codex"},
+	})
+	if !live.Alive(kinds[2], "sess") || live.PaneAgent(10) != "codex" {
+		t.Error("codex process not attributed to its pane")
+	}
+}
+
+func TestIsStatusFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/cache/api" + config.StatusFileExt, true},
+		{"/cache/api.abc" + config.PiStatusFileExt, true},
+		{"/cache/helm.sock", false},
+		{"/cache/snapshot.json", false},
+	}
+	for _, tt := range tests {
+		if got := NewRegistry(config.Config{}).IsStatusFile(tt.path); got != tt.want {
+			t.Errorf("IsStatusFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxTranscriptLine caps a single JSONL entry; larger entries (huge tool
+// results) are skipped rather than aborting the read.
+const maxTranscriptLine = 16 << 20
+
+// Transcript summarizes an agent session transcript for the preview: the
+// latest exchange plus running counts.
+type Transcript struct {
+	LastPrompt string // Most recent user prompt
+	LastReply  string // Text of the most recent assistant message
+	Turns      int    // User prompts so far
+	Context    int    // Input tokens of the latest request, cache included
+	Output     int    // Output tokens across all replies
+
+	offset int64  // Bytes consumed, so appends are read incrementally
+	lastID string // Message id of the last counted reply
+}
+
+// transcriptEntry is one JSONL line. Claude Code writes {"type":"user" |
+// "assistant", "message":{...}}; Pi writes {"type":"message",
+// "message":{"role":...}}. The usage fields of both are merged.
+type transcriptEntry struct {
+	Type        string `json:"type"`
+	IsMeta      bool   `json:"isMeta"`
+	IsSidechain bool   `json:"isSidechain"`
+	Message     struct {
+		ID      string          `json:"id"`
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+		Usage   *struct {
+			InputTokens         int `json:"input_tokens"`
+			OutputTokens        int `json:"output_tokens"`
+			CacheReadTokens     int `json:"cache_read_input_tokens"`
+			CacheCreationTokens int `json:"cache_creation_input_tokens"`
+			Input               int `json:"input"`
+			Output              int `json:"output"`
+			CacheRead           int `json:"cacheRead"`
+			CacheWrite          int `json:"cacheWrite"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// ReadTranscript parses the transcript at path. Transcripts only ever grow,
+// so when prev came from an earlier read of the same file only the appended
+// lines are parsed; a file that shrank is read from the start.
+func ReadTranscript(path string, prev Transcript) (Transcript, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Transcript{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return Transcript{}, err
+	}
+	t := prev
+	if info.Size() < t.offset {
+		t = Transcript{}
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return Transcript{}, err
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		line, n, err := readLine(r)
+		if err != nil {
+			// A line without newline is still being written; pick it up
+			// on the next read
+			break
+		}
+		t.offset += int64(n)
+		t.add(line)
+	}
+	return t, nil
+}
+
+// readLine returns the next newline-terminated line and its size in bytes.
+// Lines longer than maxTranscriptLine come back empty.
+func readLine(r *bufio.Reader) ([]byte, int, error) {
+	var line []byte
+	size := 0
+	for {
+		chunk, err := r.ReadSlice('\n')
+		size += len(chunk)
+		if size <= maxTranscriptLine {
+			line = append(line, chunk...)
+		} else {
+			line = nil
+		}
+		switch err {
+		case nil:
+			return line, size, nil
+		case bufio.ErrBufferFull:
+			continue
+		default:
+			return nil, 0, err
+		}
+	}
+}
+
+// add folds one JSONL line into the summary.
+func (t *Transcript) add(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return
+	}
+	var e transcriptEntry
+	if err := json.Unmarshal(line, &e); err != nil || e.IsMeta || e.IsSidechain {
+		return
+	}
+
+	role := e.Message.Role
+	if role == "" && (e.Type == "user" || e.Type == "assistant") {
+		role = e.Type
+	}
+
+	switch role {
+	case "user":
+		text, ok := messageText(e.Message.Content)
+		if !ok || isCommandOutput(text) {
+			return // tool results and slash-command plumbing
+		}
+		t.LastPrompt = text
+		t.Turns++
+	case "assistant":
+		if text, ok := messageText(e.Message.Content); ok {
+			t.LastReply = text
+		}
+		// Claude Code writes one line per content block, each repeating the
+		// message's usage — count every message once
+		if u := e.Message.Usage; u != nil && (e.Message.ID == "" || e.Message.ID != t.lastID) {
+			t.lastID = e.Message.ID
+			t.Context = u.InputTokens + u.CacheReadTokens + u.CacheCreationTokens +
+				u.Input + u.CacheRead + u.CacheWrite
+			t.Output += u.OutputTokens + u.Output
+		}
+	}
+}
+
+// messageText extracts the text of a message's content, either a plain
+// string or an array of blocks of which only text blocks count. Reports
+// false when there is no text (e.g. only tool calls or tool results).
+func messageText(raw json.RawMessage) (string, bool) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		s = strings.TrimSpace(s)
+		return s, s != ""
+	}
+
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return "", false
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" && strings.TrimSpace(b.Text) != "" {
+			parts = append(parts, strings.TrimSpace(b.Text))
+		}
+	}
+	return strings.Join(parts, "\n"), len(parts) > 0
+}
+
+// isCommandOutput reports whether a user entry is Claude Code's record of
+// a slash command rather than a typed prompt.
+func isCommandOutput(text string) bool {
+	for _, prefix := range []string{"<command-", "<local-command-"} {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
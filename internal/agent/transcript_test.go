@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadTranscript(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  Transcript
+	}{
+		{
+			name: "claude code",
+			lines: []string{
+				`{"type":"summary","summary":"Fix tests"}`,
+				`{"type":"user","isMeta":true,"message":{"role":"user","content":"Caveat: local commands"}}`,
+				`{"type":"user","message":{"role":"user","content":"<command-name>/clear</command-name>"}}`,
+				`{"type":"user","message":{"role":"user","content":"fix the tests"}}`,
+				`{"type":"assistant","message":{"id":"msg_1","role":"assistant","content":[{"type":"text","text":"Running them."}],"usage":{"input_tokens":10,"cache_read_input_tokens":1000,"output_tokens":20}}}`,
+				`{"type":"assistant","message":{"id":"msg_1","role":"assistant","content":[{"type":"tool_use","name":"Bash"}],"usage":{"input_tokens":10,"cache_read_input_tokens":1000,"output_tokens":20}}}`,
+				`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","content":"ok"}]}}`,
+				`{"type":"user","isSidechain":true,"message":{"role":"user","content":"subagent task"}}`,
+				`{"type":"assistant","message":{"id":"msg_2","role":"assistant","content":[{"type":"text","text":"All green."}],"usage":{"input_tokens":5,"cache_read_input_tokens":1200,"cache_creation_input_tokens":30,"output_tokens":7}}}`,
+			},
+			want: Transcript{LastPrompt: "fix the tests", LastReply: "All green.", Turns: 1, Context: 1235, Output: 27},
+		},
+		{
+			name: "pi",
+			lines: []string{
+				`{"type":"session","id":"abc","cwd":"/tmp"}`,
+				`{"type":"message","message":{"role":"user","content":[{"type":"text","text":"hello"}]}}`,
+				`{"type":"message","message":{"role":"assistant","content":[{"type":"text","text":"hi"},{"type":"toolCall","name":"bash"}],"usage":{"input":50,"output":3,"cacheRead":100}}}`,
+				`{"type":"message","message":{"role":"toolResult","content":[{"type":"text","text":"out"}]}}`,
+				`{"type":"message","message":{"role":"user","content":"again"}}`,
+			},
+			want: Transcript{LastPrompt: "again", LastReply: "hi", Turns: 2, Context: 150, Output: 3},
+		},
+		{
+			name:  "malformed lines skipped",
+			lines: []string{`not json`, `{"type":"user"`, `{"type":"user","message":{"content":"ok"}}`},
+			want:  Transcript{LastPrompt: "ok", Turns: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.jsonl")
+			if err := os.WriteFile(path, []byte(strings.Join(tt.lines, "\n")+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadTranscript(path, Transcript{})
+			if err != nil {
+				t.Fatalf("ReadTranscript() error = %v", err)
+			}
+			got.offset, got.lastID = 0, ""
+			if got != tt.want {
+				t.Errorf("ReadTranscript() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadTranscriptIncremental(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	first := `{"type":"user","message":{"content":"one"}}` + "\n"
+	if err := os.WriteFile(path, []byte(first+`{"type":"user","mess`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadTranscript(path, Transcript{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Turns != 1 || got.offset != int64(len(first)) {
+		t.Fatalf("partial line should be left for later: %+v", got)
+	}
+
+	// The partial line completes and another one is appended
+	rest := first + `{"type":"user","message":{"content":"two"}}` + "\n" + `{"type":"user","message":{"content":"three"}}` + "\n"
+	if err := os.WriteFile(path, []byte(rest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = ReadTranscript(path, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Turns != 3 || got.LastPrompt != "three" {
+		t.Errorf("incremental read = %+v, want 3 turns ending in three", got)
+	}
+
+	// A truncated (rewritten) file is read from the start
+	if err := os.WriteFile(path, []byte(first), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = ReadTranscript(path, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Turns != 1 || got.LastPrompt != "one" {
+		t.Errorf("read after truncation = %+v, want 1 turn", got)
+	}
+
+	if _, err := ReadTranscript(filepath.Join(t.TempDir(), "missing.jsonl"), Transcript{}); err == nil {
+		t.Error("ReadTranscript() of missing file should fail")
+	}
+}
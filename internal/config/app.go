@@ -7,11 +7,18 @@ const (
 	AppName = "BLACK ATOM HELM"
 
 	// Directory and file names
-	AppDirName        = "helm"
-	ConfigFileName    = "config.yml"
-	BookmarksFileName = "bookmarks.yml"
-	StatusFileExt     = ".status"
-	PiStatusFileExt   = ".pi-status"
+	AppDirName             = "helm"
+	ConfigFileName         = "config.yml"
+	BookmarksFileName      = "bookmarks.yml"
+	ProjectFileName        = ".helm.yml"            // Per-project session template, in the repo root
+	TrustedLayoutsFileName = "trusted-layouts.json" // Approved ProjectFileName hashes
+	SnapshotFileName       = "snapshot.json"
+	ReapedFileName         = "reaped.json"     // Snapshot of sessions killed by `helm reap`
+	StatuslineFileName     = "statusline.json" // Cached `helm statusline` data
+	DaemonDirName          = "daemon"          // Owner-only dir the daemon socket is in
+	DaemonSocketFileName   = "helm.sock"       // `helm daemon` Unix socket
+	StatusFileExt          = ".status"
+	PiStatusFileExt        = ".pi-status"
 )
 
 // ConfigDirName returns the relative path for config files under ~/.config/
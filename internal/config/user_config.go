@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/x/ansi"
 	"gopkg.in/yaml.v3"
 )
 
@@ -35,18 +38,43 @@ type Config struct {
 	// Directory containing layout scripts
 	LayoutDir string `yaml:"layout_dir"`
 
-	// Enable layout scripts feature (when disabled, layouts won't be auto-applied)
+	// Enable layouts feature — session templates and layout scripts
+	// (when disabled, layouts won't be auto-applied)
 	EnableLayouts bool `yaml:"enable_layouts"`
 
+	// Directory containing named session templates (<name>.yml)
+	TemplateDir string `yaml:"template_dir"`
+
+	// Maps project path globs to session template names. First match wins;
+	// a .helm.yml in the project root takes priority over these rules.
+	SessionTemplates []SessionTemplateRule `yaml:"session_templates,omitempty"`
+
 	// Enable Claude Code status integration
 	ClaudeStatusEnabled bool `yaml:"claude_status_enabled"`
 
 	// Enable Pi status integration
 	PiStatusEnabled bool `yaml:"pi_status_enabled"`
 
+	// Additional agent clients (Codex, Aider, ...) whose hooks write helm
+	// status files. Declared agents are always tracked.
+	Agents []AgentConfig `yaml:"agents,omitempty"`
+
 	// Enable git status indicator in session list
 	GitStatusEnabled bool `yaml:"git_status_enabled"`
 
+	// Optional git columns in the session list (need git_status_enabled)
+	SessionColumns SessionColumnsConfig `yaml:"session_columns"`
+
+	// Group the session list under collapsible headers: "project_dir" or
+	// "git_host". Sessions tagged with `helm sessions group` go to their
+	// tag's group either way. Empty = tags only.
+	GroupBy string `yaml:"group_by,omitempty"`
+
+	// Session list order: "activity" (default), "name", "path" or
+	// "urgency". C-s cycles through them in the TUI; pinned sessions
+	// (C-f) stay on top in every mode.
+	SessionSort string `yaml:"session_sort,omitempty"`
+
 	// Directory for status cache files
 	CacheDir string `yaml:"cache_dir"`
 
@@ -62,9 +90,17 @@ type Config struct {
 	// to corp/~alice/proj instead of git.corp.example.com/~alice/proj
 	GitProviders map[string]string `yaml:"git_providers,omitempty"`
 
+	// Hosts whose repos the clone picker lists under "My repos". Empty =
+	// github.com only, with the gh CLI's login.
+	RepoProviders []RepoProvider `yaml:"repo_providers,omitempty"`
+
 	// Default directory for new sessions created with C-n
 	DefaultSessionDir string `yaml:"default_session_dir"`
 
+	// Directory for new git worktrees. Empty = next to the repo, as
+	// <repo>@<branch>.
+	WorktreeDir string `yaml:"worktree_dir,omitempty"`
+
 	// Lazygit popup dimensions
 	LazygitPopup PopupConfig `yaml:"lazygit_popup"`
 
@@ -77,6 +113,188 @@ type Config struct {
 
 	// Repositories to ensure are cloned (used by helm setup)
 	EnsureCloned []EnsureClonedEntry `yaml:"ensure_cloned,omitempty"`
+
+	// Session snapshots (helm snapshot save/restore)
+	Snapshot SnapshotConfig `yaml:"snapshot"`
+
+	// Agent attention notifications (helm watch)
+	Notifications NotificationsConfig `yaml:"notifications"`
+
+	// Idle session cleanup (helm reap)
+	Reap ReapConfig `yaml:"reap"`
+
+	// Canned replies offered when replying to an agent from the TUI (C-t),
+	// cycled with Tab
+	Replies []string `yaml:"replies,omitempty"`
+}
+
+// NotificationsConfig holds settings for the helm watch daemon
+type NotificationsConfig struct {
+	// Show a tmux display-message on the attached client
+	Tmux bool `yaml:"tmux"`
+
+	// Shell command run per notification. Placeholders: {session}, {agent},
+	// {tool}, {event} ("waiting" or "overdue"), {waited}, {message}; they
+	// expand to $HELM_SESSION etc., so always wrap them in double quotes:
+	// unquoted, the shell word-splits and globs the values
+	NotifyCommand string `yaml:"notify_command,omitempty"`
+
+	// Notify again when an agent has been waiting this long; 0 disables
+	WaitingThreshold time.Duration `yaml:"waiting_threshold,omitempty"`
+
+	// How often helm watch polls the status files
+	PollInterval time.Duration `yaml:"poll_interval,omitempty"`
+}
+
+// Session grouping modes (group_by)
+const (
+	GroupByProjectDir = "project_dir" // The project_dirs root a session lives under
+	GroupByGitHost    = "git_host"    // The git_providers alias (or host) directory
+)
+
+// Session list orders (session_sort)
+const (
+	SortActivity = "activity" // Most recently active first
+	SortName     = "name"     // Alphabetical
+	SortPath     = "path"     // By session directory
+	SortUrgency  = "urgency"  // Waiting agents first, then working ones
+)
+
+// SortModes lists the session list orders in the order C-s cycles them.
+var SortModes = []string{SortActivity, SortName, SortPath, SortUrgency}
+
+// SessionColumnsConfig toggles the optional session list columns
+type SessionColumnsConfig struct {
+	// Show the branch each session's repo is on
+	Branch bool `yaml:"branch"`
+
+	// Widest the branch column gets; longer names are cut with "…"
+	BranchWidth int `yaml:"branch_width,omitempty"`
+
+	// Show commits ahead of/behind the upstream as ↑n↓n
+	AheadBehind bool `yaml:"ahead_behind"`
+}
+
+// SnapshotConfig holds session snapshot settings
+type SnapshotConfig struct {
+	// How often the open TUI saves a snapshot (e.g. "15m"); 0 disables auto-save
+	AutoSaveInterval time.Duration `yaml:"auto_save_interval,omitempty"`
+
+	// Programs restarted in their panes on restore. Panes running anything
+	// else come back as a plain shell in the same directory.
+	RestoreCommands []string `yaml:"restore_commands,omitempty"`
+}
+
+// RepoProvider is a git host whose API lists the user's repos for cloning.
+type RepoProvider struct {
+	// github, gitlab, gitea (also Forgejo) or bitbucket (Server / Data Center)
+	Type string `yaml:"type"`
+
+	// Hostname, required but for github.com and gitlab.com
+	Host string `yaml:"host,omitempty"`
+
+	// API root when it isn't the default for the host
+	URL string `yaml:"url,omitempty"`
+
+	// API token, or the environment variable holding it. Default for
+	// github.com: GITHUB_TOKEN/GH_TOKEN, for gitlab.com: GITLAB_TOKEN; for
+	// GitHub hosts also the gh CLI's login. Other hosts need one of them.
+	Token    string `yaml:"token,omitempty"`
+	TokenEnv string `yaml:"token_env,omitempty"`
+}
+
+// ReapConfig holds the policies of helm reap. A session is eligible for
+// cleanup when it meets all of them; pinned sessions never are.
+type ReapConfig struct {
+	// No activity for this long (e.g. "24h")
+	IdleAfter time.Duration `yaml:"idle_after,omitempty"`
+
+	// No clients attached
+	Detached bool `yaml:"detached"`
+
+	// No live agent process in any pane
+	NoAgent bool `yaml:"no_agent"`
+
+	// No uncommitted changes in the session's repo
+	Clean bool `yaml:"clean"`
+
+	// Save each session's windows and panes to <cache_dir>/reaped.json
+	// before killing it, for 'helm snapshot restore --file'
+	Snapshot bool `yaml:"snapshot"`
+
+	// Session name globs never reaped (filepath.Match syntax)
+	Keep []string `yaml:"keep,omitempty"`
+}
+
+// AgentConfig declares an agent client beyond the built-in Claude Code
+// and Pi. Everything but the name has a default.
+type AgentConfig struct {
+	Name     string   `yaml:"name"`               // e.g. "codex"
+	FileExt  string   `yaml:"file_ext,omitempty"` // Status file extension (default ".<name>-status")
+	Binaries []string `yaml:"binaries,omitempty"` // Process names for liveness checks (default [name])
+	Header   string   `yaml:"header,omitempty"`   // Status column header, 2 cells wide (default "Co" for codex)
+	Glyph    string   `yaml:"glyph,omitempty"`    // 1-cell icon while working (default: the spinner)
+	Color    string   `yaml:"color,omitempty"`    // Header and working icon color, hex or ANSI index (default: theme's)
+}
+
+// builtinAgents are the agent names with dedicated integrations.
+var builtinAgents = []string{"claude", "pi"}
+
+// normalizeAgents validates declared agents and fills in defaults.
+func normalizeAgents(agents []AgentConfig) error {
+	seen := make(map[string]bool)
+	for i := range agents {
+		a := &agents[i]
+		a.Name = strings.TrimSpace(a.Name)
+		switch {
+		case a.Name == "":
+			return fmt.Errorf("agents[%d]: name is required", i)
+		case slices.Contains(builtinAgents, a.Name):
+			return fmt.Errorf("agents[%d]: %q is built in, use %s_status_enabled", i, a.Name, a.Name)
+		case seen[a.Name]:
+			return fmt.Errorf("agents[%d]: duplicate agent %q", i, a.Name)
+		}
+		seen[a.Name] = true
+
+		if a.FileExt == "" {
+			a.FileExt = "." + a.Name + "-status"
+		} else if !strings.HasPrefix(a.FileExt, ".") {
+			a.FileExt = "." + a.FileExt
+		}
+		if a.FileExt == StatusFileExt || a.FileExt == PiStatusFileExt {
+			return fmt.Errorf("agents[%d]: file_ext %q is taken by a built-in agent", i, a.FileExt)
+		}
+		if len(a.Binaries) == 0 {
+			a.Binaries = []string{a.Name}
+		}
+		if a.Header == "" {
+			a.Header = defaultHeader(a.Name)
+		}
+		if w := ansi.StringWidth(a.Header); w != 2 {
+			return fmt.Errorf("agents[%d]: header %q is %d cells wide, want 2", i, a.Header, w)
+		}
+		if a.Glyph != "" && ansi.StringWidth(a.Glyph) != 1 {
+			return fmt.Errorf("agents[%d]: glyph %q must be 1 cell wide", i, a.Glyph)
+		}
+	}
+	return nil
+}
+
+// defaultHeader derives a 2-cell column header from an agent name: its
+// capitalized first letters, padded if the name is a single letter.
+func defaultHeader(name string) string {
+	var header string
+	for i, r := range name {
+		next := string(r)
+		if i == 0 {
+			next = strings.ToUpper(next)
+		}
+		if ansi.StringWidth(header+next) > 2 {
+			break
+		}
+		header += next
+	}
+	return header + strings.Repeat(" ", 2-ansi.StringWidth(header))
 }
 
 // PopupConfig holds popup dimension settings
@@ -85,16 +303,62 @@ type PopupConfig struct {
 	Height string `yaml:"height"`
 }
 
+// SessionTemplateRule selects a named session template for projects whose
+// path matches Match. Patterns use filepath.Match syntax and may start
+// with ~; a pattern without "/" is matched against the directory name only.
+type SessionTemplateRule struct {
+	Match    string `yaml:"match"`
+	Template string `yaml:"template"`
+}
+
 // Bookmark represents a quick-access session bookmark
 type Bookmark struct {
 	Path string `yaml:"path"`
+
+	// Session name override, set when the bookmarked session was renamed.
+	// Empty = derived from Path (see ExtractSessionName).
+	Session string `yaml:"session,omitempty"`
+}
+
+// BookmarkSessionName returns the tmux session name a bookmark opens.
+func (cfg Config) BookmarkSessionName(b Bookmark) string {
+	if b.Session != "" {
+		return b.Session
+	}
+	return ExtractSessionName(b.Path, cfg.ProjectDirs, cfg.ProjectDepth)
+}
+
+// RenameBookmarkSession points bookmarks that open oldName at newName.
+// Reports whether any bookmark changed (and needs saving).
+func (cfg *Config) RenameBookmarkSession(oldName, newName string) bool {
+	changed := false
+	for i, b := range cfg.Bookmarks {
+		if cfg.BookmarkSessionName(b) != oldName {
+			continue
+		}
+		// Drop the override when the new name is the derived one again
+		cfg.Bookmarks[i].Session = newName
+		if ExtractSessionName(b.Path, cfg.ProjectDirs, cfg.ProjectDepth) == newName {
+			cfg.Bookmarks[i].Session = ""
+		}
+		changed = true
+	}
+	return changed
 }
 
 // EnsureClonedEntry represents a repository to ensure is cloned.
-// Supports both string format (just a URL) and object format (url + post_clone).
+// Supports both string format (just a URL) and object format (url +
+// post_clone and clone options).
 type EnsureClonedEntry struct {
 	URL       string `yaml:"url"`
 	PostClone string `yaml:"post_clone,omitempty"`
+
+	// Clone options, for repos too big to clone in full
+	Depth             int      `yaml:"depth,omitempty"`              // Shallow clone of the last N commits
+	Filter            string   `yaml:"filter,omitempty"`             // Partial clone filter, e.g. blob:none
+	Sparse            []string `yaml:"sparse,omitempty"`             // Sparse checkout of these directories
+	Branch            string   `yaml:"branch,omitempty"`             // Branch to check out instead of the default
+	RecurseSubmodules bool     `yaml:"recurse_submodules,omitempty"` // Clone submodules too
 }
 
 // UnmarshalYAML allows EnsureClonedEntry to be specified as either a plain string or an object.
@@ -115,6 +379,7 @@ func DefaultConfig() Config {
 		Layout:              "",
 		LayoutDir:           filepath.Join(home, ".config", "tmux", "layouts"),
 		EnableLayouts:       false,
+		TemplateDir:         filepath.Join(home, ".config", ConfigDirName(), "templates"),
 		ClaudeStatusEnabled: false,
 		PiStatusEnabled:     false,
 		GitStatusEnabled:    false,
@@ -122,10 +387,30 @@ func DefaultConfig() Config {
 		ProjectDirs:         []string{filepath.Join(home, "repos")},
 		ProjectDepth:        2,
 		DefaultSessionDir:   home,
+		SessionSort:         SortActivity,
 		LazygitPopup: PopupConfig{
 			Width:  "90%",
 			Height: "90%",
 		},
+		SessionColumns: SessionColumnsConfig{
+			BranchWidth: 20,
+		},
+		Snapshot: SnapshotConfig{
+			RestoreCommands: []string{"nvim", "vim", "lazygit", "htop", "btop"},
+		},
+		Notifications: NotificationsConfig{
+			Tmux:             true,
+			WaitingThreshold: 5 * time.Minute,
+			PollInterval:     2 * time.Second,
+		},
+		Reap: ReapConfig{
+			IdleAfter: 24 * time.Hour,
+			Detached:  true,
+			NoAgent:   true,
+			Clean:     true,
+			Snapshot:  true,
+		},
+		Replies: []string{"yes", "continue"},
 	}
 }
 
@@ -165,8 +450,10 @@ func Load() (Config, error) {
 
 	// Expand ~ in paths
 	cfg.LayoutDir = expandPath(cfg.LayoutDir)
+	cfg.TemplateDir = expandPath(cfg.TemplateDir)
 	cfg.CacheDir = expandPath(cfg.CacheDir)
 	cfg.DefaultSessionDir = expandPath(cfg.DefaultSessionDir)
+	cfg.WorktreeDir = expandPath(cfg.WorktreeDir)
 
 	// Expand ~ in project directories
 	for i, d := range cfg.ProjectDirs {
@@ -178,6 +465,41 @@ func Load() (Config, error) {
 		cfg.Bookmarks[i].Path = expandPath(cfg.Bookmarks[i].Path)
 	}
 
+	// Expand ~ in session template globs
+	for i := range cfg.SessionTemplates {
+		cfg.SessionTemplates[i].Match = expandPath(cfg.SessionTemplates[i].Match)
+	}
+
+	if err := normalizeAgents(cfg.Agents); err != nil {
+		return cfg, err
+	}
+
+	switch cfg.GroupBy {
+	case "", GroupByProjectDir, GroupByGitHost:
+	default:
+		return cfg, fmt.Errorf("group_by: unknown mode %q (want %s or %s)", cfg.GroupBy, GroupByProjectDir, GroupByGitHost)
+	}
+
+	if !slices.Contains(SortModes, cfg.SessionSort) {
+		return cfg, fmt.Errorf("session_sort: unknown mode %q (want one of %s)", cfg.SessionSort, strings.Join(SortModes, ", "))
+	}
+
+	for i, e := range cfg.EnsureCloned {
+		if e.Depth < 0 {
+			return cfg, fmt.Errorf("ensure_cloned[%d]: depth must not be negative", i)
+		}
+	}
+
+	for _, pattern := range cfg.Reap.Keep {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return cfg, fmt.Errorf("reap.keep: bad pattern %q: %w", pattern, err)
+		}
+	}
+
+	if cfg.SessionColumns.BranchWidth < 1 {
+		cfg.SessionColumns.BranchWidth = DefaultConfig().SessionColumns.BranchWidth
+	}
+
 	// Ensure ProjectDepth is at least 1
 	if cfg.ProjectDepth < 1 {
 		cfg.ProjectDepth = 2
@@ -272,7 +594,7 @@ func (cfg *Config) SaveBookmarks() error {
 	// Contract absolute paths back to ~ before saving
 	contracted := make([]Bookmark, len(cfg.Bookmarks))
 	for i, b := range cfg.Bookmarks {
-		contracted[i] = Bookmark{Path: contractPath(b.Path)}
+		contracted[i] = Bookmark{Path: contractPath(b.Path), Session: b.Session}
 	}
 	bf := BookmarksFile{Bookmarks: contracted}
 	data, err := yaml.Marshal(bf)
@@ -349,6 +671,10 @@ func ScanForGitRepos(baseDir string) []string {
 			entryPath := filepath.Join(dir, entry.Name())
 			gitPath := filepath.Join(entryPath, ".git")
 			if _, err := os.Stat(gitPath); err == nil {
+				// Linked worktrees are listed under their main repo
+				if isLinkedWorktree(gitPath) {
+					continue
+				}
 				// Found a repo — add it, don't recurse into it
 				repos = append(repos, entryRel)
 				continue
@@ -361,6 +687,18 @@ func ScanForGitRepos(baseDir string) []string {
 	return repos
 }
 
+// isLinkedWorktree reports whether the .git at gitPath is the file of a
+// linked worktree, pointing into its main repo's .git/worktrees. Kept here
+// rather than using internal/git, so config stays a leaf package.
+func isLinkedWorktree(gitPath string) bool {
+	data, err := os.ReadFile(gitPath) // fails for a .git directory
+	if err != nil {
+		return false
+	}
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	return ok && filepath.Base(filepath.Dir(filepath.Clean(gitdir))) == "worktrees"
+}
+
 // IsHiddenDir returns true for VCS and internal metadata directories
 // (e.g. .git, .hg, .svn) but false for project directories that happen to
 // start with a dot (e.g. .github-private).
@@ -383,23 +721,6 @@ func ListClonedRepos(basePath string) ([]string, error) {
 	return repos, nil
 }
 
-// FilterUncloned returns repos from available that are not in cloned
-func FilterUncloned(available, cloned []string) []string {
-	clonedSet := make(map[string]bool)
-	for _, r := range cloned {
-		clonedSet[r] = true
-	}
-
-	var uncloned []string
-	for _, r := range available {
-		if !clonedSet[r] {
-			uncloned = append(uncloned, r)
-		}
-	}
-
-	return uncloned
-}
-
 // ListAllRepos scans all project directories and returns every cloned repo with its path.
 // Deduplicates by name — first occurrence wins.
 func ListAllRepos(projectDirs []string) ([]RepoInfo, error) {
@@ -442,6 +763,23 @@ func SanitizeSessionName(name string) string {
 	return replacer.Replace(name)
 }
 
+// WorktreeSessionName returns the session name for a repo's worktree:
+// "<repo session>@<branch>".
+func WorktreeSessionName(repoSession, branch string) string {
+	return SanitizeSessionName(repoSession + "@" + branch)
+}
+
+// WorktreePath returns where a new worktree for branch is created:
+// <repo>@<branch> in worktreeDir, or next to the repo when worktreeDir is
+// empty.
+func WorktreePath(worktreeDir, repoPath, branch string) string {
+	base := worktreeDir
+	if base == "" {
+		base = filepath.Dir(repoPath)
+	}
+	return filepath.Join(base, filepath.Base(repoPath)+"@"+SanitizeSessionName(branch))
+}
+
 // extractRelPath converts an absolute path to a relative form for display or
 // session naming. Tries each projectDir in order — the first one that contains
 // the path wins. Falls back to the last `depth` components of the path when
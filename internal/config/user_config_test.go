@@ -3,8 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestExpandPath(t *testing.T) {
@@ -175,6 +177,54 @@ func TestSaveBookmarksUsesTildePaths(t *testing.T) {
 	}
 }
 
+func TestRenameBookmarkSession(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectDirs = []string{"/repos"}
+	cfg.ProjectDepth = 2
+	cfg.Bookmarks = []Bookmark{
+		{Path: "/repos/org/api"},
+		{Path: "/repos/org/web"},
+	}
+
+	if !cfg.RenameBookmarkSession("org-api", "api") {
+		t.Fatal("RenameBookmarkSession() = false, want true")
+	}
+	if got := cfg.BookmarkSessionName(cfg.Bookmarks[0]); got != "api" {
+		t.Errorf("renamed bookmark opens %q, want %q", got, "api")
+	}
+	if got := cfg.BookmarkSessionName(cfg.Bookmarks[1]); got != "org-web" {
+		t.Errorf("untouched bookmark opens %q, want %q", got, "org-web")
+	}
+
+	// Renaming back to the derived name drops the override
+	cfg.RenameBookmarkSession("api", "org-api")
+	if cfg.Bookmarks[0].Session != "" {
+		t.Errorf("Session = %q, want override cleared", cfg.Bookmarks[0].Session)
+	}
+
+	if cfg.RenameBookmarkSession("unrelated", "x") {
+		t.Error("RenameBookmarkSession() = true for a session without bookmarks")
+	}
+}
+
+func TestWorktreeNaming(t *testing.T) {
+	if got := WorktreeSessionName("org-api", "feat/login"); got != "org-api@feat-login" {
+		t.Errorf("WorktreeSessionName() = %q, want %q", got, "org-api@feat-login")
+	}
+	if got := WorktreePath("", "/repos/org/api", "feat/login"); got != "/repos/org/api@feat-login" {
+		t.Errorf("WorktreePath() next to repo = %q", got)
+	}
+	if got := WorktreePath("/worktrees", "/repos/org/api", "fix"); got != "/worktrees/api@fix" {
+		t.Errorf("WorktreePath() in worktree_dir = %q", got)
+	}
+	// The default placement yields the same session name as picking the
+	// worktree dir in the project picker
+	path := WorktreePath("", "/repos/org/api", "feat/login")
+	if got := ExtractSessionName(path, []string{"/repos"}, 2); got != WorktreeSessionName("org-api", "feat/login") {
+		t.Errorf("ExtractSessionName(%q) = %q, want worktree session name", path, got)
+	}
+}
+
 func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -311,6 +361,218 @@ bookmarks:
 	}
 }
 
+func TestLoadSnapshotConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	if err := os.MkdirAll(filepath.Dir(Path()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configContent := `snapshot:
+  auto_save_interval: 15m
+`
+	if err := os.WriteFile(Path(), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if cfg.Snapshot.AutoSaveInterval != 15*time.Minute {
+		t.Errorf("AutoSaveInterval = %v, want 15m", cfg.Snapshot.AutoSaveInterval)
+	}
+	// Omitted keys keep their defaults
+	if len(cfg.Snapshot.RestoreCommands) == 0 {
+		t.Error("RestoreCommands should keep its default when not configured")
+	}
+}
+
+func TestLoadNotificationsConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	if err := os.MkdirAll(filepath.Dir(Path()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configContent := `notifications:
+  notify_command: notify-send "{message}"
+  waiting_threshold: 10m
+`
+	if err := os.WriteFile(Path(), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	n := cfg.Notifications
+	if n.NotifyCommand != `notify-send "{message}"` {
+		t.Errorf("NotifyCommand = %q", n.NotifyCommand)
+	}
+	if n.WaitingThreshold != 10*time.Minute {
+		t.Errorf("WaitingThreshold = %v, want 10m", n.WaitingThreshold)
+	}
+	// Unset keys keep their defaults
+	if !n.Tmux || n.PollInterval != 2*time.Second {
+		t.Errorf("defaults lost: Tmux = %v, PollInterval = %v", n.Tmux, n.PollInterval)
+	}
+}
+
+func TestLoadReapConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	if err := os.MkdirAll(filepath.Dir(Path()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(content string) {
+		if err := os.WriteFile(Path(), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`reap:
+  idle_after: 6h
+  clean: false
+  keep: [main, "dot*"]
+`)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	r := cfg.Reap
+	if r.IdleAfter != 6*time.Hour || r.Clean || len(r.Keep) != 2 {
+		t.Errorf("Reap = %+v", r)
+	}
+	// Unset keys keep their defaults
+	if !r.Detached || !r.NoAgent || !r.Snapshot {
+		t.Errorf("defaults lost: %+v", r)
+	}
+
+	write(`reap:
+  keep: ["[main"]
+`)
+	if _, err := Load(); err == nil {
+		t.Error("Load() should reject a bad reap.keep pattern")
+	}
+}
+
+func TestLoadRepoProviders(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	if err := os.MkdirAll(filepath.Dir(Path()), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "repo_providers:\n  - type: github\n  - type: gitea\n    host: codeberg.org\n    token_env: CB_TOKEN\n"
+	if err := os.WriteFile(Path(), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := []RepoProvider{{Type: "github"}, {Type: "gitea", Host: "codeberg.org", TokenEnv: "CB_TOKEN"}}
+	if !reflect.DeepEqual(cfg.RepoProviders, want) {
+		t.Errorf("RepoProviders = %+v, want %+v", cfg.RepoProviders, want)
+	}
+}
+
+func TestLoadEnsureClonedOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	if err := os.MkdirAll(filepath.Dir(Path()), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `ensure_cloned:
+  - owner/dots
+  - url: corp/monorepo
+    depth: 1
+    filter: blob:none
+    sparse: [services/api, tools]
+    branch: main
+    recurse_submodules: true
+`
+	if err := os.WriteFile(Path(), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.EnsureCloned) != 2 {
+		t.Fatalf("EnsureCloned = %+v", cfg.EnsureCloned)
+	}
+	if got, want := cfg.EnsureCloned[0], (EnsureClonedEntry{URL: "owner/dots"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("plain entry = %+v, want %+v", got, want)
+	}
+	want := EnsureClonedEntry{
+		URL:               "corp/monorepo",
+		Depth:             1,
+		Filter:            "blob:none",
+		Sparse:            []string{"services/api", "tools"},
+		Branch:            "main",
+		RecurseSubmodules: true,
+	}
+	if got := cfg.EnsureCloned[1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("entry = %+v, want %+v", got, want)
+	}
+
+	if err := os.WriteFile(Path(), []byte("ensure_cloned:\n  - url: a/b\n    depth: -1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "depth") {
+		t.Errorf("negative depth: err = %v", err)
+	}
+}
+
+func TestNormalizeAgents(t *testing.T) {
+	agents := []AgentConfig{
+		{Name: "codex"},
+		{Name: "aider", FileExt: "aider", Binaries: []string{"aider", "python"}, Header: "AD", Color: "#ff8800"},
+		{Name: "x"},
+		{Name: "日本", Glyph: "◆"},
+	}
+	if err := normalizeAgents(agents); err != nil {
+		t.Fatalf("normalizeAgents() error: %v", err)
+	}
+
+	codex := agents[0]
+	if codex.FileExt != ".codex-status" || codex.Header != "Co" || len(codex.Binaries) != 1 || codex.Binaries[0] != "codex" {
+		t.Errorf("codex defaults = %+v", codex)
+	}
+	if agents[1].FileExt != ".aider" || agents[1].Header != "AD" || len(agents[1].Binaries) != 2 {
+		t.Errorf("aider = %+v, explicit values must be kept", agents[1])
+	}
+	if agents[2].Header != "X " {
+		t.Errorf("one-letter name header = %q, want %q", agents[2].Header, "X ")
+	}
+	if agents[3].Header != "日" {
+		t.Errorf("wide name header = %q, want %q", agents[3].Header, "日")
+	}
+
+	invalid := map[string][]AgentConfig{
+		"empty name":  {{Name: " "}},
+		"builtin":     {{Name: "claude"}},
+		"duplicate":   {{Name: "codex"}, {Name: "codex"}},
+		"taken ext":   {{Name: "codex", FileExt: ".status"}},
+		"long header": {{Name: "codex", Header: "COD"}},
+		"wide header": {{Name: "codex", Header: "日本"}},
+		"wide glyph":  {{Name: "codex", Glyph: "日"}},
+	}
+	for name, agents := range invalid {
+		if err := normalizeAgents(agents); err == nil {
+			t.Errorf("%s: normalizeAgents() succeeded, want error", name)
+		}
+	}
+}
+
 func TestScanForGitRepos(t *testing.T) {
 	// Create a temp directory for the test
 	tmpDir, err := os.MkdirTemp("", "helm-scantest")
@@ -364,6 +626,19 @@ func TestScanForGitRepos(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// A linked worktree of repo1 next to it (listed under repo1, not as a repo)
+	if err := os.MkdirAll(filepath.Join(repo1, ".git", "worktrees", "repo1@feat"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	worktree := filepath.Join(tmpDir, "repo1@feat")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitFile := "gitdir: " + filepath.Join(repo1, ".git", "worktrees", "repo1@feat") + "\n"
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte(gitFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	repos := ScanForGitRepos(tmpDir)
 
 	// Should find all three repos (sorted alphabetically)
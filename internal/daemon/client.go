@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// dialTimeout is short: with no daemon running the dial fails at once,
+	// and callers fall back to collecting state themselves.
+	dialTimeout = 100 * time.Millisecond
+	// callTimeout bounds a whole request; only refresh takes long.
+	callTimeout = 30 * time.Second
+)
+
+// ErrNotRunning is returned when no daemon listens on the socket.
+var ErrNotRunning = errors.New("daemon not running")
+
+var nextID atomic.Int64
+
+// Call sends one JSON-RPC request to the daemon listening on socket and
+// decodes the result into result (which may be nil).
+func Call(socket, method string, params, result any) error {
+	conn, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(callTimeout))
+
+	id, _ := json.Marshal(nextID.Add(1))
+	req := request{JSONRPC: "2.0", ID: id, Method: method}
+	if params != nil {
+		if req.Params, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return err
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// State fetches the daemon's current snapshot.
+func State(socket string) (Snapshot, error) {
+	var snap Snapshot
+	err := Call(socket, "state", nil, &snap)
+	return snap, err
+}
@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// Collector returns the CollectFunc for the given config: sessions from
+// tmux, git status per session (in parallel, like `helm sessions list`) and
+// agent statuses with dead agents dropped.
+func Collector(cfg config.Config, agents agent.Registry) CollectFunc {
+	return func(prev Snapshot, full bool) Snapshot {
+		snap := Snapshot{UpdatedAt: time.Now(), Sessions: prev.Sessions, PaneAgents: prev.PaneAgents}
+		if full || prev.UpdatedAt.IsZero() {
+			sessions, err := tmux.ListSessions("")
+			if err != nil {
+				sessions = nil // tmux server not running
+			}
+			snap.Sessions = collectSessions(cfg, sessions)
+		}
+		collectAgents(cfg, agents, &snap)
+		return snap
+	}
+}
+
+// collectSessions builds the session list with paths and git status.
+func collectSessions(cfg config.Config, sessions []tmux.Session) []Session {
+	out := make([]Session, len(sessions))
+	var wg sync.WaitGroup
+	const maxParallel = 8
+	sem := make(chan struct{}, maxParallel)
+	for i, s := range sessions {
+		out[i] = Session{Name: s.Name, LastActivity: s.LastActivity, Agents: []Agent{}}
+		wg.Add(1)
+		go func(session *Session) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			session.Path, _ = git.GetSessionPath(session.Name)
+			if cfg.GitStatusEnabled && session.Path != "" {
+				if status := git.GetStatus(session.Path); status.IsRepo {
+					session.Git = &Git{
+						Dirty:     status.Dirty,
+						Additions: status.Additions,
+						Deletions: status.Deletions,
+						Branch:    status.Branch,
+						Upstream:  status.Upstream,
+						Ahead:     status.Ahead,
+						Behind:    status.Behind,
+						Stash:     status.Stash,
+					}
+				}
+			}
+		}(&out[i])
+	}
+	wg.Wait()
+	return out
+}
+
+// collectAgents re-reads the agent statuses of the snapshot's sessions.
+// The session slice is copied first: the previous snapshot may still be
+// served to clients while this one is built.
+func collectAgents(cfg config.Config, agents agent.Registry, snap *Snapshot) {
+	kinds := agents.Enabled()
+	sessions := make([]Session, len(snap.Sessions))
+	copy(sessions, snap.Sessions)
+	snap.Sessions = sessions
+
+	names := make([]string, len(sessions))
+	for i, s := range sessions {
+		names[i] = s.Name
+		sessions[i].Agents = []Agent{}
+	}
+	if len(kinds) == 0 || len(names) == 0 {
+		return
+	}
+
+	// Without a liveness check (tmux or ps failed) statuses are kept as
+	// they are, like in the TUI
+	var live agent.Liveness
+	checked := false
+	if panePIDs, err := tmux.PanePIDs(); err == nil {
+		if live, err = agents.CheckLiveness(panePIDs); err == nil {
+			checked = true
+			snap.PaneAgents = live.PaneAgents()
+		}
+	}
+
+	for _, kind := range kinds {
+		byKind := agent.ReadAll(kind, names, cfg.CacheDir)
+		if checked {
+			agent.DropDead(kind, byKind, live, cfg.CacheDir)
+		}
+		for i := range sessions {
+			for _, s := range byKind[sessions[i].Name] {
+				sessions[i].Agents = append(sessions[i].Agents, Agent{
+					Kind:       kind.Name,
+					State:      s.State,
+					Since:      s.Timestamp,
+					Tool:       s.Tool,
+					Cwd:        s.Cwd,
+					SessionID:  s.SessionID,
+					Transcript: s.Transcript,
+				})
+			}
+		}
+	}
+}
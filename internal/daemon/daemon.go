@@ -0,0 +1,255 @@
+// Package daemon keeps session, git and agent state warm in memory and
+// serves it over a Unix socket, so the TUI, `helm statusline` and editor
+// integrations can read a snapshot instead of spawning git and ps on every
+// open.
+//
+// The protocol is JSON-RPC 2.0, one request or response object per line.
+// Methods:
+//
+//	ping                        → "pong"
+//	state                       → Snapshot
+//	session {"name": "<name>"}  → Session
+//	refresh                     → Snapshot, after a full re-collect
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/config"
+)
+
+// Snapshot is the state the daemon serves.
+type Snapshot struct {
+	UpdatedAt  time.Time      `json:"updated_at"`
+	Sessions   []Session      `json:"sessions"`
+	PaneAgents map[int]string `json:"pane_agents"` // pane shell PID → agent kind
+}
+
+// Session is one tmux session with its git and agent state.
+type Session struct {
+	Name         string    `json:"name"`
+	LastActivity time.Time `json:"last_activity"`
+	Path         string    `json:"path,omitempty"`
+	Git          *Git      `json:"git,omitempty"` // nil outside a repo or with git status disabled
+	Agents       []Agent   `json:"agents"`
+}
+
+// Git is the git status of a session's directory.
+type Git struct {
+	Dirty     int    `json:"dirty"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Branch    string `json:"branch,omitempty"`
+	Upstream  string `json:"upstream,omitempty"`
+	Ahead     int    `json:"ahead,omitempty"`
+	Behind    int    `json:"behind,omitempty"`
+	Stash     int    `json:"stash,omitempty"`
+}
+
+// Agent is one live agent instance.
+type Agent struct {
+	Kind       string    `json:"kind"`
+	State      string    `json:"state"`
+	Since      time.Time `json:"since"`
+	Tool       string    `json:"tool,omitempty"`
+	Cwd        string    `json:"cwd,omitempty"`
+	SessionID  string    `json:"session_id,omitempty"`
+	Transcript string    `json:"transcript,omitempty"`
+}
+
+// Session looks up a session by name.
+func (s Snapshot) Session(name string) (Session, bool) {
+	for _, session := range s.Sessions {
+		if session.Name == name {
+			return session, true
+		}
+	}
+	return Session{}, false
+}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeNotFound       = -32001 // unknown session
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// CollectFunc gathers fresh state. With full set it re-lists sessions and
+// git status; otherwise only agent statuses change and the rest is carried
+// over from prev.
+type CollectFunc func(prev Snapshot, full bool) Snapshot
+
+// Server holds the current snapshot and answers requests about it.
+type Server struct {
+	collect CollectFunc
+
+	refreshMu sync.Mutex // one refresh at a time
+	mu        sync.RWMutex
+	snap      Snapshot
+}
+
+// NewServer returns a server that refreshes its snapshot with collect.
+func NewServer(collect CollectFunc) *Server {
+	return &Server{collect: collect}
+}
+
+// Snapshot returns the current snapshot.
+func (s *Server) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snap
+}
+
+// Refresh re-collects the snapshot and returns it.
+func (s *Server) Refresh(full bool) Snapshot {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	snap := s.collect(s.Snapshot(), full)
+	s.mu.Lock()
+	s.snap = snap
+	s.mu.Unlock()
+	return snap
+}
+
+// Serve answers connections on ln until ctx is done.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn answers requests on one connection until the client hangs up.
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	enc := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			_ = enc.Encode(response{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &rpcError{Code: codeParseError, Message: "parse error"}})
+			continue
+		}
+		result, rerr := s.dispatch(req)
+		if len(req.ID) == 0 {
+			continue // notification: no response
+		}
+		resp := response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// dispatch runs one method.
+func (s *Server) dispatch(req request) (any, *rpcError) {
+	switch req.Method {
+	case "ping":
+		return "pong", nil
+	case "state":
+		return s.Snapshot(), nil
+	case "session":
+		var params struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return nil, &rpcError{Code: codeInvalidParams, Message: `params must be {"name": "<session>"}`}
+		}
+		session, ok := s.Snapshot().Session(params.Name)
+		if !ok {
+			return nil, &rpcError{Code: codeNotFound, Message: fmt.Sprintf("unknown session %q", params.Name)}
+		}
+		return session, nil
+	case "refresh":
+		return s.Refresh(true), nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+// SocketPath returns the daemon socket, in its own dir in the cache dir.
+func SocketPath(cacheDir string) string {
+	return filepath.Join(cacheDir, config.DaemonDirName, config.DaemonSocketFileName)
+}
+
+// ErrRunning is returned by Listen when another daemon owns the socket.
+var ErrRunning = errors.New("daemon already running")
+
+// Listen creates the socket at path, replacing a stale one left behind by
+// a daemon that didn't shut down cleanly. The socket exposes paths and
+// transcripts, so its dir is made owner only first: a socket created with
+// the umask's permissions can't be reached by others before it's chmodded.
+func Listen(path string) (net.Listener, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, dialTimeout); err == nil {
+			conn.Close()
+			return nil, ErrRunning
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
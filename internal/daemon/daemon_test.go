@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// startServer serves a fake snapshot on a socket in a temp dir.
+func startServer(t *testing.T) (string, *int) {
+	t.Helper()
+	full := 0
+	server := NewServer(func(prev Snapshot, isFull bool) Snapshot {
+		if isFull {
+			full++
+		}
+		return Snapshot{
+			UpdatedAt: time.Now(),
+			Sessions: []Session{
+				{Name: "api", Git: &Git{Dirty: 2}, Agents: []Agent{{Kind: "claude", State: "waiting"}}},
+				{Name: "web", Agents: []Agent{}},
+			},
+		}
+	})
+	server.Refresh(true)
+
+	// Unix socket paths are length-limited; t.TempDir() can be too deep
+	dir, err := os.MkdirTemp("", "helm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "helm.sock")
+
+	ln, err := Listen(socket)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go server.Serve(ctx, ln)
+	return socket, &full
+}
+
+func TestServer(t *testing.T) {
+	socket, full := startServer(t)
+
+	var pong string
+	if err := Call(socket, "ping", nil, &pong); err != nil || pong != "pong" {
+		t.Errorf("ping = %q, %v", pong, err)
+	}
+
+	snap, err := State(socket)
+	if err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+	if len(snap.Sessions) != 2 || snap.Sessions[0].Git.Dirty != 2 || snap.Sessions[0].Agents[0].State != "waiting" {
+		t.Errorf("State() = %+v", snap)
+	}
+
+	var session Session
+	if err := Call(socket, "session", map[string]string{"name": "web"}, &session); err != nil || session.Name != "web" {
+		t.Errorf("session web = %+v, %v", session, err)
+	}
+
+	var rerr *rpcError
+	if err := Call(socket, "session", map[string]string{"name": "nope"}, &session); !errors.As(err, &rerr) || rerr.Code != codeNotFound {
+		t.Errorf("unknown session error = %v", err)
+	}
+	if err := Call(socket, "session", nil, &session); !errors.As(err, &rerr) || rerr.Code != codeInvalidParams {
+		t.Errorf("missing params error = %v", err)
+	}
+	if err := Call(socket, "bogus", nil, nil); !errors.As(err, &rerr) || rerr.Code != codeMethodNotFound {
+		t.Errorf("unknown method error = %v", err)
+	}
+
+	before := *full
+	if err := Call(socket, "refresh", nil, &snap); err != nil || *full != before+1 {
+		t.Errorf("refresh ran %d full collects, err %v", *full-before, err)
+	}
+}
+
+func TestListen(t *testing.T) {
+	socket, _ := startServer(t)
+	if _, err := Listen(socket); !errors.Is(err, ErrRunning) {
+		t.Errorf("Listen() on a live socket = %v, want ErrRunning", err)
+	}
+
+	// A socket file nobody listens on is replaced
+	dir, err := os.MkdirTemp("", "helm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stale := filepath.Join(dir, "helm.sock")
+	ln, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+
+	ln, err = Listen(stale)
+	if err != nil {
+		t.Fatalf("Listen() on a stale socket error = %v", err)
+	}
+	ln.Close()
+
+	// The socket's dir is made owner only, even when it already existed
+	if err := os.Chmod(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	ln, err = Listen(filepath.Join(dir, "helm.sock"))
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ln.Close()
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("socket dir mode = %v, want 0700", perm)
+	}
+}
+
+func TestCallNotRunning(t *testing.T) {
+	err := Call(filepath.Join(t.TempDir(), "helm.sock"), "ping", nil, nil)
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Call() without daemon = %v, want ErrNotRunning", err)
+	}
+}
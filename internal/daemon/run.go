@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+)
+
+// Run serves the daemon on the socket in the cache dir until ctx is done.
+// Sessions, git and liveness are re-collected every interval; agent
+// statuses also whenever a status file in the cache dir changes.
+func Run(ctx context.Context, cfg config.Config, interval time.Duration) error {
+	path := SocketPath(cfg.CacheDir)
+	ln, err := Listen(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	agents := agent.NewRegistry(cfg)
+	server := NewServer(Collector(cfg, agents))
+	server.Refresh(true)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		ln.Close()
+		return fmt.Errorf("failed to watch %s: %w", cfg.CacheDir, err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(cfg.CacheDir); err != nil {
+		ln.Close()
+		return fmt.Errorf("failed to watch %s: %w", cfg.CacheDir, err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				server.Refresh(true)
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if agents.IsStatusFile(event.Name) && debounce == nil {
+					debounce = time.After(agent.WriteDebounce)
+				}
+			case <-debounce:
+				debounce = nil
+				server.Refresh(false)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fmt.Fprintf(os.Stderr, "helm daemon: watch error: %v\n", err)
+			}
+		}
+	}()
+
+	return server.Serve(ctx, ln)
+}
@@ -1,10 +1,6 @@
 package git
 
-import (
-	"os/exec"
-	"strconv"
-	"strings"
-)
+import "os/exec"
 
 // RepoState represents the sync state of a git repository
 type RepoState string
@@ -23,30 +19,29 @@ const (
 // SyncStatus holds the full sync state of a repository
 type SyncStatus struct {
 	State  RepoState
+	Branch string
 	Ahead  int
 	Behind int
 	Dirty  int
+	Stash  int
 }
 
 // GetSyncStatus returns the sync state of a git repository at dir.
 func GetSyncStatus(dir string) SyncStatus {
-	dirty := getDirtyCount(dir)
-
-	// Check if there's an upstream tracking branch
-	if _, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "@{u}").Output(); err != nil {
-		return SyncStatus{State: StateNoUpstream, Dirty: dirty}
+	status, _ := readStatus(dir)
+	sync := SyncStatus{
+		Branch: status.Branch,
+		Ahead:  status.Ahead,
+		Behind: status.Behind,
+		Dirty:  status.Dirty,
+		Stash:  status.Stash,
 	}
-
-	ahead := revListCount(dir, "@{u}..")
-	behind := revListCount(dir, "..@{u}")
-
-	state := resolveState(dirty, ahead, behind)
-	return SyncStatus{
-		State:  state,
-		Ahead:  ahead,
-		Behind: behind,
-		Dirty:  dirty,
+	if status.Upstream == "" {
+		sync.State = StateNoUpstream
+	} else {
+		sync.State = resolveState(status.Dirty, status.Ahead, status.Behind)
 	}
+	return sync
 }
 
 // Fetch runs git fetch --all --quiet in the given directory.
@@ -68,25 +63,6 @@ func Push(dir string) error {
 	return cmd.Run()
 }
 
-// GetBranch returns the current branch name for the repo at dir.
-func GetBranch(dir string) (string, error) {
-	out, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(out)), nil
-}
-
-// revListCount runs git rev-list --count with the given revspec and returns the count.
-func revListCount(dir, revspec string) int {
-	out, err := exec.Command("git", "-C", dir, "rev-list", "--count", revspec).Output()
-	if err != nil {
-		return 0
-	}
-	n, _ := strconv.Atoi(strings.TrimSpace(string(out)))
-	return n
-}
-
 // resolveState determines the RepoState from dirty, ahead, and behind counts.
 func resolveState(dirty, ahead, behind int) RepoState {
 	switch {
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -9,10 +10,19 @@ import (
 
 // Status represents git repository status for a session
 type Status struct {
-	IsRepo    bool
-	Dirty     int // Count of uncommitted changes (staged + unstaged + untracked)
-	Additions int // Lines added
-	Deletions int // Lines deleted
+	IsRepo     bool
+	Dirty      int // Count of uncommitted changes (staged + unstaged + untracked)
+	Staged     int // Files with changes in the index
+	Unstaged   int // Tracked files with changes in the worktree
+	Untracked  int
+	Conflicted int    // Unmerged files
+	Additions  int    // Lines added
+	Deletions  int    // Lines deleted
+	Branch     string // Current branch, "HEAD" when detached
+	Upstream   string // Tracking branch, empty if none
+	Ahead      int    // Commits not on the upstream
+	Behind     int    // Upstream commits not on the branch
+	Stash      int    // Stash entries
 }
 
 // IsClean returns true if there are no changes to show
@@ -29,39 +39,97 @@ func GetSessionPath(sessionName string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// IsRepo reports whether dir is the top of a git repo or worktree, by
+// looking for .git (a file in worktrees).
+func IsRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
 // GetStatus returns the git status for a directory
 // Returns Status{IsRepo: false} if the directory is not a git repository
 func GetStatus(dir string) Status {
-	// Check if this is a git repo by looking for .git
-	gitDir := filepath.Join(dir, ".git")
-	if !isDir(gitDir) && !isFile(gitDir) {
+	if !IsRepo(dir) {
 		return Status{IsRepo: false}
 	}
 
-	status := Status{IsRepo: true}
-
-	// Get dirty count: staged + unstaged + untracked
-	status.Dirty = getDirtyCount(dir)
-
-	// Get line additions/deletions
-	status.Additions, status.Deletions = getLineStats(dir)
+	status, err := readStatus(dir)
+	if err != nil {
+		return Status{IsRepo: true}
+	}
 
+	// Untracked files have no diff against HEAD; a clean tree needs no diff
+	if status.Staged+status.Unstaged+status.Conflicted > 0 {
+		status.Additions, status.Deletions = getLineStats(dir)
+	}
 	return status
 }
 
-// getDirtyCount returns the number of dirty files (modified, staged, untracked)
-func getDirtyCount(dir string) int {
-	cmd := exec.Command("git", "-C", dir, "status", "--porcelain")
-	out, err := cmd.Output()
+// readStatus runs a single `git status --porcelain=v2` for the file counts,
+// branch, upstream and stash of the repo at dir.
+func readStatus(dir string) (Status, error) {
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain=v2", "--branch", "--show-stash").Output()
 	if err != nil {
-		return 0
+		return Status{}, err
+	}
+	return parsePorcelainV2(string(out)), nil
+}
+
+// parsePorcelainV2 parses `git status --porcelain=v2 --branch --show-stash`.
+// Header lines start with "#"; each changed file is one entry line whose
+// XY field holds its index (X) and worktree (Y) state, "." meaning unchanged.
+func parsePorcelainV2(out string) Status {
+	status := Status{IsRepo: true}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "#":
+			parseHeader(&status, fields[1:])
+		case "1", "2":
+			status.Dirty++
+			if xy := fields[1]; len(xy) == 2 {
+				if xy[0] != '.' {
+					status.Staged++
+				}
+				if xy[1] != '.' {
+					status.Unstaged++
+				}
+			}
+		case "u":
+			status.Dirty++
+			status.Conflicted++
+		case "?":
+			status.Dirty++
+			status.Untracked++
+		}
 	}
+	return status
+}
 
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	if len(lines) == 1 && lines[0] == "" {
-		return 0
+// parseHeader applies one "# <key> <value>..." header line.
+func parseHeader(status *Status, fields []string) {
+	if len(fields) < 2 {
+		return
+	}
+	switch fields[0] {
+	case "branch.head":
+		status.Branch = fields[1]
+		if status.Branch == "(detached)" {
+			status.Branch = "HEAD" // like `git rev-parse --abbrev-ref HEAD`
+		}
+	case "branch.upstream":
+		status.Upstream = fields[1]
+	case "branch.ab":
+		if len(fields) >= 3 {
+			status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "+"))
+			status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "-"))
+		}
+	case "stash":
+		status.Stash, _ = strconv.Atoi(fields[1])
 	}
-	return len(lines)
 }
 
 // getLineStats returns lines added and deleted in working directory
@@ -84,15 +152,3 @@ func getLineStats(dir string) (additions, deletions int) {
 	}
 	return additions, deletions
 }
-
-// isDir checks if path is a directory
-func isDir(path string) bool {
-	cmd := exec.Command("test", "-d", path)
-	return cmd.Run() == nil
-}
-
-// isFile checks if path is a file (for git worktrees where .git is a file)
-func isFile(path string) bool {
-	cmd := exec.Command("test", "-f", path)
-	return cmd.Run() == nil
-}
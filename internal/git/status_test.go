@@ -0,0 +1,103 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePorcelainV2(t *testing.T) {
+	out := `# branch.oid 1111111111111111111111111111111111111111
+# branch.head feat/login
+# branch.upstream origin/feat/login
+# branch.ab +2 -1
+# stash 3
+1 M. N... 100644 100644 100644 aaaa bbbb staged.go
+1 .M N... 100644 100644 100644 aaaa aaaa unstaged.go
+1 MM N... 100644 100644 100644 aaaa bbbb both.go
+2 R. N... 100644 100644 100644 aaaa aaaa R100 new name.go	old name.go
+u UU N... 100644 100644 100644 100644 aaaa bbbb cccc conflict.go
+? untracked.go
+! ignored.log
+`
+	got := parsePorcelainV2(out)
+	want := Status{
+		IsRepo:     true,
+		Dirty:      6,
+		Staged:     3,
+		Unstaged:   2,
+		Untracked:  1,
+		Conflicted: 1,
+		Branch:     "feat/login",
+		Upstream:   "origin/feat/login",
+		Ahead:      2,
+		Behind:     1,
+		Stash:      3,
+	}
+	if got != want {
+		t.Errorf("parsePorcelainV2() = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParsePorcelainV2Headers(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want Status
+	}{
+		{"clean, no upstream", "# branch.oid 1111\n# branch.head main\n", Status{IsRepo: true, Branch: "main"}},
+		{"detached", "# branch.oid 1111\n# branch.head (detached)\n", Status{IsRepo: true, Branch: "HEAD"}},
+		{"no commits", "# branch.oid (initial)\n# branch.head main\n? a\n", Status{IsRepo: true, Branch: "main", Dirty: 1, Untracked: 1}},
+		{"empty", "", Status{IsRepo: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePorcelainV2(tt.out); got != tt.want {
+				t.Errorf("parsePorcelainV2() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := GetStatus(repo); got.IsRepo {
+		t.Fatalf("GetStatus() outside a repo = %+v", got)
+	}
+
+	run("init", "-q", "-b", "main")
+	write("a.txt", "one\n")
+	run("add", "a.txt")
+	run("commit", "-q", "-m", "init")
+
+	write("a.txt", "one\ntwo\nthree\n")
+	write("b.txt", "new\n")
+	got := GetStatus(repo)
+	if got.Branch != "main" || got.Dirty != 2 || got.Unstaged != 1 || got.Untracked != 1 || got.Additions != 2 {
+		t.Errorf("GetStatus() = %+v", got)
+	}
+
+	sync := GetSyncStatus(repo)
+	if sync.State != StateNoUpstream || sync.Branch != "main" || sync.Dirty != 2 {
+		t.Errorf("GetSyncStatus() = %+v", sync)
+	}
+}
@@ -0,0 +1,122 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree is one working tree of a repository
+type Worktree struct {
+	Path     string
+	Branch   string // Short branch name; empty when detached
+	Head     string // Checked-out commit
+	Main     bool   // The repository's main working tree
+	Detached bool
+	Prunable bool // Directory no longer exists
+}
+
+// ListWorktrees returns the working trees of the repository at repoDir,
+// main working tree first. Bare entries are skipped.
+func ListWorktrees(repoDir string) ([]Worktree, error) {
+	out, err := exec.Command("git", "-C", repoDir, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseWorktrees(string(out)), nil
+}
+
+// parseWorktrees parses `git worktree list --porcelain` output: one block
+// of "key value" lines per worktree, separated by blank lines.
+func parseWorktrees(out string) []Worktree {
+	var worktrees []Worktree
+	for i, block := range strings.Split(strings.TrimSpace(out), "\n\n") {
+		var wt Worktree
+		bare := false
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				wt.Path = value
+			case "HEAD":
+				wt.Head = value
+			case "branch":
+				wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "detached":
+				wt.Detached = true
+			case "prunable":
+				wt.Prunable = true
+			case "bare":
+				bare = true
+			}
+		}
+		if wt.Path == "" || bare {
+			continue
+		}
+		wt.Main = i == 0
+		worktrees = append(worktrees, wt)
+	}
+	return worktrees
+}
+
+// AddWorktree creates a worktree at path with branch checked out. The
+// branch is created from HEAD unless it already exists.
+func AddWorktree(repoDir, path, branch string) error {
+	args := []string{"-C", repoDir, "worktree", "add"}
+	if branchExists(repoDir, branch) {
+		args = append(args, path, branch)
+	} else {
+		args = append(args, "-b", branch, path)
+	}
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RemoveWorktree removes a linked worktree. Fails, like git does, when the
+// worktree has uncommitted changes. The branch is kept.
+func RemoveWorktree(repoDir, path string) error {
+	out, err := exec.Command("git", "-C", repoDir, "worktree", "remove", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// branchExists reports whether a local branch exists.
+func branchExists(repoDir, branch string) bool {
+	return exec.Command("git", "-C", repoDir, "show-ref", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+}
+
+// IsLinkedWorktree reports whether dir is a linked (non-main) worktree:
+// its .git is a file pointing into the main repo's .git/worktrees.
+func IsLinkedWorktree(dir string) bool {
+	_, err := MainWorktree(dir)
+	return err == nil
+}
+
+// MainWorktree returns the main working tree of the linked worktree at
+// dir, read from its .git file ("gitdir: <main>/.git/worktrees/<name>").
+func MainWorktree(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return "", err // also a directory: main worktree or plain repo
+	}
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return "", fmt.Errorf("%s: malformed .git file", dir)
+	}
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(dir, gitdir)
+	}
+	// <main>/.git/worktrees/<name>; submodules point into .git/modules
+	worktreesDir := filepath.Dir(filepath.Clean(gitdir))
+	if filepath.Base(worktreesDir) != "worktrees" {
+		return "", fmt.Errorf("%s: not a linked worktree", dir)
+	}
+	return filepath.Dir(filepath.Dir(worktreesDir)), nil
+}
@@ -0,0 +1,132 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseWorktrees(t *testing.T) {
+	out := `worktree /repos/api
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /repos/api@feat-login
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feat/login
+
+worktree /repos/api@detached
+HEAD 3333333333333333333333333333333333333333
+detached
+
+worktree /tmp/gone
+HEAD 4444444444444444444444444444444444444444
+branch refs/heads/old
+prunable gitdir file points to non-existent location
+`
+	got := parseWorktrees(out)
+	if len(got) != 4 {
+		t.Fatalf("parseWorktrees() returned %d worktrees, want 4", len(got))
+	}
+	if !got[0].Main || got[0].Branch != "main" || got[0].Path != "/repos/api" {
+		t.Errorf("main worktree = %+v", got[0])
+	}
+	if got[1].Main || got[1].Branch != "feat/login" {
+		t.Errorf("linked worktree = %+v, want branch feat/login", got[1])
+	}
+	if !got[2].Detached || got[2].Branch != "" {
+		t.Errorf("detached worktree = %+v", got[2])
+	}
+	if !got[3].Prunable {
+		t.Errorf("prunable worktree = %+v", got[3])
+	}
+}
+
+func TestParseWorktreesSkipsBare(t *testing.T) {
+	out := "worktree /repos/api.git\nbare\n\nworktree /repos/api-main\nHEAD 1111\nbranch refs/heads/main\n"
+	got := parseWorktrees(out)
+	if len(got) != 1 || got[0].Path != "/repos/api-main" {
+		t.Errorf("parseWorktrees() = %+v, want only the non-bare worktree", got)
+	}
+}
+
+func TestMainWorktree(t *testing.T) {
+	tmp := t.TempDir()
+	main := filepath.Join(tmp, "api")
+	linked := filepath.Join(tmp, "api@feat")
+	sub := filepath.Join(main, "vendor", "lib")
+	for _, dir := range []string{filepath.Join(main, ".git", "worktrees", "api@feat"), linked, sub} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeGitFile(t, linked, "gitdir: "+filepath.Join(main, ".git", "worktrees", "api@feat"))
+	writeGitFile(t, sub, "gitdir: ../../.git/modules/lib")
+
+	if got, err := MainWorktree(linked); err != nil || got != main {
+		t.Errorf("MainWorktree(linked) = %q, %v; want %q", got, err, main)
+	}
+	if IsLinkedWorktree(main) {
+		t.Error("main worktree (.git dir) reported as linked")
+	}
+	if IsLinkedWorktree(sub) {
+		t.Error("submodule reported as linked worktree")
+	}
+}
+
+func TestAddListRemoveWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tmp := t.TempDir()
+	repo := filepath.Join(tmp, "api")
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run("init", "-q", "-b", "main")
+	run("commit", "-q", "--allow-empty", "-m", "init")
+
+	path := filepath.Join(tmp, "api@feat-x")
+	if err := AddWorktree(repo, path, "feat/x"); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+
+	worktrees, err := ListWorktrees(repo)
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+	if len(worktrees) != 2 || worktrees[1].Branch != "feat/x" {
+		t.Fatalf("ListWorktrees() = %+v, want main + feat/x", worktrees)
+	}
+	if !IsLinkedWorktree(path) {
+		t.Error("new worktree not reported as linked")
+	}
+
+	if err := RemoveWorktree(repo, path); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("worktree directory should be gone")
+	}
+
+	// Re-adding checks out the now-existing branch instead of creating it
+	if err := AddWorktree(repo, path, "feat/x"); err != nil {
+		t.Errorf("AddWorktree() with existing branch error = %v", err)
+	}
+}
+
+func writeGitFile(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ".git"), []byte(content+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
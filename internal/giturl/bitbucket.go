@@ -0,0 +1,79 @@
+package giturl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// bitbucketSSHPort is the default SSH port of Bitbucket Server.
+const bitbucketSSHPort = 7999
+
+// bitbucket lists repos through the Bitbucket Server (Data Center) REST
+// API. Repo paths are the lowercase project key and the repo slug, as in
+// its clone URLs; personal repos have a "~user" project.
+type bitbucket struct{ client }
+
+func (p *bitbucket) CloneURL(path string) string {
+	return fmt.Sprintf("ssh://git@%s:%d/%s.git", p.host, bitbucketSSHPort, path)
+}
+
+func (p *bitbucket) WebURL(path string) string {
+	project, slug, _ := strings.Cut(path, "/")
+	if user, ok := strings.CutPrefix(project, "~"); ok {
+		return fmt.Sprintf("https://%s/users/%s/repos/%s/browse", p.host, user, slug)
+	}
+	return fmt.Sprintf("https://%s/projects/%s/repos/%s/browse", p.host, strings.ToUpper(project), slug)
+}
+
+type bitbucketLink struct {
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+// ListRepos lists the repos the user has read access to.
+func (p *bitbucket) ListRepos(ctx context.Context) ([]Repo, error) {
+	const perPage = 100
+	var repos []Repo
+	start := 0
+	for range maxPages {
+		var page struct {
+			Values []struct {
+				Slug    string `json:"slug"`
+				Project struct {
+					Key string `json:"key"`
+				} `json:"project"`
+				Links struct {
+					Clone []bitbucketLink `json:"clone"`
+					Self  []bitbucketLink `json:"self"`
+				} `json:"links"`
+			} `json:"values"`
+			IsLastPage    bool `json:"isLastPage"`
+			NextPageStart int  `json:"nextPageStart"`
+		}
+		if err := p.get(ctx, fmt.Sprintf("/repos?limit=%d&start=%d", perPage, start), &page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Values {
+			path := strings.ToLower(r.Project.Key) + "/" + r.Slug
+			var cloneURL, webURL string
+			for _, l := range r.Links.Clone {
+				if l.Name == "ssh" {
+					cloneURL = l.Href
+					if parsed, err := ParseGitURL(l.Href); err == nil {
+						path = parsed.Path
+					}
+				}
+			}
+			if len(r.Links.Self) > 0 {
+				webURL = r.Links.Self[0].Href
+			}
+			repos = append(repos, p.repo(path, cloneURL, webURL, p))
+		}
+		if page.IsLastPage || len(page.Values) == 0 {
+			break
+		}
+		start = page.NextPageStart
+	}
+	return repos, nil
+}
@@ -0,0 +1,201 @@
+package giturl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Progress is a clone's progress, parsed from git's --progress output.
+type Progress struct {
+	Phase   string // "Receiving objects", "Resolving deltas", ...
+	Percent int    // Of the phase
+	Done    int    // Objects, deltas or files done in the phase
+	Total   int
+}
+
+// clonePhases spreads the phases of a clone over the whole: receiving
+// takes by far the longest, the server's phases are quick.
+var clonePhases = map[string][2]int{
+	"Enumerating objects": {0, 2},
+	"Counting objects":    {2, 4},
+	"Compressing objects": {4, 6},
+	"Receiving objects":   {6, 85},
+	"Resolving deltas":    {85, 95},
+	"Updating files":      {95, 100},
+}
+
+// Overall estimates the percent of the whole clone done.
+func (p Progress) Overall() int {
+	span, ok := clonePhases[p.Phase]
+	if !ok {
+		return p.Percent
+	}
+	return span[0] + (span[1]-span[0])*p.Percent/100
+}
+
+// progressRe matches a progress line: "Receiving objects:  45% (450/1000), 1.2 MiB | 3 MiB/s".
+var progressRe = regexp.MustCompile(`^(?:remote: )?([A-Z][a-z]+(?: [a-z]+)*):\s+(\d+)% \((\d+)/(\d+)\)`)
+
+// parseProgress parses one line of git's progress output.
+func parseProgress(line string) (Progress, bool) {
+	m := progressRe.FindStringSubmatch(line)
+	if m == nil {
+		return Progress{}, false
+	}
+	percent, _ := strconv.Atoi(m[2])
+	done, _ := strconv.Atoi(m[3])
+	total, _ := strconv.Atoi(m[4])
+	return Progress{Phase: m[1], Percent: percent, Done: done, Total: total}, true
+}
+
+// scanProgressLines splits git's stderr into lines: progress updates end
+// in \r, other messages in \n.
+func scanProgressLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// CloneOptions trims what a clone fetches. The zero value is a full clone.
+type CloneOptions struct {
+	Depth             int      // History depth (--depth); 0 = all of it
+	Filter            string   // Partial clone filter (--filter), e.g. "blob:none"
+	Branch            string   // Branch to check out instead of the default
+	RecurseSubmodules bool     // Clone submodules too
+	Sparse            bool     // Sparse checkout: top-level files and SparsePaths
+	SparsePaths       []string // Directories checked out in sparse cone mode
+}
+
+// args returns the git clone flags for the options.
+func (o CloneOptions) args() []string {
+	var args []string
+	if o.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(o.Depth))
+	}
+	if o.Filter != "" {
+		args = append(args, "--filter="+o.Filter)
+	}
+	if o.Branch != "" {
+		args = append(args, "--branch", o.Branch)
+	}
+	if o.RecurseSubmodules {
+		args = append(args, "--recurse-submodules")
+		if o.Depth > 0 {
+			args = append(args, "--shallow-submodules")
+		}
+	}
+	if o.Sparse || len(o.SparsePaths) > 0 {
+		args = append(args, "--sparse")
+	}
+	return args
+}
+
+// expandCloneURL turns a bare owner/repo into a GitHub SSH URL.
+func expandCloneURL(gitURL string) string {
+	if !strings.Contains(gitURL, "@") && !strings.Contains(gitURL, "://") && strings.Contains(gitURL, "/") {
+		return fmt.Sprintf("git@github.com:%s.git", gitURL)
+	}
+	return gitURL
+}
+
+// CloneRepo clones a repository to the specified destination path.
+// gitURL should be a full clone URL (ssh://, git@, https://, or owner/repo).
+// If given owner/repo, it defaults to git@github.com:owner/repo.git.
+func CloneRepo(gitURL, destPath string, opts CloneOptions) error {
+	return CloneRepoContext(context.Background(), gitURL, destPath, opts, nil)
+}
+
+// CloneRepoContext clones like CloneRepo, passing each progress update git
+// reports to onProgress (if not nil). Canceling ctx kills git; a clone that
+// fails or is canceled leaves no directory behind.
+func CloneRepoContext(ctx context.Context, gitURL, destPath string, opts CloneOptions, onProgress func(Progress)) error {
+	// A URL starting with a dash would be taken for a git option
+	if strings.HasPrefix(gitURL, "-") {
+		return fmt.Errorf("invalid clone URL: %s", gitURL)
+	}
+
+	// Ensure parent directory exists
+	parentDir := filepath.Dir(destPath)
+	if err := os.MkdirAll(parentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", parentDir, err)
+	}
+	_, statErr := os.Stat(destPath)
+	existed := statErr == nil
+
+	gitURL = expandCloneURL(gitURL)
+	args := append([]string{"clone", "--progress"}, opts.args()...)
+	cmd := exec.CommandContext(ctx, "git", append(args, "--", gitURL, destPath)...)
+	// Kill the whole process group: git leaves the transfer to helpers
+	// (git-remote-https, index-pack) that would keep running
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = 5 * time.Second
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to clone %s: %w", gitURL, err)
+	}
+
+	// Keep the last messages that aren't progress, for the error
+	var messages []string
+	scanner := bufio.NewScanner(stderr)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if p, ok := parseProgress(line); ok {
+			if onProgress != nil {
+				onProgress(p)
+			}
+			continue
+		}
+		if line != "" && !strings.HasPrefix(line, "Cloning into") {
+			messages = append(messages[max(0, len(messages)-4):], line)
+		}
+	}
+	_, _ = io.Copy(io.Discard, stderr)
+
+	err = cmd.Wait()
+	if err == nil {
+		return setSparsePaths(ctx, destPath, opts.SparsePaths)
+	}
+	if !existed {
+		_ = os.RemoveAll(destPath)
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("clone of %s canceled: %w", gitURL, ctx.Err())
+	}
+	return fmt.Errorf("failed to clone %s: %w\n%s", gitURL, err, strings.Join(messages, "\n"))
+}
+
+// setSparsePaths checks out directories of a sparse clone, beyond the
+// top-level files --sparse leaves.
+func setSparsePaths(ctx context.Context, repoPath string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	args := append([]string{"-C", repoPath, "sparse-checkout", "set", "--cone"}, paths...)
+	if out, err := exec.CommandContext(ctx, "git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("cloned but failed to set sparse-checkout paths: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package giturl
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		line string
+		want Progress
+		ok   bool
+	}{
+		{"Receiving objects:  45% (450/1000), 1.20 MiB | 3.00 MiB/s", Progress{"Receiving objects", 45, 450, 1000}, true},
+		{"remote: Compressing objects: 100% (12/12), done.", Progress{"Compressing objects", 100, 12, 12}, true},
+		{"Resolving deltas:   3% (1/30)", Progress{"Resolving deltas", 3, 1, 30}, true},
+		{"Updating files: 100% (2048/2048), done.", Progress{"Updating files", 100, 2048, 2048}, true},
+		{"Cloning into 'repo'...", Progress{}, false},
+		{"fatal: repository 'x' not found", Progress{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseProgress(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseProgress(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestProgressOverall(t *testing.T) {
+	tests := []struct {
+		p    Progress
+		want int
+	}{
+		{Progress{Phase: "Counting objects", Percent: 100}, 4},
+		{Progress{Phase: "Receiving objects", Percent: 0}, 6},
+		{Progress{Phase: "Receiving objects", Percent: 50}, 45},
+		{Progress{Phase: "Resolving deltas", Percent: 100}, 95},
+		{Progress{Phase: "Updating files", Percent: 100}, 100},
+		{Progress{Phase: "Filtering content", Percent: 30}, 30},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Overall(); got != tt.want {
+			t.Errorf("%+v.Overall() = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestScanProgressLines(t *testing.T) {
+	out := "Cloning into 'r'...\nReceiving objects:  50% (1/2)\rReceiving objects: 100% (2/2), done.\nfatal: oops"
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Split(scanProgressLines)
+	var got []string
+	for scanner.Scan() {
+		got = append(got, scanner.Text())
+	}
+	want := []string{"Cloning into 'r'...", "Receiving objects:  50% (1/2)", "Receiving objects: 100% (2/2), done.", "fatal: oops"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+}
+
+// sourceRepo creates a repo with one commit to clone from.
+func sourceRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestCloneRepoContext(t *testing.T) {
+	src := sourceRepo(t)
+	dest := filepath.Join(t.TempDir(), "owner", "repo")
+
+	var phases []string
+	err := CloneRepoContext(context.Background(), "file://"+src, dest, CloneOptions{}, func(p Progress) {
+		phases = append(phases, p.Phase)
+	})
+	if err != nil {
+		t.Fatalf("CloneRepoContext() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".git")); err != nil {
+		t.Errorf("clone has no .git: %v", err)
+	}
+	if len(phases) == 0 {
+		t.Error("no progress reported")
+	}
+}
+
+func TestCloneRepoContext_cleansUp(t *testing.T) {
+	src := sourceRepo(t)
+	base := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dest := filepath.Join(base, "canceled")
+	err := CloneRepoContext(ctx, "file://"+src, dest, CloneOptions{}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled clone: err = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("canceled clone left %s behind", dest)
+	}
+
+	dest = filepath.Join(base, "option")
+	err = CloneRepoContext(context.Background(), "--upload-pack=touch "+filepath.Join(base, "pwned"), dest, CloneOptions{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid clone URL") {
+		t.Errorf("option-like URL: err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "pwned")); !os.IsNotExist(err) {
+		t.Error("option-like URL ran a command")
+	}
+
+	dest = filepath.Join(base, "missing")
+	err = CloneRepoContext(context.Background(), "file://"+filepath.Join(base, "nope"), dest, CloneOptions{}, nil)
+	if err == nil || errors.Is(err, context.Canceled) {
+		t.Errorf("failed clone: err = %v", err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("failed clone left %s behind", dest)
+	}
+}
+
+func TestCloneOptionsArgs(t *testing.T) {
+	tests := []struct {
+		opts CloneOptions
+		want []string
+	}{
+		{CloneOptions{}, nil},
+		{CloneOptions{Depth: 1}, []string{"--depth", "1"}},
+		{CloneOptions{Filter: "blob:none", Branch: "dev"}, []string{"--filter=blob:none", "--branch", "dev"}},
+		{CloneOptions{Depth: 5, RecurseSubmodules: true}, []string{"--depth", "5", "--recurse-submodules", "--shallow-submodules"}},
+		{CloneOptions{Sparse: true}, []string{"--sparse"}},
+		{CloneOptions{SparsePaths: []string{"web"}}, []string{"--sparse"}},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.args(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v.args() = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestCloneRepoContext_sparse(t *testing.T) {
+	src := sourceRepo(t)
+	for _, f := range []string{"README", "web/index.html", "api/main.go"} {
+		path := filepath.Join(src, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"add", "."},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "files"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", src}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	dest := filepath.Join(t.TempDir(), "repo")
+	opts := CloneOptions{Depth: 1, Sparse: true, SparsePaths: []string{"web"}}
+	if err := CloneRepoContext(context.Background(), "file://"+src, dest, opts, nil); err != nil {
+		t.Fatalf("CloneRepoContext() error: %v", err)
+	}
+	for f, want := range map[string]bool{"README": true, "web/index.html": true, "api/main.go": false} {
+		_, err := os.Stat(filepath.Join(dest, f))
+		if got := err == nil; got != want {
+			t.Errorf("%s checked out = %v, want %v", f, got, want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package giturl
+
+import (
+	"context"
+	"fmt"
+)
+
+// gitea lists repos through the Gitea API, which Forgejo shares.
+type gitea struct{ client }
+
+func (p *gitea) CloneURL(path string) string { return p.sshCloneURL(path) }
+func (p *gitea) WebURL(path string) string   { return p.webURL(path) }
+
+// ListRepos lists the repos the user owns or can access through an org.
+func (p *gitea) ListRepos(ctx context.Context) ([]Repo, error) {
+	const perPage = 50 // The default maximum page size
+	var repos []Repo
+	for page := 1; page <= maxPages; page++ {
+		var batch []struct {
+			FullName string `json:"full_name"`
+			SSHURL   string `json:"ssh_url"`
+			HTMLURL  string `json:"html_url"`
+		}
+		if err := p.get(ctx, fmt.Sprintf("/user/repos?limit=%d&page=%d", perPage, page), &batch); err != nil {
+			return nil, err
+		}
+		for _, r := range batch {
+			repos = append(repos, p.repo(r.FullName, r.SSHURL, r.HTMLURL, p))
+		}
+		if len(batch) < perPage {
+			break
+		}
+	}
+	return repos, nil
+}
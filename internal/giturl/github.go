@@ -1,10 +1,9 @@
 package giturl
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -129,44 +128,35 @@ func CheckGhCli() error {
 	return nil
 }
 
-// FetchAvailableRepos returns repos the user has access to (owner/repo format)
-func FetchAvailableRepos() ([]string, error) {
-	out, err := exec.Command("gh", "api", "/user/repos", "--paginate", "--jq", ".[].full_name").Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	if len(lines) == 0 || (len(lines) == 1 && lines[0] == "") {
-		return []string{}, nil
-	}
-
-	return lines, nil
-}
-
-// CloneRepo clones a repository to the specified destination path.
-// gitURL should be a full clone URL (ssh://, git@, https://, or owner/repo).
-// If given owner/repo, it defaults to git@github.com:owner/repo.git.
-func CloneRepo(gitURL, destPath string) error {
-	// Ensure parent directory exists
-	parentDir := filepath.Dir(destPath)
-	if err := os.MkdirAll(parentDir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", parentDir, err)
-	}
-
-	// If it's just owner/repo (no host), default to GitHub SSH
-	if !strings.Contains(gitURL, "@") && !strings.Contains(gitURL, "://") && strings.Contains(gitURL, "/") {
-		gitURL = fmt.Sprintf("git@github.com:%s.git", gitURL)
-	}
-
-	// Clone the repository
-	cmd := exec.Command("git", "clone", gitURL, destPath)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to clone %s: %w\n%s", gitURL, err, string(out))
+// github lists repos through the GitHub REST API (github.com and
+// Enterprise Server).
+type github struct{ client }
+
+func (p *github) CloneURL(path string) string { return p.sshCloneURL(path) }
+func (p *github) WebURL(path string) string   { return p.webURL(path) }
+
+// ListRepos lists the repos the user owns, collaborates on or can see
+// through an org.
+func (p *github) ListRepos(ctx context.Context) ([]Repo, error) {
+	const perPage = 100
+	var repos []Repo
+	for page := 1; page <= maxPages; page++ {
+		var batch []struct {
+			FullName string `json:"full_name"`
+			SSHURL   string `json:"ssh_url"`
+			HTMLURL  string `json:"html_url"`
+		}
+		if err := p.get(ctx, fmt.Sprintf("/user/repos?per_page=%d&page=%d", perPage, page), &batch); err != nil {
+			return nil, err
+		}
+		for _, r := range batch {
+			repos = append(repos, p.repo(r.FullName, r.SSHURL, r.HTMLURL, p))
+		}
+		if len(batch) < perPage {
+			break
+		}
 	}
-
-	return nil
+	return repos, nil
 }
 
 // ResolveOwnerRepo normalizes a repo identifier to owner/repo format.
@@ -0,0 +1,36 @@
+package giturl
+
+import (
+	"context"
+	"fmt"
+)
+
+// gitlab lists repos through the GitLab REST API (v4).
+type gitlab struct{ client }
+
+func (p *gitlab) CloneURL(path string) string { return p.sshCloneURL(path) }
+func (p *gitlab) WebURL(path string) string   { return p.webURL(path) }
+
+// ListRepos lists the projects the user is a member of.
+func (p *gitlab) ListRepos(ctx context.Context) ([]Repo, error) {
+	const perPage = 100
+	var repos []Repo
+	for page := 1; page <= maxPages; page++ {
+		var batch []struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+			SSHURL            string `json:"ssh_url_to_repo"`
+			WebURL            string `json:"web_url"`
+		}
+		path := fmt.Sprintf("/projects?membership=true&simple=true&archived=false&per_page=%d&page=%d", perPage, page)
+		if err := p.get(ctx, path, &batch); err != nil {
+			return nil, err
+		}
+		for _, r := range batch {
+			repos = append(repos, p.repo(r.PathWithNamespace, r.SSHURL, r.WebURL, p))
+		}
+		if len(batch) < perPage {
+			break
+		}
+	}
+	return repos, nil
+}
@@ -0,0 +1,241 @@
+package giturl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider kinds (repo_providers type)
+const (
+	KindGitHub    = "github"
+	KindGitLab    = "gitlab"
+	KindGitea     = "gitea"     // Also Forgejo (Codeberg)
+	KindBitbucket = "bitbucket" // Bitbucket Server / Data Center
+)
+
+// Kinds lists the provider kinds.
+var Kinds = []string{KindGitHub, KindGitLab, KindGitea, KindBitbucket}
+
+// Provider is a git host whose API lists the repos the user has access to.
+type Provider interface {
+	// Host is the hostname repos are cloned from
+	Host() string
+	// ListRepos returns every repo the token can see
+	ListRepos(ctx context.Context) ([]Repo, error)
+	// CloneURL returns the SSH clone URL of a repo path
+	CloneURL(path string) string
+	// WebURL returns the browser URL of a repo path
+	WebURL(path string) string
+}
+
+// Repo is a repository listed by a provider.
+type Repo struct {
+	Host     string
+	Path     string // owner/repo, group/subgroup/repo, project/repo
+	CloneURL string
+	WebURL   string
+}
+
+// String names a repo with its host: "gitlab.com/group/repo".
+func (r Repo) String() string {
+	return r.Host + "/" + r.Path
+}
+
+// Dir returns the directory a repo is cloned to under a project dir, by
+// the same rules as ResolveRepoDir.
+func (r Repo) Dir(providers map[string]string) string {
+	return ResolveRepoDir(GitURL{Host: r.Host, Path: r.Path}, providers)
+}
+
+// ProviderOptions configures a provider. Only Kind is required for
+// github.com and gitlab.com; self-hosted instances need Host.
+type ProviderOptions struct {
+	Kind   string
+	Host   string
+	APIURL string // API root, default derived from Host
+	Token  string // Default from DefaultToken
+}
+
+// NewProvider returns the provider for opts.
+func NewProvider(opts ProviderOptions) (Provider, error) {
+	if !slices.Contains(Kinds, opts.Kind) {
+		return nil, fmt.Errorf("unknown provider type %q (want one of %s)", opts.Kind, strings.Join(Kinds, ", "))
+	}
+	if opts.Host == "" {
+		opts.Host = publicHosts[opts.Kind]
+		if opts.Host == "" {
+			return nil, fmt.Errorf("%s provider needs a host", opts.Kind)
+		}
+	}
+	if opts.Token == "" {
+		opts.Token = DefaultToken(opts.Kind, opts.Host)
+	}
+	c := client{host: opts.Host, apiURL: strings.TrimSuffix(opts.APIURL, "/"), token: opts.Token}
+
+	switch opts.Kind {
+	case KindGitHub:
+		if c.apiURL == "" {
+			c.apiURL = "https://api.github.com"
+			if c.host != "github.com" {
+				c.apiURL = "https://" + c.host + "/api/v3" // Enterprise Server
+			}
+		}
+		c.auth = func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+c.token) }
+		return &github{c}, nil
+	case KindGitLab:
+		if c.apiURL == "" {
+			c.apiURL = "https://" + c.host + "/api/v4"
+		}
+		c.auth = func(r *http.Request) { r.Header.Set("PRIVATE-TOKEN", c.token) }
+		return &gitlab{c}, nil
+	case KindGitea:
+		if c.apiURL == "" {
+			c.apiURL = "https://" + c.host + "/api/v1"
+		}
+		c.auth = func(r *http.Request) { r.Header.Set("Authorization", "token "+c.token) }
+		return &gitea{c}, nil
+	case KindBitbucket:
+		if c.apiURL == "" {
+			c.apiURL = "https://" + c.host + "/rest/api/1.0"
+		}
+		c.auth = func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+c.token) }
+		return &bitbucket{c}, nil
+	}
+	return nil, fmt.Errorf("unknown provider type %q", opts.Kind)
+}
+
+// publicHosts are the hosts of the kinds with a public instance.
+var publicHosts = map[string]string{
+	KindGitHub: "github.com",
+	KindGitLab: "gitlab.com",
+}
+
+// tokenEnv are the environment variables the public instance's token is
+// read from when the config has none, in order. They're never sent to
+// another host: a self-hosted instance needs its token configured.
+var tokenEnv = map[string][]string{
+	KindGitHub: {"GITHUB_TOKEN", "GH_TOKEN"},
+	KindGitLab: {"GITLAB_TOKEN"},
+}
+
+// DefaultToken returns the token for a host from the environment when it's
+// the kind's public instance, or for GitHub from the gh CLI's login to
+// that host.
+func DefaultToken(kind, host string) string {
+	if host == publicHosts[kind] {
+		for _, env := range tokenEnv[kind] {
+			if token := os.Getenv(env); token != "" {
+				return token
+			}
+		}
+	}
+	if kind == KindGitHub {
+		if out, err := exec.Command("gh", "auth", "token", "--hostname", host).Output(); err == nil {
+			return strings.TrimSpace(string(out))
+		}
+	}
+	return ""
+}
+
+// ListAll lists the repos of all providers in parallel, sorted by host and
+// path. A provider that fails is reported in errs; the others still list.
+func ListAll(ctx context.Context, providers []Provider) (repos []Repo, errs []error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, p := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			listed, err := p.ListRepos(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", p.Host(), err))
+				return
+			}
+			repos = append(repos, listed...)
+		}()
+	}
+	wg.Wait()
+
+	slices.SortFunc(repos, func(a, b Repo) int { return strings.Compare(a.String(), b.String()) })
+	return repos, errs
+}
+
+// maxPages bounds pagination, in case an API keeps returning full pages.
+const maxPages = 100
+
+// client is the HTTP side shared by the providers.
+type client struct {
+	host   string
+	apiURL string
+	token  string
+	auth   func(*http.Request)
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Host returns the provider's hostname.
+func (c client) Host() string {
+	return c.host
+}
+
+// get fetches an API path and decodes the JSON response into v.
+func (c client) get(ctx context.Context, path string, v any) error {
+	if c.token == "" {
+		return fmt.Errorf("no API token (see repo_providers in the README)")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	c.auth(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("%s: %s %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", path, err)
+	}
+	return nil
+}
+
+// sshCloneURL is the SCP-like clone URL GitHub, GitLab and Gitea use.
+func (c client) sshCloneURL(path string) string {
+	return fmt.Sprintf("git@%s:%s.git", c.host, path)
+}
+
+// webURL is the browser URL GitHub, GitLab and Gitea use.
+func (c client) webURL(path string) string {
+	return fmt.Sprintf("https://%s/%s", c.host, path)
+}
+
+// repo builds a listed repo, filling in URLs the API left out. A clone
+// URL pointing at another host than the provider's is not trusted, and
+// derived from the path instead.
+func (c client) repo(path, cloneURL, webURL string, p Provider) Repo {
+	if u, err := ParseGitURL(cloneURL); err != nil || u.Host != c.host {
+		cloneURL = p.CloneURL(path)
+	}
+	if webURL == "" {
+		webURL = p.WebURL(path)
+	}
+	return Repo{Host: c.host, Path: path, CloneURL: cloneURL, WebURL: webURL}
+}
@@ -0,0 +1,294 @@
+package giturl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubAPI serves pages of a provider API: pages maps "path?query" to the
+// JSON body. Requests without the expected auth header get a 401.
+func stubAPI(t *testing.T, header, value string, pages map[string]string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(header) != value {
+			http.Error(w, `{"message":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		body, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			t.Errorf("unexpected request %s", r.URL.RequestURI())
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// fullPage returns a JSON array of n repos in a provider's format, to fill
+// a page so the next one is requested.
+func fullPage(n int, format string) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(format, i)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func listRepos(t *testing.T, p Provider) []Repo {
+	t.Helper()
+	repos, err := p.ListRepos(context.Background())
+	if err != nil {
+		t.Fatalf("ListRepos() error: %v", err)
+	}
+	return repos
+}
+
+func TestGitHubListRepos(t *testing.T) {
+	api := stubAPI(t, "Authorization", "Bearer secret", map[string]string{
+		"/user/repos?per_page=100&page=1": fullPage(100, `{"full_name":"org/r%d"}`),
+		"/user/repos?per_page=100&page=2": `[
+			{"full_name":"me/dots","ssh_url":"git@github.com:me/dots.git","html_url":"https://github.com/me/dots"},
+			{"full_name":"me/evil","ssh_url":"--upload-pack=touch /tmp/pwned"},
+			{"full_name":"me/away","ssh_url":"git@evil.example:me/away.git"}
+		]`,
+	})
+	p, err := NewProvider(ProviderOptions{Kind: KindGitHub, APIURL: api, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos := listRepos(t, p)
+	if len(repos) != 103 {
+		t.Fatalf("got %d repos, want 103", len(repos))
+	}
+	want := Repo{Host: "github.com", Path: "me/dots", CloneURL: "git@github.com:me/dots.git", WebURL: "https://github.com/me/dots"}
+	if repos[100] != want {
+		t.Errorf("repos[100] = %+v, want %+v", repos[100], want)
+	}
+	// URLs the API leaves out are derived from the path
+	if got := repos[0].CloneURL; got != "git@github.com:org/r0.git" {
+		t.Errorf("derived CloneURL = %q", got)
+	}
+	// So are URLs that aren't the provider's host
+	for _, r := range repos[101:] {
+		if want := "git@github.com:" + r.Path + ".git"; r.CloneURL != want {
+			t.Errorf("%s CloneURL = %q, want %q", r.Path, r.CloneURL, want)
+		}
+	}
+}
+
+func TestGitLabListRepos(t *testing.T) {
+	api := stubAPI(t, "PRIVATE-TOKEN", "secret", map[string]string{
+		"/projects?membership=true&simple=true&archived=false&per_page=100&page=1": `[
+			{"path_with_namespace":"team/sub/api","ssh_url_to_repo":"git@gitlab.corp.dev:team/sub/api.git","web_url":"https://gitlab.corp.dev/team/sub/api"}
+		]`,
+	})
+	p, err := NewProvider(ProviderOptions{Kind: KindGitLab, Host: "gitlab.corp.dev", APIURL: api, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Repo{{
+		Host:     "gitlab.corp.dev",
+		Path:     "team/sub/api",
+		CloneURL: "git@gitlab.corp.dev:team/sub/api.git",
+		WebURL:   "https://gitlab.corp.dev/team/sub/api",
+	}}
+	if got := listRepos(t, p); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRepos() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGiteaListRepos(t *testing.T) {
+	api := stubAPI(t, "Authorization", "token secret", map[string]string{
+		"/user/repos?limit=50&page=1": fullPage(50, `{"full_name":"org/r%d"}`),
+		"/user/repos?limit=50&page=2": `[]`,
+	})
+	p, err := NewProvider(ProviderOptions{Kind: KindGitea, Host: "git.home.lan", APIURL: api, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos := listRepos(t, p)
+	if len(repos) != 50 {
+		t.Fatalf("got %d repos, want 50", len(repos))
+	}
+	want := Repo{Host: "git.home.lan", Path: "org/r7", CloneURL: "git@git.home.lan:org/r7.git", WebURL: "https://git.home.lan/org/r7"}
+	if repos[7] != want {
+		t.Errorf("repos[7] = %+v, want %+v", repos[7], want)
+	}
+}
+
+func TestBitbucketListRepos(t *testing.T) {
+	api := stubAPI(t, "Authorization", "Bearer secret", map[string]string{
+		"/repos?limit=100&start=0": `{"isLastPage":false,"nextPageStart":1,"values":[
+			{"slug":"core","project":{"key":"PLAT"},"links":{
+				"clone":[{"href":"https://git.corp.dev/scm/plat/core.git","name":"http"},{"href":"ssh://git@git.corp.dev:7999/plat/core.git","name":"ssh"}],
+				"self":[{"href":"https://git.corp.dev/projects/PLAT/repos/core/browse"}]}}
+		]}`,
+		"/repos?limit=100&start=1": `{"isLastPage":true,"values":[
+			{"slug":"notes","project":{"key":"~ALICE"},"links":{}}
+		]}`,
+	})
+	p, err := NewProvider(ProviderOptions{Kind: KindBitbucket, Host: "git.corp.dev", APIURL: api, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Repo{
+		{
+			Host:     "git.corp.dev",
+			Path:     "plat/core",
+			CloneURL: "ssh://git@git.corp.dev:7999/plat/core.git",
+			WebURL:   "https://git.corp.dev/projects/PLAT/repos/core/browse",
+		},
+		{
+			Host:     "git.corp.dev",
+			Path:     "~alice/notes",
+			CloneURL: "ssh://git@git.corp.dev:7999/~alice/notes.git",
+			WebURL:   "https://git.corp.dev/users/alice/repos/notes/browse",
+		},
+	}
+	if got := listRepos(t, p); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRepos() = %+v, want %+v", got, want)
+	}
+}
+
+func TestListRepos_errors(t *testing.T) {
+	api := stubAPI(t, "Authorization", "Bearer secret", nil)
+
+	wrong, _ := NewProvider(ProviderOptions{Kind: KindGitHub, APIURL: api, Token: "wrong"})
+	if _, err := wrong.ListRepos(context.Background()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("wrong token: err = %v, want a 401", err)
+	}
+
+	none, _ := NewProvider(ProviderOptions{Kind: KindGitea, Host: "git.home.lan", APIURL: api})
+	if _, err := none.ListRepos(context.Background()); err == nil || !strings.Contains(err.Error(), "no API token") {
+		t.Errorf("no token: err = %v", err)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "from-env")
+	t.Setenv("GITHUB_TOKEN", "from-env")
+
+	tests := []struct {
+		opts    ProviderOptions
+		wantAPI string
+		wantErr bool
+	}{
+		{ProviderOptions{Kind: KindGitHub, Token: "t"}, "https://api.github.com", false},
+		{ProviderOptions{Kind: KindGitHub, Host: "ghe.corp.dev", Token: "t"}, "https://ghe.corp.dev/api/v3", false},
+		{ProviderOptions{Kind: KindGitLab}, "https://gitlab.com/api/v4", false},
+		{ProviderOptions{Kind: KindGitea, Host: "codeberg.org", Token: "t"}, "https://codeberg.org/api/v1", false},
+		{ProviderOptions{Kind: KindBitbucket, Host: "git.corp.dev", APIURL: "https://git.corp.dev/bb/rest/api/1.0/", Token: "t"}, "https://git.corp.dev/bb/rest/api/1.0", false},
+		{ProviderOptions{Kind: KindGitea}, "", true},
+		{ProviderOptions{Kind: "svn", Host: "svn.corp.dev"}, "", true},
+	}
+	for _, tt := range tests {
+		p, err := NewProvider(tt.opts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewProvider(%+v) error = %v, wantErr %v", tt.opts, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		c := clientOf(p)
+		if c.apiURL != tt.wantAPI {
+			t.Errorf("NewProvider(%+v) API = %q, want %q", tt.opts, c.apiURL, tt.wantAPI)
+		}
+		if tt.opts.Kind == KindGitLab && c.token != "from-env" {
+			t.Errorf("GitLab token = %q, want it from GITLAB_TOKEN", c.token)
+		}
+	}
+}
+
+func TestDefaultToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "gh-public")
+	t.Setenv("GITLAB_TOKEN", "gl-public")
+	t.Setenv("GITEA_TOKEN", "gitea")
+	t.Setenv("PATH", t.TempDir()) // No gh CLI
+
+	tests := []struct {
+		kind, host, want string
+	}{
+		{KindGitHub, "github.com", "gh-public"},
+		{KindGitLab, "gitlab.com", "gl-public"},
+		{KindGitHub, "ghe.corp.dev", ""},
+		{KindGitLab, "gitlab.corp.dev", ""},
+		{KindGitea, "codeberg.org", ""},
+		{KindGitLab, "github.com", ""},
+	}
+	for _, tt := range tests {
+		if got := DefaultToken(tt.kind, tt.host); got != tt.want {
+			t.Errorf("DefaultToken(%q, %q) = %q, want %q", tt.kind, tt.host, got, tt.want)
+		}
+	}
+}
+
+// clientOf returns the HTTP side of a provider.
+func clientOf(p Provider) client {
+	switch p := p.(type) {
+	case *github:
+		return p.client
+	case *gitlab:
+		return p.client
+	case *gitea:
+		return p.client
+	case *bitbucket:
+		return p.client
+	}
+	return client{}
+}
+
+func TestListAll(t *testing.T) {
+	gh := stubAPI(t, "Authorization", "Bearer a", map[string]string{
+		"/user/repos?per_page=100&page=1": `[{"full_name":"me/web"},{"full_name":"me/api"}]`,
+	})
+	gl := stubAPI(t, "PRIVATE-TOKEN", "b", map[string]string{
+		"/projects?membership=true&simple=true&archived=false&per_page=100&page=1": `[{"path_with_namespace":"team/api"}]`,
+	})
+	down := stubAPI(t, "Authorization", "token c", nil)
+
+	github, _ := NewProvider(ProviderOptions{Kind: KindGitHub, APIURL: gh, Token: "a"})
+	gitlab, _ := NewProvider(ProviderOptions{Kind: KindGitLab, APIURL: gl, Token: "b"})
+	gitea, _ := NewProvider(ProviderOptions{Kind: KindGitea, Host: "git.home.lan", APIURL: down, Token: "wrong"})
+
+	repos, errs := ListAll(context.Background(), []Provider{github, gitlab, gitea})
+	var got []string
+	for _, r := range repos {
+		got = append(got, r.String())
+	}
+	want := []string{"github.com/me/api", "github.com/me/web", "gitlab.com/team/api"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAll() = %v, want %v", got, want)
+	}
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "git.home.lan:") {
+		t.Errorf("errs = %v, want the gitea failure", errs)
+	}
+}
+
+func TestRepoDir(t *testing.T) {
+	providers := map[string]string{"git.corp.dev": "corp"}
+	tests := []struct {
+		repo Repo
+		want string
+	}{
+		{Repo{Host: "github.com", Path: "me/dots"}, "me/dots"},
+		{Repo{Host: "gitlab.com", Path: "team/sub/api"}, "gitlab.com/team/sub/api"},
+		{Repo{Host: "git.corp.dev", Path: "~alice/notes"}, "corp/alice/notes"},
+	}
+	for _, tt := range tests {
+		if got := tt.repo.Dir(providers); got != tt.want {
+			t.Errorf("%s.Dir() = %q, want %q", tt.repo, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package layout
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// Apply shapes a freshly created session: the project's session template
+// if one resolves, otherwise the global layout script. A no-op when
+// layouts are disabled. The error wraps ErrUntrusted when the session was
+// built without the commands of an untrusted .helm.yml.
+func Apply(cfg config.Config, sessionName, dir string) error {
+	if !cfg.EnableLayouts {
+		return nil
+	}
+
+	t, err := Resolve(dir, cfg)
+	if err != nil {
+		return err
+	}
+	if t != nil {
+		if err := ApplyTemplate(sessionName, dir, *t); err != nil {
+			return err
+		}
+		if t.untrusted {
+			return fmt.Errorf("%w: pane commands skipped, run `helm layout trust %s` to allow them", ErrUntrusted, dir)
+		}
+		return nil
+	}
+	return runScript(cfg, sessionName, dir)
+}
+
+// ApplyTemplate builds the template's windows and panes in the session.
+// The session's initial window becomes the first template window; the
+// rest are appended. Targets are tmux window/pane IDs, so base-index and
+// renumber-windows settings don't matter.
+func ApplyTemplate(sessionName, dir string, t Template) error {
+	root := resolveDir(dir, t.Root)
+
+	var focusWindow string
+	for i, w := range t.Windows {
+		windowDir := resolveDir(root, w.Dir)
+
+		var windowID string
+		var err error
+		if i == 0 {
+			windowID, err = tmux.DisplayMessage(sessionName+":^", "#{window_id}")
+			if err == nil && w.Name != "" {
+				err = tmux.RenameWindow(windowID, w.Name)
+			}
+			// The initial pane was started in the session dir; move it
+			if err == nil && windowDir != dir {
+				err = tmux.SendKeys(windowID, "cd "+shellQuote(windowDir)+" && clear", true)
+			}
+		} else {
+			windowID, err = tmux.NewWindow(sessionName+":", w.Name, windowDir)
+		}
+		if err != nil {
+			return fmt.Errorf("window %q: %w", w.Name, err)
+		}
+
+		if err := buildPanes(windowID, windowDir, w); err != nil {
+			return fmt.Errorf("window %q: %w", w.Name, err)
+		}
+		if w.Focus {
+			focusWindow = windowID
+		}
+	}
+
+	if focusWindow == "" {
+		focusWindow = sessionName + ":^"
+	}
+	return tmux.FocusWindow(focusWindow)
+}
+
+// buildPanes splits the window into its panes and starts their commands.
+func buildPanes(windowID, windowDir string, w Window) error {
+	if len(w.Panes) == 0 {
+		return nil
+	}
+
+	firstPane, err := tmux.DisplayMessage(windowID, "#{pane_id}")
+	if err != nil {
+		return err
+	}
+
+	paneIDs := make([]string, len(w.Panes))
+	paneIDs[0] = firstPane
+	for i := 1; i < len(w.Panes); i++ {
+		p := w.Panes[i]
+		id, err := tmux.SplitWindow(paneIDs[i-1], p.Horizontal(), p.Size, resolveDir(windowDir, p.Dir))
+		if err != nil {
+			return fmt.Errorf("pane %d: %w", i+1, err)
+		}
+		paneIDs[i] = id
+	}
+
+	if w.Layout != "" {
+		if err := tmux.SelectLayout(windowID, w.Layout); err != nil {
+			return fmt.Errorf("layout %q: %w", w.Layout, err)
+		}
+	}
+
+	focus := firstPane
+	for i, p := range w.Panes {
+		text := p.Command
+		// The first pane already exists, so a dir of its own needs a cd
+		if i == 0 && p.Dir != "" {
+			cd := "cd " + shellQuote(resolveDir(windowDir, p.Dir))
+			if text == "" {
+				text = cd
+			} else {
+				text = cd + " && " + text
+			}
+		}
+		if text != "" {
+			if err := tmux.SendKeys(paneIDs[i], text, true); err != nil {
+				return fmt.Errorf("pane %d: %w", i+1, err)
+			}
+		}
+		if p.Focus {
+			focus = paneIDs[i]
+		}
+	}
+	return tmux.FocusPane(focus)
+}
+
+// resolveDir resolves a template dir against base. Absolute and ~ paths
+// are taken as-is.
+func resolveDir(base, dir string) string {
+	switch {
+	case dir == "":
+		return base
+	case dir == "~" || strings.HasPrefix(dir, "~/"):
+		return filepath.Join(os.Getenv("HOME"), dir[1:])
+	case filepath.IsAbs(dir):
+		return dir
+	default:
+		return filepath.Join(base, dir)
+	}
+}
+
+// shellQuote single-quotes s for the pane's shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runScript runs the global layout script, if one is configured.
+func runScript(cfg config.Config, sessionName, dir string) error {
+	if cfg.Layout == "" {
+		return nil
+	}
+
+	scriptPath := filepath.Join(cfg.LayoutDir, cfg.Layout+".sh")
+	if _, err := os.Stat(scriptPath); err != nil {
+		return fmt.Errorf("layout script not found: %s", scriptPath)
+	}
+
+	cmd := exec.Command(scriptPath, sessionName, dir)
+	cmd.Env = append(os.Environ(),
+		"TMUX_SESSION="+sessionName,
+		"TMUX_WORKING_DIR="+dir,
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("layout %q failed: %w", cfg.Layout, err)
+	}
+	return nil
+}
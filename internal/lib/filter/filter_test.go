@@ -150,3 +150,25 @@ func TestFilter_PathMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter_Ranked(t *testing.T) {
+	f := NewRanked([]string{"a-p-i-x", "scratch", "happily", "api"}, ScoreSessionName)
+
+	// Empty filter keeps the item order
+	if got := f.Results(); got[0] != "a-p-i-x" {
+		t.Errorf("empty filter: first = %q, want a-p-i-x", got[0])
+	}
+
+	f.SetFilter("api")
+	got := f.Results()
+	want := []string{"api", "a-p-i-x", "happily"}
+	if len(got) != len(want) {
+		t.Fatalf("filter 'api': got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("filter 'api': got %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package filter
+
+import (
+	"slices"
+	"strings"
+)
+
+// Query keys. A word "key:value" with one of these keys is a structured
+// term; any other word is matched against names.
+const (
+	KeyIs     = "is"     // State: is:dirty, is:waiting, ...
+	KeyAgent  = "agent"  // Agent kind: agent:claude
+	KeyBranch = "branch" // Git branch: branch:main
+	KeyDir    = "dir"    // Directory: dir:~/repos/work
+	KeyGroup  = "group"  // Session group, also written @work
+)
+
+// Keys lists the query keys.
+var Keys = []string{KeyIs, KeyAgent, KeyBranch, KeyDir, KeyGroup}
+
+// Query is a parsed filter string. Its words must all hold: plain words
+// make up Text, matched fuzzily against names and ranked; the others are
+// Terms.
+type Query struct {
+	Text  string
+	Terms []Term
+}
+
+// Term is a word of a query other than plain text: "!" negates it, "|"
+// separates alternatives, any of which holds.
+type Term struct {
+	Negate bool
+	Atoms  []Atom
+}
+
+// Atom is a single test. An empty Key matches Value against the name.
+// Values are lowercase.
+type Atom struct {
+	Key   string
+	Value string
+}
+
+// ParseQuery parses a filter string:
+//
+//	api                plain text, fuzzy matched against the name
+//	is:dirty           a key:value test (see Keys)
+//	@work              shorthand for group:work
+//	!api  !is:dirty    negation
+//	api|web            alternatives
+//
+// Words still being typed ("is:", "!", "@") are ignored rather than
+// matching nothing.
+func ParseQuery(filter string) Query {
+	var q Query
+	var text []string
+	for _, word := range strings.Fields(filter) {
+		term := Term{}
+		word, term.Negate = strings.CutPrefix(word, "!")
+		for _, alt := range strings.Split(word, "|") {
+			if atom, ok := parseAtom(alt); ok {
+				term.Atoms = append(term.Atoms, atom)
+			}
+		}
+
+		switch {
+		case len(term.Atoms) == 0:
+			continue
+		case !term.Negate && len(term.Atoms) == 1 && term.Atoms[0].Key == "":
+			text = append(text, term.Atoms[0].Value)
+		default:
+			q.Terms = append(q.Terms, term)
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q
+}
+
+// parseAtom parses one alternative of a word. False for an empty one.
+func parseAtom(s string) (Atom, bool) {
+	s = strings.ToLower(s)
+	if group, ok := strings.CutPrefix(s, "@"); ok {
+		return Atom{Key: KeyGroup, Value: group}, group != ""
+	}
+	if key, value, ok := strings.Cut(s, ":"); ok && slices.Contains(Keys, key) {
+		return Atom{Key: key, Value: value}, value != ""
+	}
+	return Atom{Value: s}, s != ""
+}
+
+// IsZero reports whether the query lets everything through.
+func (q Query) IsZero() bool {
+	return q.Text == "" && len(q.Terms) == 0
+}
+
+// Match reports whether all terms hold, testing each atom with test. The
+// text is left to the caller, which scores it.
+func (q Query) Match(test func(Atom) bool) bool {
+	for _, term := range q.Terms {
+		if slices.ContainsFunc(term.Atoms, test) == term.Negate {
+			return false
+		}
+	}
+	return true
+}
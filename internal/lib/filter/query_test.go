@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   Query
+	}{
+		{"", Query{}},
+		{"api", Query{Text: "api"}},
+		{"my api", Query{Text: "my api"}},
+		{"@Work api", Query{Text: "api", Terms: []Term{{Atoms: []Atom{{KeyGroup, "work"}}}}}},
+		{"@ api", Query{Text: "api"}},
+		{"is:dirty is:", Query{Terms: []Term{{Atoms: []Atom{{KeyIs, "dirty"}}}}}},
+		{"!api", Query{Terms: []Term{{Negate: true, Atoms: []Atom{{"", "api"}}}}}},
+		{"!", Query{}},
+		{"api|web", Query{Terms: []Term{{Atoms: []Atom{{"", "api"}, {"", "web"}}}}}},
+		{"!is:waiting|agent:Claude", Query{Terms: []Term{{Negate: true, Atoms: []Atom{{KeyIs, "waiting"}, {KeyAgent, "claude"}}}}}},
+		{"dir:~/repos/work", Query{Terms: []Term{{Atoms: []Atom{{KeyDir, "~/repos/work"}}}}}},
+		{"foo:bar", Query{Text: "foo:bar"}},
+	}
+	for _, tt := range tests {
+		if got := ParseQuery(tt.filter); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseQuery(%q) = %+v, want %+v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	facts := map[Atom]bool{{KeyIs, "dirty"}: true, {KeyAgent, "pi"}: true}
+	test := func(a Atom) bool { return facts[a] }
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"", true},
+		{"anything", true}, // text is left to the caller
+		{"is:dirty", true},
+		{"is:dirty agent:claude", false},
+		{"is:dirty agent:claude|pi", false}, // alternatives need their own key
+		{"is:dirty agent:claude|agent:pi", true},
+		{"!is:dirty", false},
+		{"!is:waiting", true},
+		{"!is:waiting|is:dirty", false},
+	}
+	for _, tt := range tests {
+		if got := ParseQuery(tt.filter).Match(test); got != tt.want {
+			t.Errorf("ParseQuery(%q).Match() = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
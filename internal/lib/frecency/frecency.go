@@ -0,0 +1,129 @@
+// Package frecency ranks past selections by how often and how recently
+// they were made, the way zoxide ranks directories. The store is a JSON
+// file; keys are opaque strings.
+package frecency
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxTotal is the summed rank above which all ranks are aged, so old
+// favorites fade once new ones are used more.
+const maxTotal = 1000
+
+// maxBoost caps Boost: frecency reorders close matches, it doesn't lift a
+// poor match over a good one.
+const maxBoost = 40
+
+// Entry is the selection history of one key.
+type Entry struct {
+	Rank float64   `json:"rank"` // Selection count, aged
+	Last time.Time `json:"last"` // Last selection
+}
+
+// Store holds the entries of all keys.
+type Store struct {
+	path    string
+	entries map[string]Entry
+}
+
+// Path returns the store file in the cache dir.
+func Path(cacheDir string) string {
+	return filepath.Join(cacheDir, "frecency.json")
+}
+
+// Load reads the store from the cache dir. A missing or unreadable file
+// gives an empty store.
+func Load(cacheDir string) *Store {
+	s := &Store{path: Path(cacheDir), entries: make(map[string]Entry)}
+	if data, err := os.ReadFile(s.path); err == nil {
+		if err := json.Unmarshal(data, &s.entries); err != nil || s.entries == nil {
+			s.entries = make(map[string]Entry)
+		}
+	}
+	return s
+}
+
+// Add records a selection of key.
+func (s *Store) Add(key string, now time.Time) {
+	e := s.entries[key]
+	e.Rank++
+	e.Last = now
+	s.entries[key] = e
+
+	total := 0.0
+	for _, e := range s.entries {
+		total += e.Rank
+	}
+	if total <= maxTotal {
+		return
+	}
+	for k, e := range s.entries {
+		e.Rank *= 0.9
+		if e.Rank < 1 {
+			delete(s.entries, k)
+			continue
+		}
+		s.entries[k] = e
+	}
+}
+
+// Rename moves the history of oldKey to newKey, merging it into any
+// newKey has. False if oldKey has none.
+func (s *Store) Rename(oldKey, newKey string) bool {
+	e, ok := s.entries[oldKey]
+	if !ok {
+		return false
+	}
+	delete(s.entries, oldKey)
+	if cur, ok := s.entries[newKey]; ok {
+		e.Rank += cur.Rank
+		if cur.Last.After(e.Last) {
+			e.Last = cur.Last
+		}
+	}
+	s.entries[newKey] = e
+	return true
+}
+
+// Save writes the store.
+func (s *Store) Save() error {
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// Score returns the frecency of key: its rank weighted by how recently it
+// was last selected. 0 for keys never selected.
+func (s *Store) Score(key string, now time.Time) float64 {
+	e, ok := s.entries[key]
+	if !ok {
+		return 0
+	}
+	switch age := now.Sub(e.Last); {
+	case age < time.Hour:
+		return e.Rank * 4
+	case age < 24*time.Hour:
+		return e.Rank * 2
+	case age < 7*24*time.Hour:
+		return e.Rank / 2
+	default:
+		return e.Rank / 4
+	}
+}
+
+// Boost converts the frecency of key into points to add to a fuzzy match
+// score. It grows logarithmically and is capped, so a few selections make
+// a difference and hundreds don't drown out the match quality.
+func (s *Store) Boost(key string, now time.Time) int {
+	return min(int(8*math.Log2(1+s.Score(key, now))), maxBoost)
+}
@@ -0,0 +1,84 @@
+package frecency
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoost(t *testing.T) {
+	now := time.Now()
+	s := Load(t.TempDir())
+
+	if got := s.Boost("a", now); got != 0 {
+		t.Errorf("Boost of unknown key = %d, want 0", got)
+	}
+
+	s.Add("a", now)
+	s.Add("b", now.Add(-48*time.Hour))
+	a, b := s.Boost("a", now), s.Boost("b", now)
+	if a <= b || b <= 0 {
+		t.Errorf("Boost(a) = %d, Boost(b) = %d, want a > b > 0", a, b)
+	}
+
+	for range 500 {
+		s.Add("c", now)
+	}
+	if got := s.Boost("c", now); got != maxBoost {
+		t.Errorf("Boost(c) = %d, want the cap %d", got, maxBoost)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	s := Load(dir)
+	s.Add("session:helm", now)
+	s.Add("session:helm", now)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded := Load(dir)
+	if got, want := loaded.Score("session:helm", now), 8.0; got != want {
+		t.Errorf("Score after Load = %v, want %v", got, want)
+	}
+}
+
+func TestAging(t *testing.T) {
+	now := time.Now()
+	s := Load(t.TempDir())
+	s.Add("old", now)
+	for range maxTotal {
+		s.Add("new", now)
+	}
+
+	// Aging dropped the single selection below 1 and removed it
+	if _, ok := s.entries["old"]; ok {
+		t.Error("old entry survived aging")
+	}
+	if rank := s.entries["new"].Rank; rank >= maxTotal {
+		t.Errorf("new rank = %v, want aged below %d", rank, maxTotal)
+	}
+}
+
+func TestRename(t *testing.T) {
+	now := time.Now()
+	s := Load(t.TempDir())
+	s.Add("session:old", now.Add(-time.Hour))
+	s.Add("session:old", now.Add(-time.Hour))
+	s.Add("session:new", now)
+
+	if !s.Rename("session:old", "session:new") {
+		t.Fatal("Rename() = false for a known key")
+	}
+	if _, ok := s.entries["session:old"]; ok {
+		t.Error("old key kept after Rename")
+	}
+	if e := s.entries["session:new"]; e.Rank != 3 || !e.Last.Equal(now) {
+		t.Errorf("merged entry = %+v, want rank 3 last selected now", e)
+	}
+	if s.Rename("session:gone", "session:x") {
+		t.Error("Rename() = true for an unknown key")
+	}
+}
@@ -0,0 +1,203 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Scoring weights. A matched character is worth scoreMatch; bonuses favor
+// matches that start words and continue runs, penalties spread-out ones.
+// A run inherits the bonus of the boundary it started on, so "api" scores
+// higher in "api" than in "a-p-i-x" even though every letter of the latter
+// starts a word.
+const (
+	scoreMatch        = 16
+	penaltyGapStart   = 3
+	penaltyGapExtend  = 1
+	bonusBoundary     = 8 // After a separator or at the start of the text
+	bonusCamel        = 7 // An upper-case letter after a lower-case one
+	bonusConsecutive  = 4 // Minimum bonus inside a run
+	bonusFirstPattern = 2 // Multiplier for the first pattern character's bonus
+	bonusPathTail     = 16
+)
+
+// Result is a scored match: higher scores are better matches. Positions
+// holds the rune indices of the matched characters in the text.
+type Result struct {
+	Score     int
+	Positions []int
+}
+
+// Score matches the pattern against the text like Match and scores the
+// best alignment: contiguous runs, matches at word boundaries (after
+// "-_./: " or a camelCase hump) and an early first match score higher.
+// Matching is case-insensitive.
+func Score(text, pattern string) (Result, bool) {
+	if pattern == "" {
+		return Result{}, true
+	}
+	pat := []rune(strings.ToLower(pattern))
+	if !Match(text, string(pat)) {
+		return Result{}, false
+	}
+
+	orig := []rune(text)
+	n, m := len(orig), len(pat)
+	lower := make([]rune, n)
+	bonus := make([]int, n)
+	for i, r := range orig {
+		lower[i] = unicode.ToLower(r)
+		bonus[i] = boundaryBonus(orig, i)
+	}
+
+	// score[i*m+j] is the best score of pattern[:j+1] with pattern[j] on
+	// text[i]; run the bonus its run carries; from the text index of
+	// pattern[j-1] in that alignment.
+	const none = -1 << 30
+	score := make([]int, n*m)
+	run := make([]int, n*m)
+	from := make([]int, n*m)
+	for k := range score {
+		score[k] = none
+	}
+
+	for j := 0; j < m; j++ {
+		gapBest, gapFrom := none, -1 // best predecessor at i-2 or before, gap penalty applied
+		for i := j; i < n; i++ {
+			if j > 0 && i >= 2 {
+				if prev := score[(i-2)*m+j-1]; prev > none && prev-penaltyGapStart > gapBest-penaltyGapExtend {
+					gapBest, gapFrom = prev-penaltyGapStart, i-2
+				} else if gapBest > none {
+					gapBest -= penaltyGapExtend
+				}
+			}
+			if lower[i] != pat[j] {
+				continue
+			}
+			k := i*m + j
+
+			if j == 0 {
+				score[k] = scoreMatch + bonus[i]*bonusFirstPattern
+				run[k] = bonus[i]
+				from[k] = -1
+				continue
+			}
+
+			if gapBest > none {
+				score[k] = gapBest + scoreMatch + bonus[i]
+				run[k] = bonus[i]
+				from[k] = gapFrom
+			}
+			if prev := (i-1)*m + j - 1; score[prev] > none {
+				b := max(bonus[i], run[prev], bonusConsecutive)
+				if s := score[prev] + scoreMatch + b; s >= score[k] {
+					score[k] = s
+					run[k] = max(bonus[i], run[prev])
+					from[k] = i - 1
+				}
+			}
+		}
+	}
+
+	best, end := none, -1
+	for i := m - 1; i < n; i++ {
+		if s := score[i*m+m-1]; s > best {
+			best, end = s, i
+		}
+	}
+	if end < 0 {
+		return Result{}, false
+	}
+
+	positions := make([]int, m)
+	for j, i := m-1, end; j >= 0; j-- {
+		positions[j] = i
+		i = from[i*m+j]
+	}
+	return Result{Score: best, Positions: positions}, true
+}
+
+// boundaryBonus returns the bonus for a match on text[i].
+func boundaryBonus(text []rune, i int) int {
+	if i == 0 {
+		return bonusBoundary
+	}
+	prev, cur := text[i-1], text[i]
+	switch {
+	case strings.ContainsRune("-_./: @~", prev):
+		return bonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return bonusCamel
+	}
+	return 0
+}
+
+// ScorePath is Score with the segment rules of MatchPath. Without "/" the
+// pattern is scored against the best-matching segment, with a bonus when
+// that is the last one — "helm" ranks "black-atom-industries/helm" over
+// "helm/notes". With "/" the pattern segments are scored against the
+// text's trailing segments and summed. Positions index the whole text.
+func ScorePath(text, pattern string) (Result, bool) {
+	if pattern == "" {
+		return Result{}, true
+	}
+
+	segments := strings.Split(text, "/")
+	starts := make([]int, len(segments))
+	offset := 0
+	for i, segment := range segments {
+		starts[i] = offset
+		offset += len([]rune(segment)) + 1
+	}
+	patternSegments := strings.Split(pattern, "/")
+
+	// No "/" in pattern: the best segment
+	if len(patternSegments) == 1 {
+		var best Result
+		found := false
+		for i, segment := range segments {
+			r, ok := Score(segment, pattern)
+			if !ok {
+				continue
+			}
+			if i == len(segments)-1 {
+				r.Score += bonusPathTail
+			}
+			if !found || r.Score > best.Score {
+				best, found = shift(r, starts[i]), true
+			}
+		}
+		return best, found
+	}
+
+	// "/" in pattern: segments right-to-left
+	if len(patternSegments) > len(segments) {
+		return Result{}, false
+	}
+	var total Result
+	ti := len(segments) - 1
+	for pi := len(patternSegments) - 1; pi >= 0; pi, ti = pi-1, ti-1 {
+		if patternSegments[pi] == "" {
+			continue // matches any segment
+		}
+		r, ok := Score(segments[ti], patternSegments[pi])
+		if !ok {
+			return Result{}, false
+		}
+		if ti == len(segments)-1 {
+			r.Score += bonusPathTail
+		}
+		r = shift(r, starts[ti])
+		total.Score += r.Score
+		total.Positions = append(r.Positions, total.Positions...)
+	}
+	return total, true
+}
+
+// shift moves a segment's match positions to the segment's offset.
+func shift(r Result, offset int) Result {
+	for i := range r.Positions {
+		r.Positions[i] += offset
+	}
+	return r
+}
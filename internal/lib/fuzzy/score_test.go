@@ -0,0 +1,101 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScorePositions(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		pattern string
+		want    []int
+	}{
+		{"word starts", "hello-world", "hw", []int{0, 6}},
+		{"camel case", "FooBar", "fb", []int{0, 3}},
+		{"contiguous run after a boundary", "nikbrunner-notes", "notes", []int{11, 12, 13, 14, 15}},
+		{"case insensitive", "Helm", "HE", []int{0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := Score(tt.text, tt.pattern)
+			if !ok {
+				t.Fatalf("Score(%q, %q) did not match", tt.text, tt.pattern)
+			}
+			if !reflect.DeepEqual(r.Positions, tt.want) {
+				t.Errorf("positions = %v, want %v", r.Positions, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreRanking(t *testing.T) {
+	tests := []struct {
+		pattern       string
+		better, worse string
+	}{
+		{"api", "api", "a-p-i-x"},
+		{"api", "api-server", "happily"},
+		{"hw", "hello-world", "show"},
+		{"fb", "FooBar", "fabric"},
+		{"dots", "dots", "dotfiles-tests"},
+	}
+	for _, tt := range tests {
+		better, ok := Score(tt.better, tt.pattern)
+		if !ok {
+			t.Fatalf("Score(%q, %q) did not match", tt.better, tt.pattern)
+		}
+		worse, ok := Score(tt.worse, tt.pattern)
+		if !ok {
+			t.Fatalf("Score(%q, %q) did not match", tt.worse, tt.pattern)
+		}
+		if better.Score <= worse.Score {
+			t.Errorf("%q: %q scored %d, not above %q with %d", tt.pattern, tt.better, better.Score, tt.worse, worse.Score)
+		}
+	}
+}
+
+func TestScoreAgreesWithMatch(t *testing.T) {
+	texts := []string{"hello", "hello-world", "FooBar", "nikbrunner-notes", "a", ""}
+	patterns := []string{"", "h", "hw", "ow", "fb", "xyz", "notes", "hello-world!"}
+	for _, text := range texts {
+		for _, pattern := range patterns {
+			_, got := Score(text, pattern)
+			if want := Match(text, pattern); got != want {
+				t.Errorf("Score(%q, %q) matched = %v, Match = %v", text, pattern, got, want)
+			}
+		}
+	}
+}
+
+func TestScorePath(t *testing.T) {
+	// The last segment wins over an earlier one
+	tail, _ := ScorePath("black-atom-industries/helm", "helm")
+	head, _ := ScorePath("helm/notes", "helm")
+	if tail.Score <= head.Score {
+		t.Errorf("tail match scored %d, not above %d", tail.Score, head.Score)
+	}
+	if want := []int{22, 23, 24, 25}; !reflect.DeepEqual(tail.Positions, want) {
+		t.Errorf("positions = %v, want %v", tail.Positions, want)
+	}
+
+	// Pattern segments map to the trailing text segments
+	r, ok := ScorePath("nikbrunner/dots", "nb/d")
+	if !ok {
+		t.Fatal(`ScorePath("nikbrunner/dots", "nb/d") did not match`)
+	}
+	if want := []int{0, 3, 11}; !reflect.DeepEqual(r.Positions, want) {
+		t.Errorf("positions = %v, want %v", r.Positions, want)
+	}
+
+	for _, tt := range []struct{ text, pattern string }{
+		{"a/b", "a/b/c"},
+		{"helm/notes", "helm/x"},
+		{"nikbrunner/dots", "xyz"},
+	} {
+		if _, ok := ScorePath(tt.text, tt.pattern); ok != MatchPath(tt.text, tt.pattern) {
+			t.Errorf("ScorePath(%q, %q) disagrees with MatchPath", tt.text, tt.pattern)
+		}
+	}
+}
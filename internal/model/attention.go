@@ -0,0 +1,24 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/session"
+)
+
+// jumpToWaiting is the TUI side of session.NextWaiting: switch and close
+// helm.
+func (m *Model) jumpToWaiting() (tea.Model, tea.Cmd) {
+	if _, err := session.NextWaiting(m.config); err != nil {
+		if errors.Is(err, session.ErrNoneWaiting) {
+			m.setMessage("No agent is waiting")
+			return m, clearMessageAfter(3 * time.Second)
+		}
+		m.setError("Error: %v", err)
+		return m, nil
+	}
+	return m, tea.Quit
+}
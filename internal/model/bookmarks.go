@@ -37,7 +37,7 @@ func (m *Model) handleBookmarksMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, keys.Expand):
 		// Expand bookmark if it has a session
 		if selected, ok := m.bookmarkList.SelectedItem(); ok {
-			sessionName := m.extractSessionName(selected.Path)
+			sessionName := m.config.BookmarkSessionName(selected)
 			if session := m.findSessionByName(sessionName); session != nil {
 				m.bookmarkExpanded[selected.Path] = true
 			}
@@ -121,30 +121,15 @@ func (m *Model) addSelectedToBookmarks() (tea.Model, tea.Cmd) {
 	}
 
 	session := m.getSession(item)
-	// Get session path from tmux
-	path, err := git.GetSessionPath(session.Name)
-	if err != nil || path == "" {
-		// Fallback: assume it's in one of the project dirs
-		for _, dir := range m.config.ProjectDirs {
-			possiblePath := filepath.Join(dir, session.Name)
-			if _, err := os.Stat(possiblePath); err == nil {
-				path = possiblePath
-				break
-			}
-		}
-	}
-
+	path := m.sessionBookmarkPath(session.Name)
 	if path == "" {
 		m.setError("Could not determine path for session")
 		return m, nil
 	}
 
-	// Check if already bookmarked
-	for _, b := range m.config.Bookmarks {
-		if b.Path == path || filepath.Base(b.Path) == session.Name {
-			m.setError("Session already bookmarked")
-			return m, nil
-		}
+	if m.isBookmarked(session.Name, path) {
+		m.setError("Session already bookmarked")
+		return m, nil
 	}
 
 	// Add bookmark
@@ -162,9 +147,36 @@ func (m *Model) addSelectedToBookmarks() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// sessionBookmarkPath returns the directory a bookmark of the session
+// points at: its tmux session path, else a project dir entry of its name.
+// Empty if neither is found.
+func (m *Model) sessionBookmarkPath(name string) string {
+	if path, err := git.GetSessionPath(name); err == nil && path != "" {
+		return path
+	}
+	// Fallback: assume it's in one of the project dirs
+	for _, dir := range m.config.ProjectDirs {
+		possiblePath := filepath.Join(dir, name)
+		if _, err := os.Stat(possiblePath); err == nil {
+			return possiblePath
+		}
+	}
+	return ""
+}
+
+// isBookmarked reports whether a bookmark already opens the session.
+func (m *Model) isBookmarked(name, path string) bool {
+	for _, b := range m.config.Bookmarks {
+		if b.Path == path || filepath.Base(b.Path) == name {
+			return true
+		}
+	}
+	return false
+}
+
 // openBookmark opens or switches to a bookmarked session
 func (m *Model) openBookmark(bookmark config.Bookmark) (tea.Model, tea.Cmd) {
-	sessionName := m.extractSessionName(bookmark.Path)
+	sessionName := m.config.BookmarkSessionName(bookmark)
 
 	// Create session if it doesn't exist
 	if !tmux.SessionExists(sessionName) {
@@ -182,6 +194,7 @@ func (m *Model) openBookmark(bookmark config.Bookmark) (tea.Model, tea.Cmd) {
 		m.setError("Failed to switch to session: %v", err)
 		return m, nil
 	}
+	m.recordSelection(sessionName, bookmark.Path)
 
 	return m, tea.Quit
 }
@@ -276,8 +289,8 @@ func (m Model) viewBookmarks() string {
 		maxGitWidth := 0
 		for _, bookmark := range visibleBookmarks {
 			// Check if session has git status
-			sessionName := m.extractSessionName(bookmark.Path)
-			if _, ok := m.gitStatuses[sessionName]; ok && m.config.GitStatusEnabled {
+			sessionName := m.config.BookmarkSessionName(bookmark)
+			if status, ok := m.gitStatuses[sessionName]; ok && !status.IsClean() && m.config.GitStatusEnabled {
 				if ui.GitStatusColumnWidth > maxGitWidth {
 					maxGitWidth = ui.GitStatusColumnWidth
 				}
@@ -287,6 +300,7 @@ func (m Model) viewBookmarks() string {
 		layout := ui.RowLayout{
 			NameWidth:      m.maxNameWidth, // Use shared width for stable layout
 			GitStatusWidth: maxGitWidth,
+			AgentKinds:     m.agents.Kinds(),
 		}
 
 		// Table header row
@@ -294,8 +308,6 @@ func (m Model) viewBookmarks() string {
 			ShowExpandIcon: false,
 			ShowTime:       false,
 			ShowGit:        maxGitWidth > 0,
-			ShowCC:         true, // Always show CC in header when data rows have it reserved
-			ShowPi:         true, // Always show Pi in header when data rows have it reserved
 			NameLabel:      "BKMK",
 		})
 		b.WriteString(header)
@@ -320,7 +332,7 @@ func (m Model) viewBookmarks() string {
 				}
 			}
 
-			sessionName := m.extractSessionName(bookmark.Path)
+			sessionName := m.config.BookmarkSessionName(bookmark)
 			session := m.findSessionByName(sessionName)
 			expanded := m.bookmarkExpanded[bookmark.Path]
 
@@ -344,13 +356,7 @@ func (m Model) viewBookmarks() string {
 				if m.gitStatusShowLoading && m.gitStatusPending[sessionName] {
 					opts.GitStatusLoading = true
 				}
-				// Statuses are sorted most-active first — [0] drives the glyph
-				if statuses := m.claudeStatuses[sessionName]; len(statuses) > 0 {
-					opts.ClaudeStatus = &statuses[0]
-				}
-				if statuses := m.piStatuses[sessionName]; len(statuses) > 0 {
-					opts.PiStatus = &statuses[0]
-				}
+				opts.AgentStatuses = m.topAgentStatuses(sessionName)
 				b.WriteString(ui.RenderSessionRow(sessionName, session.LastActivity, layout, opts, m.rowWidth()))
 				b.WriteString("\n")
 				// line count tracked by renderWithSidebar
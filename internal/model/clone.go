@@ -1,9 +1,13 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,18 +21,14 @@ import (
 // Clone-specific message types
 
 type cloneReposLoadedMsg struct {
-	repos []string
+	repos []giturl.Repo
+	errs  []error // Providers that failed to list
 }
 
 type cloneErrorMsg struct {
 	err error
 }
 
-type cloneSuccessMsg struct {
-	repoPath    string
-	sessionName string
-}
-
 // handleCloneChoiceMode handles input in the clone choice sub-menu (URL vs My Repos)
 func (m *Model) handleCloneChoiceMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	keys := ui.DefaultKeyMap
@@ -42,6 +42,7 @@ func (m *Model) handleCloneChoiceMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.cloneChoiceCursor = 1 - m.cloneChoiceCursor
 
 	case key.Matches(msg, keys.Select):
+		m.cloneJobs = nil
 		if m.cloneChoiceCursor == 0 {
 			// Enter URL
 			m.input.Reset()
@@ -60,7 +61,6 @@ func (m *Model) handleCloneChoiceMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.cloneList.Clear()
 		m.cloneError = ""
 		m.cloneLoading = true
-		m.cloneCloning = false
 		m.mode = ModeCloneRepo
 		return m, m.fetchAvailableReposCmd()
 
@@ -76,30 +76,12 @@ func (m *Model) handleCloneURLMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// Handle confirmation state (reused from clone flow)
 	if m.cloneSuccess {
-		switch {
-		case key.Matches(msg, keys.Select):
-			m.applyLayout(m.cloneSuccessSession, m.cloneSuccessPath)
-			if err := tmux.SwitchClient(m.cloneSuccessSession); err != nil {
-				m.setError("Created but failed to switch: %v", err)
-				m.mode = ModeNormal
-				m.cloneSuccess = false
-				return m, m.loadSessions
-			}
-			return m, tea.Quit
-		case key.Matches(msg, keys.Cancel):
-			m.mode = ModeNormal
-			m.cloneSuccess = false
-			return m, m.loadSessions
-		}
-		return m, nil
+		return m.handleCloneSuccess(msg)
 	}
 
 	switch {
 	case key.Matches(msg, keys.Cancel):
-		if m.cloneCloning {
-			m.mode = ModeNormal
-			m.cloneCloning = false
-			m.cloneError = ""
+		if m.cancelNewestClone() {
 			return m, nil
 		}
 		if m.cloneError != "" {
@@ -120,10 +102,17 @@ func (m *Model) handleCloneURLMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.cloneError = err.Error()
 			return m, nil
 		}
-		m.input.Blur()
-		return m.cloneSelectedRepo(ownerRepo)
+		// Ready for the next URL while this one clones
+		m.input.Reset()
+		m.cloneError = ""
+		return m, m.startClone(ownerRepo, value, ownerRepo)
+
+	case msg.Type == tea.KeyTab:
+		m.cycleClonePreset()
+		return m, nil
 
 	case key.Matches(msg, keys.Quit):
+		m.cancelClones()
 		return m, tea.Quit
 	}
 
@@ -147,42 +136,27 @@ func (m *Model) handleCloneRepoMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// Handle confirmation state
 	if m.cloneSuccess {
-		switch {
-		case key.Matches(msg, keys.Select):
-			// Apply layout and switch to the session
-			m.applyLayout(m.cloneSuccessSession, m.cloneSuccessPath)
-			if err := tmux.SwitchClient(m.cloneSuccessSession); err != nil {
-				m.setError("Created but failed to switch: %v", err)
-				m.mode = ModeNormal
-				m.cloneSuccess = false
-				return m, m.loadSessions
-			}
-			return m, tea.Quit
-
-		case key.Matches(msg, keys.Cancel):
-			// Go back to session list without switching
-			m.mode = ModeNormal
-			m.cloneSuccess = false
-			return m, m.loadSessions
-		}
-		return m, nil
+		return m.handleCloneSuccess(msg)
 	}
 
 	switch {
 	case key.Matches(msg, keys.Cancel):
-		// If loading or cloning, just cancel and go back
-		if m.cloneLoading || m.cloneCloning {
+		// If loading, just cancel and go back
+		if m.cloneLoading {
 			m.mode = ModeNormal
 			m.cloneLoading = false
-			m.cloneCloning = false
 			m.cloneError = ""
 			return m, nil
 		}
-		// Clear filter first, then exit on second press
+		// Clear filter first, then cancel running clones, newest first,
+		// then exit
 		if m.cloneList.Filter() != "" {
 			m.cloneList.SetFilter("")
 			return m, nil
 		}
+		if m.cancelNewestClone() {
+			return m, nil
+		}
 		// If there's an error, clear it and go back
 		if m.cloneError != "" {
 			m.mode = ModeNormal
@@ -199,15 +173,21 @@ func (m *Model) handleCloneRepoMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.cloneList.MoveCursor(1)
 
 	case key.Matches(msg, keys.Select):
-		if selected, ok := m.cloneList.SelectedItem(); ok && !m.cloneLoading && !m.cloneCloning && m.cloneError == "" {
-			return m.cloneSelectedRepo(selected)
+		if selected, ok := m.cloneList.SelectedItem(); ok && !m.cloneLoading && m.cloneError == "" {
+			// Take it off the list, so more repos can be queued meanwhile
+			m.cloneList.SetItems(slices.DeleteFunc(slices.Clone(m.cloneList.Items()), func(r giturl.Repo) bool { return r == selected }))
+			return m, m.startClone(selected.Dir(m.config.GitProviders), selected.CloneURL, selected.String())
 		}
 
+	case msg.Type == tea.KeyTab:
+		m.cycleClonePreset()
+
 	case key.Matches(msg, keys.Quit):
+		m.cancelClones()
 		return m, tea.Quit
 
 	default:
-		if !m.cloneLoading && !m.cloneCloning && m.cloneError == "" {
+		if !m.cloneLoading && m.cloneError == "" {
 			m.cloneList.HandleKey(msg)
 		}
 	}
@@ -215,55 +195,88 @@ func (m *Model) handleCloneRepoMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) cloneSelectedRepo(selected string) (tea.Model, tea.Cmd) {
-	m.cloneCloning = true
-	m.cloneCloningRepo = selected
-
-	destPath := filepath.Join(m.cloneBasePath, selected)
-	// Derive the session name from the actual destination path so it stays
-	// in sync with what the project picker would produce for the same dir
-	// (single source of truth = m.extractSessionName).
-	sessionName := m.extractSessionName(destPath)
+// handleCloneSuccess handles the confirmation shown once the clones are
+// done: Enter switches to the last one cloned.
+func (m *Model) handleCloneSuccess(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := ui.DefaultKeyMap
 
-	return m, func() tea.Msg {
-		if err := giturl.CloneRepo(selected, destPath); err != nil {
-			return cloneErrorMsg{err: err}
+	switch {
+	case key.Matches(msg, keys.Select):
+		// Apply layout and switch to the session
+		m.applyLayout(m.cloneSuccessSession, m.cloneSuccessPath)
+		if err := tmux.SwitchClient(m.cloneSuccessSession); err != nil {
+			m.setError("Created but failed to switch: %v", err)
+			m.mode = ModeNormal
+			m.cloneSuccess = false
+			return m, m.loadSessions
 		}
+		return m, tea.Quit
 
-		// Create tmux session
-		if err := tmux.CreateSession(sessionName, destPath); err != nil {
-			return cloneErrorMsg{err: fmt.Errorf("cloned but failed to create session: %w", err)}
-		}
+	case key.Matches(msg, keys.Cancel):
+		// Go back to session list without switching
+		m.mode = ModeNormal
+		m.cloneSuccess = false
+		return m, m.loadSessions
+	}
+	return m, nil
+}
 
-		return cloneSuccessMsg{
-			repoPath:    destPath,
-			sessionName: sessionName,
+// repoProviders returns the providers of the repo_providers config, or
+// github.com alone when there are none.
+func repoProviders(cfg config.Config) ([]giturl.Provider, error) {
+	configured := cfg.RepoProviders
+	if len(configured) == 0 {
+		configured = []config.RepoProvider{{Type: giturl.KindGitHub}}
+	}
+	providers := make([]giturl.Provider, 0, len(configured))
+	for i, p := range configured {
+		token := p.Token
+		if p.TokenEnv != "" {
+			token = os.Getenv(p.TokenEnv)
 		}
+		provider, err := giturl.NewProvider(giturl.ProviderOptions{Kind: p.Type, Host: p.Host, APIURL: p.URL, Token: token})
+		if err != nil {
+			return nil, fmt.Errorf("repo_providers[%d]: %w", i, err)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// joinErrors joins errors into one line, for the status bar.
+func joinErrors(errs []error) string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
 	}
+	return strings.Join(msgs, "; ")
 }
 
-// fetchAvailableReposCmd fetches repos from GitHub
+// fetchAvailableReposCmd lists the repos of all providers that aren't
+// cloned yet
 func (m *Model) fetchAvailableReposCmd() tea.Cmd {
+	cfg := m.config
 	basePath := m.cloneBasePath
 	return func() tea.Msg {
-		// Check gh CLI
-		if err := giturl.CheckGhCli(); err != nil {
+		providers, err := repoProviders(cfg)
+		if err != nil {
 			return cloneErrorMsg{err: err}
 		}
 
-		// Fetch available repos
-		available, err := giturl.FetchAvailableRepos()
-		if err != nil {
-			return cloneErrorMsg{err: err}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		available, errs := giturl.ListAll(ctx, providers)
+		if len(errs) == len(providers) {
+			return cloneErrorMsg{err: errors.New(joinErrors(errs))}
 		}
 
-		// Get already cloned
+		// Filter out the cloned ones, by the directory they'd be cloned to
 		cloned, _ := config.ListClonedRepos(basePath)
+		uncloned := slices.DeleteFunc(available, func(r giturl.Repo) bool {
+			return slices.Contains(cloned, r.Dir(cfg.GitProviders))
+		})
 
-		// Filter out cloned
-		uncloned := config.FilterUncloned(available, cloned)
-
-		return cloneReposLoadedMsg{repos: uncloned}
+		return cloneReposLoadedMsg{repos: uncloned, errs: errs}
 	}
 }
 
@@ -272,7 +285,7 @@ func (m *Model) fetchAvailableReposCmd() tea.Cmd {
 func (m *Model) cloneMaxVisibleItems() int {
 	contentH := m.contentHeight()
 	if contentH > 0 {
-		if available := contentH - 6; available > 0 { // header(3) + footer(3)
+		if available := contentH - 7 - m.cloneJobsHeight(); available > 0 { // header(3) + footer(3) + presets + clones
 			return available
 		}
 	}
@@ -315,18 +328,20 @@ func (m Model) viewCloneURL() string {
 	header.WriteString(ui.RenderBorder(m.borderWidth()))
 	header.WriteString("\n")
 
+	// Clones running or done
+	b.WriteString(m.renderCloneJobs())
+
 	if m.cloneSuccess {
-		fmt.Fprintf(&b, "  Cloned: %s\n", m.cloneCloningRepo)
+		fmt.Fprintf(&b, "  Cloned: %s\n", m.cloneSuccessRepo)
 		fmt.Fprintf(&b, "  Session: %s\n", m.cloneSuccessSession)
 		b.WriteString("\n")
 		b.WriteString("  Switch to the new session?\n")
-	} else if m.cloneCloning {
-		fmt.Fprintf(&b, "  Cloning %s...\n", m.cloneCloningRepo)
-	} else if m.cloneError != "" {
-		b.WriteString(ui.ErrorMessageStyle.Render("  "+m.cloneError) + "\n")
-		b.WriteString("  " + m.input.View() + "\n")
 	} else {
+		if m.cloneError != "" {
+			b.WriteString(ui.ErrorMessageStyle.Render("  "+m.cloneError) + "\n")
+		}
 		b.WriteString("  " + m.input.View() + "\n")
+		b.WriteString(m.renderClonePresets())
 	}
 
 	// Padding is handled by renderWithSidebar
@@ -347,16 +362,16 @@ func (m Model) viewCloneRepo() string {
 	header.WriteString(ui.RenderBorder(m.borderWidth()))
 	header.WriteString("\n")
 
-	// Content area
+	// Content area: clones running or done above the list
+	b.WriteString(m.renderCloneJobs())
+
 	if m.cloneSuccess {
-		fmt.Fprintf(&b, "  Cloned: %s\n", m.cloneCloningRepo)
+		fmt.Fprintf(&b, "  Cloned: %s\n", m.cloneSuccessRepo)
 		fmt.Fprintf(&b, "  Session: %s\n", m.cloneSuccessSession)
 		b.WriteString("\n")
 		b.WriteString("  Switch to the new session?\n")
 	} else if m.cloneLoading {
 		b.WriteString("  Fetching available repositories...\n")
-	} else if m.cloneCloning {
-		fmt.Fprintf(&b, "  Cloning %s...\n", m.cloneCloningRepo)
 	} else if m.cloneError != "" {
 		b.WriteString(ui.ErrorMessageStyle.Render("  "+m.cloneError) + "\n")
 	} else if m.cloneList.Len() == 0 {
@@ -366,6 +381,8 @@ func (m Model) viewCloneRepo() string {
 			b.WriteString("  No repositories available to clone\n")
 		}
 	} else {
+		b.WriteString(m.renderClonePresets())
+
 		// Repository list - calculate max visible items
 		maxItems := m.cloneMaxVisibleItems()
 		m.cloneList.SetHeight(maxItems)
@@ -385,9 +402,9 @@ func (m Model) viewCloneRepo() string {
 			}
 
 			if selected {
-				b.WriteString(ui.FilterStyle.Render(repo))
+				b.WriteString(ui.FilterStyle.Render(repo.String()))
 			} else {
-				b.WriteString(repo)
+				b.WriteString(repo.String())
 			}
 			b.WriteString("\n")
 		}
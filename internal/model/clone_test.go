@@ -0,0 +1,36 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/black-atom-industries/helm/internal/config"
+)
+
+func TestRepoProviders(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []config.RepoProvider
+		want      int
+		wantErr   string
+	}{
+		{"default github", nil, 1, ""},
+		{"configured", []config.RepoProvider{{Type: "github", Token: "t"}, {Type: "gitea", Host: "codeberg.org", Token: "t"}}, 2, ""},
+		{"unknown type", []config.RepoProvider{{Type: "github", Token: "t"}, {Type: "svn"}}, 0, "repo_providers[1]: unknown provider type"},
+		{"missing host", []config.RepoProvider{{Type: "bitbucket"}}, 0, "repo_providers[0]: bitbucket provider needs a host"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repoProviders(config.Config{RepoProviders: tt.providers})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("repoProviders() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || len(got) != tt.want {
+				t.Errorf("repoProviders() = %d providers, %v, want %d", len(got), err, tt.want)
+			}
+		})
+	}
+}
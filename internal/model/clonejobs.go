@@ -0,0 +1,245 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/giturl"
+	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+// cloneJob is a clone running in the background of the clone views.
+// Several can run at once; they are listed above the repo list or URL
+// input until the clone flow is left.
+type cloneJob struct {
+	label    string // Repo as listed, or the URL's directory
+	destPath string
+	session  string
+	progress giturl.Progress
+	started  bool // Progress has been reported
+	running  bool
+	canceled bool
+	err      error
+
+	cancel context.CancelFunc
+	events chan tea.Msg // Progress and the final done message, then closed
+}
+
+// cloneProgressMsg reports a clone's progress.
+type cloneProgressMsg struct {
+	job      *cloneJob
+	progress giturl.Progress
+}
+
+// cloneDoneMsg reports that a clone finished, failed or was canceled.
+type cloneDoneMsg struct {
+	job *cloneJob
+	err error
+}
+
+// clonePreset is a way to clone, picked with Tab in the clone views.
+type clonePreset struct {
+	name string
+	opts giturl.CloneOptions
+}
+
+// clonePresets trade history and files for a faster clone of big repos.
+// Sparse checks out the top-level files only; widen it later with
+// git sparse-checkout add.
+var clonePresets = []clonePreset{
+	{"full", giturl.CloneOptions{}},
+	{"shallow", giturl.CloneOptions{Depth: 1}},
+	{"partial", giturl.CloneOptions{Filter: "blob:none"}},
+	{"sparse", giturl.CloneOptions{Filter: "blob:none", Sparse: true}},
+}
+
+// cycleClonePreset moves to the next preset (Tab).
+func (m *Model) cycleClonePreset() {
+	m.clonePreset = (m.clonePreset + 1) % len(clonePresets)
+}
+
+// renderClonePresets renders the presets with the current one highlighted.
+func (m Model) renderClonePresets() string {
+	parts := make([]string, len(clonePresets))
+	for i, p := range clonePresets {
+		if i == m.clonePreset {
+			parts[i] = ui.HelpKeyStyle.Render(p.name)
+		} else {
+			parts[i] = ui.HelpDescStyle.Render(p.name)
+		}
+	}
+	return "  " + ui.HelpDescStyle.Render("Tab ") + strings.Join(parts, ui.HelpDescStyle.Render(" · ")) + "\n"
+}
+
+// startClone clones a repo into dir under the clone base path and creates
+// its session, in the background, the way the current preset says; label
+// names it in the job list.
+func (m *Model) startClone(dir, cloneURL, label string) tea.Cmd {
+	destPath := filepath.Join(m.cloneBasePath, dir)
+	for _, job := range m.cloneJobs {
+		if job.running && job.destPath == destPath {
+			m.setError("Already cloning %s", label)
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &cloneJob{
+		label:    label,
+		destPath: destPath,
+		// Derive the session name from the actual destination path so it
+		// stays in sync with what the project picker would produce for the
+		// same dir (single source of truth = m.extractSessionName).
+		session: m.extractSessionName(destPath),
+		running: true,
+		cancel:  cancel,
+		events:  make(chan tea.Msg, 16),
+	}
+	m.cloneJobs = append(m.cloneJobs, job)
+	opts := clonePresets[m.clonePreset].opts
+
+	go func() {
+		defer close(job.events)
+		defer cancel()
+		err := giturl.CloneRepoContext(ctx, cloneURL, destPath, opts, func(p giturl.Progress) {
+			// Drop updates the UI hasn't caught up with; the next one
+			// supersedes them anyway
+			select {
+			case job.events <- cloneProgressMsg{job: job, progress: p}:
+			default:
+			}
+		})
+		if err == nil {
+			if err = tmux.CreateSession(job.session, destPath); err != nil {
+				err = fmt.Errorf("cloned but failed to create session: %w", err)
+			}
+		}
+		job.events <- cloneDoneMsg{job: job, err: err}
+	}()
+	return waitClone(job)
+}
+
+// waitClone waits for a job's next event. The handlers re-issue it until
+// the job is done, so one wait per job is pending at a time.
+func waitClone(job *cloneJob) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-job.events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+func (m *Model) handleCloneProgress(msg cloneProgressMsg) tea.Cmd {
+	msg.job.progress = msg.progress
+	msg.job.started = true
+	return waitClone(msg.job)
+}
+
+// handleCloneDone records a finished clone. Once none are running, the
+// last one that succeeded is offered to switch to.
+func (m *Model) handleCloneDone(msg cloneDoneMsg) {
+	job := msg.job
+	job.running = false
+	job.err = msg.err
+	job.canceled = errors.Is(msg.err, context.Canceled)
+
+	if m.clonesRunning() {
+		return
+	}
+	for i := len(m.cloneJobs) - 1; i >= 0; i-- {
+		if done := m.cloneJobs[i]; done.err == nil {
+			m.cloneSuccess = true
+			m.cloneSuccessRepo = done.label
+			m.cloneSuccessPath = done.destPath
+			m.cloneSuccessSession = done.session
+			return
+		}
+	}
+}
+
+// clonesRunning reports whether any clone is still running.
+func (m *Model) clonesRunning() bool {
+	for _, job := range m.cloneJobs {
+		if job.running {
+			return true
+		}
+	}
+	return false
+}
+
+// cancelNewestClone cancels the most recently started clone that is still
+// running (Esc). False if none is.
+func (m *Model) cancelNewestClone() bool {
+	for i := len(m.cloneJobs) - 1; i >= 0; i-- {
+		if job := m.cloneJobs[i]; job.running && !job.canceled {
+			job.canceled = true
+			job.cancel()
+			return true
+		}
+	}
+	return false
+}
+
+// cancelClones cancels all running clones and waits for them to clean up
+// their partial directories, before helm quits.
+func (m *Model) cancelClones() {
+	for _, job := range m.cloneJobs {
+		if job.running {
+			job.cancel()
+			for range job.events {
+			}
+		}
+	}
+}
+
+// cloneJobsHeight is the number of lines renderCloneJobs takes.
+func (m Model) cloneJobsHeight() int {
+	if len(m.cloneJobs) == 0 {
+		return 0
+	}
+	return len(m.cloneJobs) + 1
+}
+
+// cloneProgressWidth is the width of a clone's progress bar.
+const cloneProgressWidth = 20
+
+// renderCloneJobs renders a line per clone — a progress bar while it
+// runs, the outcome after — and a blank line below them.
+func (m Model) renderCloneJobs() string {
+	if len(m.cloneJobs) == 0 {
+		return ""
+	}
+	spinner := ui.ClaudeSpinnerFrames[m.animationFrame%len(ui.ClaudeSpinnerFrames)]
+	var b strings.Builder
+	for _, job := range m.cloneJobs {
+		switch {
+		case job.running && job.canceled:
+			fmt.Fprintf(&b, "  %s %s  canceling...\n", spinner, job.label)
+		case job.running && !job.started:
+			fmt.Fprintf(&b, "  %s %s  connecting...\n", spinner, job.label)
+		case job.running:
+			p := job.progress
+			fmt.Fprintf(&b, "  %s %s  %s %3d%%  %s (%d/%d)\n", spinner, job.label,
+				ui.RenderProgressBar(p.Overall(), cloneProgressWidth), p.Overall(), p.Phase, p.Done, p.Total)
+		case job.canceled:
+			b.WriteString(ui.HintStyle.Render(fmt.Sprintf("  – %s  canceled", job.label)) + "\n")
+		case job.err != nil:
+			// git's own message is on the last line
+			lines := strings.Split(strings.TrimSpace(job.err.Error()), "\n")
+			reason := lines[len(lines)-1]
+			b.WriteString(ui.ErrorMessageStyle.Render(fmt.Sprintf(" ✗ %s  %s", job.label, reason)) + "\n")
+		default:
+			fmt.Fprintf(&b, "  ✓ %s  → %s\n", job.label, job.session)
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestCloneJobs(t *testing.T) {
+	canceled := map[string]bool{}
+	job := func(label string) *cloneJob {
+		return &cloneJob{
+			label:   label,
+			session: label,
+			running: true,
+			cancel:  func() { canceled[label] = true },
+		}
+	}
+	api, web, docs := job("api"), job("web"), job("docs")
+	m := Model{cloneJobs: []*cloneJob{api, web, docs}}
+
+	// Esc cancels the newest running clone, one per press
+	if !m.cancelNewestClone() || !canceled["docs"] {
+		t.Fatalf("first Esc canceled %v, want docs", canceled)
+	}
+	if !m.cancelNewestClone() || !canceled["web"] {
+		t.Fatalf("second Esc canceled %v, want web too", canceled)
+	}
+
+	m.handleCloneDone(cloneDoneMsg{job: docs, err: fmt.Errorf("clone canceled: %w", context.Canceled)})
+	m.handleCloneDone(cloneDoneMsg{job: api, err: nil})
+	if m.cloneSuccess {
+		t.Error("offered to switch while web is still running")
+	}
+
+	// web finished before its cancel took: the last success is offered
+	m.handleCloneDone(cloneDoneMsg{job: web, err: nil})
+	if !m.cloneSuccess || m.cloneSuccessSession != "web" {
+		t.Errorf("cloneSuccess = %v for %q, want web", m.cloneSuccess, m.cloneSuccessSession)
+	}
+	if !docs.canceled || web.canceled {
+		t.Errorf("canceled: docs = %v, web = %v, want true, false", docs.canceled, web.canceled)
+	}
+	if m.cancelNewestClone() {
+		t.Error("canceled a clone with none running")
+	}
+}
+
+func TestCloneJobs_noSuccess(t *testing.T) {
+	failed := &cloneJob{label: "api", running: true}
+	m := Model{cloneJobs: []*cloneJob{failed}}
+
+	m.handleCloneDone(cloneDoneMsg{job: failed, err: errors.New("failed to clone api: exit status 128\nfatal: not found")})
+	if m.cloneSuccess {
+		t.Error("offered to switch with no clone succeeded")
+	}
+	if m.clonesRunning() {
+		t.Error("clonesRunning() after all finished")
+	}
+}
+
+func TestCycleClonePreset(t *testing.T) {
+	var m Model
+	var got []string
+	for range len(clonePresets) + 1 {
+		got = append(got, clonePresets[m.clonePreset].name)
+		m.cycleClonePreset()
+	}
+	want := []string{"full", "shallow", "partial", "sparse", "full"}
+	if !slices.Equal(got, want) {
+		t.Errorf("presets = %v, want %v", got, want)
+	}
+}
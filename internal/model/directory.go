@@ -9,8 +9,10 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/lib/fuzzy"
 	"github.com/black-atom-industries/helm/internal/tmux"
 	"github.com/black-atom-industries/helm/internal/ui"
 )
@@ -58,6 +60,11 @@ func (m *Model) handlePickDirectoryMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m.createSessionFromDir(selected)
 		}
 
+	case key.Matches(msg, keys.Expand):
+		if selected, ok := m.projectList.SelectedItem(); ok && m.pendingSessionName == "" && !m.returnToBookmarks {
+			return m.openWorktreePicker(selected)
+		}
+
 	case key.Matches(msg, keys.Kill):
 		return m.confirmRemoveFolder()
 
@@ -182,10 +189,20 @@ func (m Model) viewPickDirectory() string {
 			b.WriteString(" ")
 		}
 
+		// Highlight matches within the shown part of the path
+		var matches []int
+		if r, ok := fuzzy.ScorePath(fullPath, filter); ok && strings.HasSuffix(fullPath, displayPath) {
+			offset := len([]rune(fullPath)) - len([]rune(displayPath))
+			for _, p := range r.Positions {
+				if p >= offset {
+					matches = append(matches, p-offset)
+				}
+			}
+		}
 		if selected {
-			b.WriteString(ui.FilterStyle.Render(displayPath))
+			b.WriteString(ui.RenderMatches(displayPath, matches, ui.FilterStyle, ui.MatchSelectedStyle.Inherit(ui.FilterStyle)))
 		} else {
-			b.WriteString(displayPath)
+			b.WriteString(ui.RenderMatches(displayPath, matches, lipgloss.NewStyle(), ui.MatchStyle))
 		}
 		b.WriteString("\n")
 	}
@@ -1,12 +1,9 @@
 package model
 
 import (
-	"strings"
-
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/black-atom-industries/helm/internal/lib/filter"
-	"github.com/black-atom-industries/helm/internal/lib/fuzzy"
 	"github.com/black-atom-industries/helm/internal/tmux"
 )
 
@@ -24,10 +21,21 @@ func (m *Model) SessionFilter() *filter.Filter[tmux.Session] {
 	return m.sessionFilter
 }
 
-// SetFilter updates the session filter and rebuilds items.
+// SetFilter updates the session filter and rebuilds items. While filtering,
+// the cursor goes to the first match, the best one.
 func (m *Model) SetFilter(f string) {
 	m.sessionFilter.SetFilter(f)
 	m.rebuildItems()
+	if f == "" {
+		return
+	}
+	for i, item := range m.items {
+		if item.Type == ItemTypeSession && !item.IsSelf {
+			m.cursor = i
+			m.updateScrollOffset()
+			break
+		}
+	}
 }
 
 // Filter returns the current session filter string.
@@ -54,12 +62,3 @@ func (m *Model) HandleFilterKey(msg tea.KeyMsg) bool {
 	}
 	return false
 }
-
-// matchesFilter checks if a name matches the current filter.
-// Returns true if filter is empty or name matches via fuzzy matching.
-func (m *Model) matchesFilter(name string) bool {
-	if m.sessionFilter.Filter() == "" {
-		return true
-	}
-	return fuzzy.Match(name, strings.ToLower(m.sessionFilter.Filter()))
-}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/session"
+)
+
+// Frecency keys: sessions by name (session.FrecencyKey), projects and
+// bookmarks by directory. A session switch records the session's directory
+// too, so the projects you work in rank up in the project picker.
+func pathKey(path string) string { return "path:" + path }
+
+// recordSelection adds a pick of a session (and its directory, if known)
+// to the frecency store that ranks filter matches.
+func (m *Model) recordSelection(sessionName, path string) {
+	if m.frecency == nil {
+		return
+	}
+	now := time.Now()
+	if sessionName != "" {
+		m.frecency.Add(session.FrecencyKey(sessionName), now)
+	}
+	if path != "" {
+		m.frecency.Add(pathKey(path), now)
+	}
+	_ = m.frecency.Save()
+}
+
+// frecencyBoost returns the score points a key's frecency adds to its
+// filter match.
+func (m *Model) frecencyBoost(key string) int {
+	if m.frecency == nil {
+		return 0
+	}
+	return m.frecency.Boost(key, time.Now())
+}
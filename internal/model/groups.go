@@ -0,0 +1,114 @@
+package model
+
+import (
+	"slices"
+
+	"github.com/black-atom-industries/helm/internal/lib/filter"
+	"github.com/black-atom-industries/helm/internal/lib/fuzzy"
+	"github.com/black-atom-industries/helm/internal/session"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// otherGroup collects the sessions without a group once any session has one
+const otherGroup = "other"
+
+// sessionGroup is session.Group with the model's config.
+func (m *Model) sessionGroup(s tmux.Session) string {
+	return session.Group(m.config, s)
+}
+
+// grouped reports whether the session list shows group headers: only when
+// at least one session has a group.
+func (m *Model) grouped() bool {
+	for _, s := range m.sessions {
+		if m.sessionGroup(s) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// groupOf is sessionGroup with the ungrouped sessions under otherGroup.
+func (m *Model) groupOf(s tmux.Session) string {
+	if group := m.sessionGroup(s); group != "" {
+		return group
+	}
+	return otherGroup
+}
+
+// groupSize returns the number of sessions of a group the filter lets
+// through. Pinned sessions are listed above the groups, not in them.
+func (m *Model) groupSize(group string) int {
+	n := 0
+	for _, s := range m.sessions {
+		if m.groupOf(s) == group && m.pinIndex(s.Name) < 0 && m.matchesSession(s) {
+			n++
+		}
+	}
+	return n
+}
+
+// toggleGroup collapses or expands a group. The state is kept in the
+// session cache, so it survives closing the popup.
+func (m *Model) toggleGroup(group string) {
+	if m.collapsedGroups == nil {
+		m.collapsedGroups = make(map[string]bool)
+	}
+	if m.collapsedGroups[group] {
+		delete(m.collapsedGroups, group)
+	} else {
+		m.collapsedGroups[group] = true
+	}
+	m.rebuildItems()
+	m.saveSessionCache()
+}
+
+// groupHeaderIndex returns the items index of a group's header, or -1.
+func (m *Model) groupHeaderIndex(group string) int {
+	return slices.IndexFunc(m.items, func(it Item) bool {
+		return it.Type == ItemTypeGroup && it.Group == group
+	})
+}
+
+// matchSession checks a session against the current filter query (see
+// filter.ParseQuery): its terms against the session's group, state and
+// path, its text against the name. The score ranks the name match, raised
+// by the session's frecency.
+func (m *Model) matchSession(s tmux.Session) (fuzzy.Result, bool) {
+	q := filter.ParseQuery(m.Filter())
+	if !q.Match(func(a filter.Atom) bool { return m.matchAtom(s, a) }) {
+		return fuzzy.Result{}, false
+	}
+	if q.Text == "" {
+		return fuzzy.Result{}, true
+	}
+	r, ok := fuzzy.Score(s.Name, q.Text)
+	if ok {
+		r.Score += m.frecencyBoost(session.FrecencyKey(s.Name))
+	}
+	return r, ok
+}
+
+// matchesSession reports whether a session passes the current filter.
+func (m *Model) matchesSession(s tmux.Session) bool {
+	_, ok := m.matchSession(s)
+	return ok
+}
+
+// listedSessions returns the indices of the sessions the filter lets
+// through, in list order. With query text the best matches come first and
+// ranked is true.
+func (m *Model) listedSessions() (listed []int, ranked bool) {
+	scores := make(map[int]int)
+	for i, s := range m.sessions {
+		if r, ok := m.matchSession(s); ok {
+			listed = append(listed, i)
+			scores[i] = r.Score
+		}
+	}
+	if filter.ParseQuery(m.Filter()).Text == "" {
+		return listed, false
+	}
+	slices.SortStableFunc(listed, func(a, b int) int { return scores[b] - scores[a] })
+	return listed, true
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+func TestGroupedItems(t *testing.T) {
+	m := Model{
+		sessions: []tmux.Session{
+			{Name: "scratch"},
+			{Name: "api", Group: "work"},
+			{Name: "helm", Group: "oss"},
+			{Name: "web", Group: "work"},
+		},
+		sessionFilter: NewFilter[tmux.Session](nil, nil),
+		config:        config.Config{CacheDir: t.TempDir()}, // collapse state lands in the session cache
+	}
+	m.orderSessions()
+	m.rebuildItems()
+
+	// Groups in order of their most recent session, ungrouped ones last
+	want := []string{"@work", "api", "web", "@oss", "helm", "@other", "scratch"}
+	if got := itemLabels(&m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+
+	m.toggleGroup("work")
+	want = []string{"@work", "@oss", "helm", "@other", "scratch"}
+	if got := itemLabels(&m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items with work collapsed = %v, want %v", got, want)
+	}
+
+	// A filter looks into collapsed groups
+	m.SetFilter("@wo")
+	want = []string{"@work", "api", "web"}
+	if got := itemLabels(&m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items for @wo = %v, want %v", got, want)
+	}
+
+	// A name term ranks the matches flat, without headers
+	m.SetFilter("@wo web")
+	want = []string{"web"}
+	if got := itemLabels(&m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items for @wo web = %v, want %v", got, want)
+	}
+}
+
+func TestRankedSessions(t *testing.T) {
+	m := Model{
+		sessions: []tmux.Session{
+			{Name: "a-p-i-x"},
+			{Name: "scratch"},
+			{Name: "api"},
+		},
+		sessionFilter: NewFilter[tmux.Session](nil, nil),
+		selfSession:   &tmux.Session{Name: "self"},
+	}
+	m.rebuildItems()
+
+	m.SetFilter("api")
+	want := []string{"self", "api", "a-p-i-x"}
+	if got := itemLabels(&m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items for api = %v, want %v", got, want)
+	}
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want 1 on the best match", m.cursor)
+	}
+}
+
+// itemLabels names the items of the list: "@group" for headers, else the
+// session name.
+func itemLabels(m *Model) []string {
+	var labels []string
+	for _, item := range m.items {
+		if item.Type == ItemTypeGroup {
+			labels = append(labels, "@"+item.Group)
+		} else {
+			labels = append(labels, m.getSession(item).Name)
+		}
+	}
+	return labels
+}
@@ -10,7 +10,7 @@ import (
 // mode. Text-input modes need "?" as a literal character.
 func (m *Model) helpAvailable() bool {
 	switch m.mode {
-	case ModeCreate, ModeCreatePath, ModeCloneURL, ModeHelp:
+	case ModeCreate, ModeCreatePath, ModeCloneURL, ModeRename, ModeReply, ModeSend, ModeHelp:
 		return false
 	default:
 		return true
@@ -26,6 +26,8 @@ func (m *Model) activeFilter() string {
 		return m.bookmarkList.Filter()
 	case ModePickDirectory:
 		return m.projectList.Filter()
+	case ModePickWorktree:
+		return m.worktreeList.Filter()
 	case ModeCloneRepo:
 		return m.cloneList.Filter()
 	default:
@@ -50,11 +52,18 @@ func (m *Model) helpActions() []ui.Action {
 		return ui.BookmarkActions
 	case ModePickDirectory, ModeConfirmRemoveFolder:
 		return ui.ProjectActions
+	case ModePickWorktree:
+		return ui.WorktreeActions
+	case ModeConfirmPruneWorktree:
+		return ui.ConfirmPruneActions
 	case ModeCloneChoice, ModeCloneRepo:
 		return ui.CloneActions
 	case ModeConfirmKill:
 		return ui.ConfirmKillActions
 	default:
+		if len(m.marks) > 0 {
+			return ui.MarkActions
+		}
 		return ui.SessionActions
 	}
 }
@@ -0,0 +1,339 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+// mark is a session or window marked for a bulk action (Tab).
+type mark struct {
+	Session string
+	Window  int // Window index, -1 for the session itself
+}
+
+// target returns the exact tmux target of a mark: its session's or
+// window's active pane.
+func (k mark) target() string {
+	if k.Window < 0 {
+		return "=" + k.Session + ":"
+	}
+	return fmt.Sprintf("=%s:%d", k.Session, k.Window)
+}
+
+// String names a mark the way tmux targets do: "api" or "api:2".
+func (k mark) String() string {
+	if k.Window < 0 {
+		return k.Session
+	}
+	return fmt.Sprintf("%s:%d", k.Session, k.Window)
+}
+
+// markOf returns the mark for a list item. Only sessions and windows of
+// other sessions can be marked.
+func (m *Model) markOf(item Item) (mark, bool) {
+	session := m.getSession(item)
+	if session == nil || item.IsSelf {
+		return mark{}, false
+	}
+	switch item.Type {
+	case ItemTypeSession:
+		return mark{Session: session.Name, Window: -1}, true
+	case ItemTypeWindow:
+		if window := m.windowAt(item); window != nil {
+			return mark{Session: session.Name, Window: window.Index}, true
+		}
+	}
+	return mark{}, false
+}
+
+// isMarked reports whether an item is marked.
+func (m *Model) isMarked(item Item) bool {
+	k, ok := m.markOf(item)
+	return ok && slices.Contains(m.marks, k)
+}
+
+// toggleMark marks or unmarks the selected item and moves down, so a run
+// of rows is marked by holding Tab.
+func (m *Model) toggleMark() {
+	if !m.isCursorValid() {
+		return
+	}
+	k, ok := m.markOf(m.items[m.cursor])
+	if !ok {
+		return
+	}
+	if i := slices.Index(m.marks, k); i >= 0 {
+		m.marks = slices.Delete(m.marks, i, i+1)
+	} else {
+		m.marks = append(m.marks, k)
+	}
+	if m.cursor < len(m.items)-1 {
+		m.cursor++
+		m.updateScrollOffset()
+	}
+}
+
+// markAll marks every session the filter lets through; when all of them
+// are marked already, it unmarks them instead.
+func (m *Model) markAll() {
+	var listed []mark
+	for _, item := range m.items {
+		if k, ok := m.markOf(item); ok && item.Type == ItemTypeSession {
+			listed = append(listed, k)
+		}
+	}
+	if len(listed) == 0 {
+		return
+	}
+	if !slices.ContainsFunc(listed, func(k mark) bool { return !slices.Contains(m.marks, k) }) {
+		m.marks = slices.DeleteFunc(m.marks, func(k mark) bool { return slices.Contains(listed, k) })
+		return
+	}
+	for _, k := range listed {
+		if !slices.Contains(m.marks, k) {
+			m.marks = append(m.marks, k)
+		}
+	}
+}
+
+// pruneMarks drops the marks of sessions that are gone.
+func (m *Model) pruneMarks() {
+	m.marks = slices.DeleteFunc(m.marks, func(k mark) bool {
+		return !slices.ContainsFunc(m.sessions, func(s tmux.Session) bool { return s.Name == k.Session })
+	})
+}
+
+// markedSessions returns the distinct sessions of the marks, in mark order:
+// a marked window stands for its session.
+func (m *Model) markedSessions() []string {
+	var names []string
+	for _, k := range m.marks {
+		if !slices.Contains(names, k.Session) {
+			names = append(names, k.Session)
+		}
+	}
+	return names
+}
+
+// marksSummary describes the marked set: "3 sessions, 2 windows".
+func (m *Model) marksSummary() string {
+	sessions, windows := 0, 0
+	for _, k := range m.marks {
+		if k.Window < 0 {
+			sessions++
+		} else {
+			windows++
+		}
+	}
+	var parts []string
+	if sessions > 0 {
+		parts = append(parts, plural(sessions, "session"))
+	}
+	if windows > 0 {
+		parts = append(parts, plural(windows, "window"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// plural formats a count with its noun: "1 session", "2 sessions".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// bulkResult formats the outcome of a bulk action: the done message, or
+// the failures after it.
+func (m *Model) bulkResult(done string, errs []string) tea.Cmd {
+	if len(errs) > 0 {
+		m.setError("%s; %d failed: %s", done, len(errs), strings.Join(errs, "; "))
+	} else {
+		m.setMessage("%s", done)
+	}
+	return tea.Batch(m.loadSessions, clearMessageAfter(5*time.Second))
+}
+
+// confirmKillMarked asks to kill the marked set (double C-x).
+func (m *Model) confirmKillMarked() (tea.Model, tea.Cmd) {
+	m.message = fmt.Sprintf("Kill %s?", m.marksSummary())
+	m.messageIsError = false
+	m.mode = ModeConfirmKill
+	return m, nil
+}
+
+// killMarked kills the marked sessions and windows. Windows go first,
+// highest index first, so renumbering can't shift a later target; windows
+// of a marked session go with it.
+func (m *Model) killMarked() (tea.Model, tea.Cmd) {
+	marks := slices.Clone(m.marks)
+	slices.SortFunc(marks, func(a, b mark) int {
+		return cmp.Or(cmp.Compare(b.Window, a.Window), cmp.Compare(a.Session, b.Session))
+	})
+
+	killed := 0
+	var errs []string
+	for _, k := range marks {
+		var err error
+		switch {
+		case k.Window < 0:
+			err = tmux.KillSession("=" + k.Session)
+		case slices.Contains(m.marks, mark{Session: k.Session, Window: -1}):
+			continue
+		default:
+			err = tmux.KillWindow("="+k.Session, k.Window)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", k, err))
+			continue
+		}
+		killed++
+	}
+
+	m.marks = nil
+	m.mode = ModeNormal
+	return m, m.bulkResult(fmt.Sprintf("Killed %d", killed), errs)
+}
+
+// detachTargets detaches the other clients of the marked sessions, or of
+// the selected one. Helm's own client is in the self session, which can't
+// be marked.
+func (m *Model) detachTargets() (tea.Model, tea.Cmd) {
+	names := m.markedSessions()
+	if len(names) == 0 && m.isCursorValid() {
+		if k, ok := m.markOf(m.items[m.cursor]); ok {
+			names = []string{k.Session}
+		}
+	}
+	if len(names) == 0 {
+		return m, nil
+	}
+
+	var errs []string
+	for _, name := range names {
+		if err := tmux.DetachClients(name); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	m.marks = nil
+	return m, m.bulkResult(fmt.Sprintf("Detached clients of %s", plural(len(names), "session")), errs)
+}
+
+// startSend enters ModeSend to type a command for the marked set.
+func (m *Model) startSend() (tea.Model, tea.Cmd) {
+	m.mode = ModeSend
+	m.SetFilter("")
+	m.input.Reset()
+	m.input.CharLimit = 0
+	m.input.Focus()
+	return m, nil
+}
+
+func (m *Model) handleSendMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := ui.DefaultKeyMap
+
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		m.mode = ModeNormal
+		m.input.Blur()
+		return m, nil
+
+	case msg.Type == tea.KeyEnter:
+		text := strings.TrimSpace(m.input.Value())
+		if text == "" {
+			m.setError("Command cannot be empty")
+			return m, nil
+		}
+		m.input.Blur()
+		return m.sendMarked(text)
+
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// sendMarked types a command into the active pane of each marked session
+// or window and presses Enter.
+func (m *Model) sendMarked(text string) (tea.Model, tea.Cmd) {
+	sent := 0
+	var errs []string
+	for _, k := range m.marks {
+		if err := tmux.SendKeys(k.target(), text, true); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", k, err))
+			continue
+		}
+		sent++
+	}
+	m.marks = nil
+	m.mode = ModeNormal
+	return m, m.bulkResult(fmt.Sprintf("Sent to %d", sent), errs)
+}
+
+// lazygitMarked opens lazygit for each marked session in turn — the next
+// popup opens when the previous one closes — then reopens helm.
+func (m *Model) lazygitMarked() (tea.Model, tea.Cmd) {
+	var popups []string
+	for _, name := range m.markedSessions() {
+		path, err := git.GetSessionPath(name)
+		if err != nil || path == "" {
+			continue
+		}
+		popups = append(popups, fmt.Sprintf("tmux display-popup -w%s -h%s -d '%s' -E lazygit",
+			m.config.LazygitPopup.Width, m.config.LazygitPopup.Height, path))
+	}
+	if len(popups) == 0 {
+		m.setError("Could not get the paths of the marked sessions")
+		return m, nil
+	}
+
+	// See openLazygit for why Run and the sleep
+	cmd := fmt.Sprintf("sleep 0.1 && %s; tmux display-popup -w%d -h%d -B -E helm",
+		strings.Join(popups, "; "), m.width, m.height)
+	_ = exec.Command("tmux", "run-shell", "-b", cmd).Run()
+
+	return m, tea.Quit
+}
+
+// bookmarkMarked bookmarks the marked sessions that aren't bookmarked yet.
+func (m *Model) bookmarkMarked() (tea.Model, tea.Cmd) {
+	added, skipped := 0, 0
+	for _, name := range m.markedSessions() {
+		path := m.sessionBookmarkPath(name)
+		if path == "" || m.isBookmarked(name, path) {
+			skipped++
+			continue
+		}
+		m.config.Bookmarks = append(m.config.Bookmarks, config.Bookmark{Path: path})
+		added++
+	}
+
+	if added > 0 {
+		if err := m.config.SaveBookmarks(); err != nil {
+			m.setError("Failed to save config: %v", err)
+			return m, nil
+		}
+	}
+	m.marks = nil
+	if skipped > 0 {
+		m.setMessage("Added %s (%d already bookmarked or without a path)", plural(added, "bookmark"), skipped)
+	} else {
+		m.setMessage("Added %s", plural(added, "bookmark"))
+	}
+	return m, clearMessageAfter(5 * time.Second)
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+func TestMarks(t *testing.T) {
+	m := Model{
+		sessions: []tmux.Session{
+			{Name: "api", Expanded: true, Windows: []tmux.Window{{Index: 1}, {Index: 3}}},
+			{Name: "web"},
+			{Name: "scratch"},
+		},
+		selfSession:   &tmux.Session{Name: "self"},
+		sessionFilter: NewFilter[tmux.Session](nil, nil),
+	}
+	m.rebuildItems()
+	// items: self, api, api:1, api:3, web, scratch
+
+	// The self session can't be marked; Tab moves on after marking
+	m.toggleMark()
+	if len(m.marks) != 0 {
+		t.Errorf("marks = %v, want none for the self session", m.marks)
+	}
+	m.cursor = 2
+	m.toggleMark()
+	m.toggleMark()
+	want := []mark{{"api", 1}, {"api", 3}}
+	if !reflect.DeepEqual(m.marks, want) {
+		t.Errorf("marks = %v, want %v", m.marks, want)
+	}
+	if m.cursor != 4 {
+		t.Errorf("cursor = %d, want 4", m.cursor)
+	}
+	if got := m.marksSummary(); got != "2 windows" {
+		t.Errorf("marksSummary() = %q, want %q", got, "2 windows")
+	}
+
+	// Mark all marks the listed sessions only, then unmarks them
+	m.SetFilter("a")
+	m.markAll()
+	want = []mark{{"api", 1}, {"api", 3}, {"api", -1}, {"scratch", -1}}
+	if !reflect.DeepEqual(m.marks, want) {
+		t.Errorf("marks after markAll = %v, want %v", m.marks, want)
+	}
+	if got := m.markedSessions(); !reflect.DeepEqual(got, []string{"api", "scratch"}) {
+		t.Errorf("markedSessions() = %v, want [api scratch]", got)
+	}
+	m.markAll()
+	want = []mark{{"api", 1}, {"api", 3}}
+	if !reflect.DeepEqual(m.marks, want) {
+		t.Errorf("marks after second markAll = %v, want %v", m.marks, want)
+	}
+
+	// Marks of sessions that are gone are dropped
+	m.sessions = m.sessions[1:]
+	m.pruneMarks()
+	if len(m.marks) != 0 {
+		t.Errorf("marks after prune = %v, want none", m.marks)
+	}
+}
+
+func TestSessionsMsgPrunesMarks(t *testing.T) {
+	m := Model{
+		config:        config.Config{CacheDir: t.TempDir()},
+		sessionFilter: NewFilter[tmux.Session](nil, nil),
+		marks:         []mark{{"api", -1}, {"old-name", -1}, {"killed", 2}},
+	}
+	updated, _ := m.Update(sessionsMsg{sessions: []tmux.Session{{Name: "api"}, {Name: "new-name"}}})
+	if got, want := updated.(Model).marks, []mark{{"api", -1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("marks after reload = %v, want %v", got, want)
+	}
+}
+
+func TestMarkTarget(t *testing.T) {
+	tests := []struct {
+		mark   mark
+		target string
+		name   string
+	}{
+		{mark{"api", -1}, "=api:", "api"},
+		{mark{"api", 2}, "=api:2", "api:2"},
+	}
+	for _, tt := range tests {
+		if got := tt.mark.target(); got != tt.target {
+			t.Errorf("target() = %q, want %q", got, tt.target)
+		}
+		if got := tt.mark.String(); got != tt.name {
+			t.Errorf("String() = %q, want %q", got, tt.name)
+		}
+	}
+}
@@ -2,10 +2,9 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"slices"
 	"strings"
 	"time"
@@ -13,12 +12,19 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
 
 	"github.com/black-atom-industries/helm/internal/agent"
 	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/daemon"
 	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/giturl"
+	"github.com/black-atom-industries/helm/internal/layout"
 	"github.com/black-atom-industries/helm/internal/lib/filter"
+	"github.com/black-atom-industries/helm/internal/lib/frecency"
 	"github.com/black-atom-industries/helm/internal/lib/fuzzy"
+	"github.com/black-atom-industries/helm/internal/session"
+	"github.com/black-atom-industries/helm/internal/snapshot"
 	"github.com/black-atom-industries/helm/internal/tmux"
 	"github.com/black-atom-industries/helm/internal/ui"
 )
@@ -36,8 +42,13 @@ const (
 	ModeCloneRepo
 	ModeCloneURL // Text input for arbitrary repo URL
 	ModeBookmarks
-	ModeCreatePath // Path input for creating session at arbitrary path
-	ModeHelp       // Full-keymap overlay (?)
+	ModeCreatePath           // Path input for creating session at arbitrary path
+	ModeHelp                 // Full-keymap overlay (?)
+	ModeRename               // Text input for renaming the selected session/window
+	ModePickWorktree         // Worktrees of the repo selected in the project picker
+	ModeConfirmPruneWorktree // Offered after killing a worktree's session
+	ModeReply                // Text input for replying to the selected agent
+	ModeSend                 // Text input for a command sent to the marked items
 )
 
 // String returns the display name for the mode (used in title bar)
@@ -61,6 +72,16 @@ func (m Mode) String() string {
 		return "REMOVE"
 	case ModeHelp:
 		return "HELP"
+	case ModeRename:
+		return "RENAME"
+	case ModePickWorktree:
+		return "WORKTREES"
+	case ModeConfirmPruneWorktree:
+		return "PRUNE"
+	case ModeReply:
+		return "REPLY"
+	case ModeSend:
+		return "SEND"
 	default:
 		return "SESSIONS"
 	}
@@ -73,24 +94,29 @@ const (
 	ItemTypeSession ItemType = iota
 	ItemTypeWindow
 	ItemTypePane
+	ItemTypeGroup // Collapsible header above a group's sessions
 )
 
-// Item represents a session, window, or pane in the flattened list
+// Item represents a session, window, pane, or group header in the flattened list
 type Item struct {
 	Type         ItemType
-	SessionIndex int  // Index in the sessions slice
-	WindowIndex  int  // Index in the session's windows slice (for windows and panes)
-	PaneIndex    int  // Index in the window's panes slice (for panes only)
-	IsSelf       bool // True if this item belongs to the current/self session
+	SessionIndex int    // Index in the sessions slice
+	WindowIndex  int    // Index in the session's windows slice (for windows and panes)
+	PaneIndex    int    // Index in the window's panes slice (for panes only)
+	IsSelf       bool   // True if this item belongs to the current/self session
+	Group        string // Group name (for group headers only)
 }
 
 // Model is the main application state
 type Model struct {
 	sessions          []tmux.Session
-	selfSession       *tmux.Session // The current/self session (pinned at top)
-	claudeStatuses    map[string][]agent.Status
-	piStatuses        map[string][]agent.Status
-	paneAgents        map[int]string // pane shell PID → agent kind name
+	selfSession       *tmux.Session                        // The current/self session (pinned at top)
+	agents            agent.Registry                       // Known agent clients
+	agentKinds        []agent.Kind                         // Kinds with status tracking enabled
+	agentStatuses     map[string]map[string][]agent.Status // kind name → session → instances
+	paneAgents        map[int]string                       // pane shell PID → agent kind name
+	agentAlive        map[string]bool                      // session → any known agent running, for is:reapable; nil until checked
+	transcripts       map[string]agent.Transcript          // transcript path → parsed summary
 	gitStatuses       map[string]git.Status
 	currentSession    string
 	cursor            int
@@ -100,11 +126,18 @@ type Model struct {
 	messageIsError    bool
 	input             textinput.Model
 	killTarget        string // Name of session/window being killed
+	renameSession     string // Session being renamed (or owning the window)
+	renameWindow      int    // Window index being renamed, -1 for the session itself
 	removeTarget      string // Full path of folder being removed
 	config            config.Config
 	maxNameWidth      int                          // For column alignment
 	maxGitStatusWidth int                          // For git status column alignment
 	sessionFilter     *filter.Filter[tmux.Session] // Session filter (shared filter logic)
+	collapsedGroups   map[string]bool              // Collapsed group headers (persisted in the session cache)
+	pins              []string                     // Pinned session names in slot order (pins.json)
+	frecency          *frecency.Store              // Past picks, ranking filter matches
+	sortMode          string                       // Session list order, one of config.SortModes
+	marks             []mark                       // Items marked for a bulk action, in mark order
 
 	// Directory picker state (uses ScrollList for cursor/scroll/filter)
 	projectList        *ui.ScrollList[string]
@@ -113,6 +146,26 @@ type Model struct {
 	returnToBookmarks  bool   // True if we should return to bookmarks mode after project picker
 	pendingSessionName string // Session name pending directory selection (for create-from-filter flow)
 
+	// Worktree picker state (ModePickWorktree, sub-mode of the project picker)
+	worktreeRepo     string // Main worktree path of the repo being browsed
+	worktreeList     *ui.ScrollList[git.Worktree]
+	worktreesLoading bool
+	worktreeAdding   string // Branch whose worktree is being added
+	pruneTarget      string // Worktree offered for pruning after its session was killed
+	pruneRepo        string // Main worktree of pruneTarget
+
+	// Agent reply state (ModeReply)
+	replyKind   string   // Agent kind being replied to
+	replyState  string   // Its state when the reply started
+	replyTarget string   // tmux target of the agent's pane
+	replyTail   []string // Last lines of the pane, for context
+	replyCanned int      // Index of the canned reply in the input, -1 for none
+
+	// Pane preview state (the side panel's alternative to AGENTS)
+	showPreview   bool     // Preview instead of the AGENTS panel (C-v toggles)
+	preview       []string // Captured lines of previewTarget, nil while loading
+	previewTarget string   // tmux target the preview shows
+
 	// Path input state (for ModeCreatePath)
 	pathInput       textinput.Model // Text input for path entry
 	pathCompletions []string        // Available path completions
@@ -129,16 +182,17 @@ type Model struct {
 
 	// Clone mode state
 	cloneChoiceCursor   int // 0 = Enter URL, 1 = My repos
-	cloneList           *ui.ScrollList[string]
-	cloneBasePath       string // From config.ProjectDirs
-	cloneLoading        bool   // True while fetching repos
-	cloneError          string // Error message if fetch/clone fails
-	cloneCloning        bool   // True while cloning
-	cloneCloningRepo    string // Repo being cloned
-	cloneSuccess        bool   // True when clone completed, awaiting confirmation
-	cloneSuccessPath    string // Path of cloned repo (for layout)
-	cloneSuccessSession string // Session name to switch to
-	clonePendingFilter  string // Filter to apply once repos are loaded
+	cloneList           *ui.ScrollList[giturl.Repo]
+	cloneBasePath       string      // From config.ProjectDirs
+	cloneLoading        bool        // True while fetching repos
+	cloneError          string      // Error message if fetch/clone fails
+	cloneJobs           []*cloneJob // Clones started in the clone views, oldest first
+	cloneSuccess        bool        // True when clones completed, awaiting confirmation
+	cloneSuccessRepo    string      // Repo cloned last
+	cloneSuccessPath    string      // Path of cloned repo (for layout)
+	cloneSuccessSession string      // Session name to switch to
+	clonePendingFilter  string      // Filter to apply once repos are loaded
+	clonePreset         int         // Index into clonePresets, cycled with Tab
 
 	// Bookmarks mode state (uses ScrollList for cursor/scroll/filter)
 	bookmarkList     *ui.ScrollList[config.Bookmark]
@@ -151,6 +205,14 @@ type Model struct {
 	gitStatusPending     map[string]bool      // Sessions still being fetched (by name)
 	gitStatusShowLoading bool                 // True after 500ms delay if still loading
 	gitStatusFetched     map[string]time.Time // Last fetch per session, for the TTL cache
+
+	// Snapshot auto-save state
+	snapshotSavedAt time.Time // Last snapshot save (seeded from the file's mtime)
+
+	statusWatcher   *fsnotify.Watcher // Cache dir watcher; nil falls back to polling
+	statusPollGen   int               // Generation of the pending status poll tick
+	statusPollSlow  bool              // Pending tick is at the sweep interval
+	livenessSweptAt time.Time         // Last full status poll with liveness check
 }
 
 // New creates a new Model
@@ -179,28 +241,43 @@ func New(currentSession string, cfg config.Config, initialView string) Model {
 	pathInput.Prompt = ""
 	pathInput.CharLimit = 256
 
-	// Create project list with filter function using segment-aware matching
+	// Past picks raise filter matches; shared by the list closures below
+	store := frecency.Load(cfg.CacheDir)
+	boost := func(path string) int { return store.Boost(pathKey(path), time.Now()) }
+
+	// Create project list ranked by segment-aware matching and frecency
 	// Uses full path (relative to base dir) for matching — no depth truncation
-	projectList := ui.NewScrollList(func(fullPath string, filter string) bool {
-		return fuzzy.MatchPath(fullPath, filter)
+	projectList := ui.NewRankedScrollList(func(fullPath string, filter string) (int, bool) {
+		r, ok := fuzzy.ScorePath(fullPath, filter)
+		return r.Score + boost(fullPath), ok
 	})
 
-	// Create clone list with filter function using segment-aware matching
-	cloneList := ui.NewScrollList(func(repo string, filter string) bool {
-		return fuzzy.MatchPath(repo, filter)
+	// Create clone list ranked by segment-aware matching on host/path
+	cloneList := ui.NewRankedScrollList(func(repo giturl.Repo, filter string) (int, bool) {
+		r, ok := fuzzy.ScorePath(repo.String(), filter)
+		return r.Score, ok
 	})
 
-	// Create bookmark list with filter function using segment-aware matching
-	bookmarkList := ui.NewScrollList(func(b config.Bookmark, filter string) bool {
+	// Create bookmark list ranked by segment-aware matching and frecency
+	bookmarkList := ui.NewRankedScrollList(func(b config.Bookmark, filter string) (int, bool) {
 		// Normalize path: strip leading slash to avoid empty first segment
 		path := strings.TrimPrefix(b.Path, "/")
-		return fuzzy.MatchPath(path, filter)
+		r, ok := fuzzy.ScorePath(path, filter)
+		return r.Score + boost(b.Path), ok
+	})
+
+	// Create worktree list, ranked by branch name
+	worktreeList := ui.NewRankedScrollList(func(wt git.Worktree, filter string) (int, bool) {
+		r, ok := fuzzy.ScorePath(worktreeLabel(wt), filter)
+		return r.Score, ok
 	})
 
+	// Sessions are matched and ranked in rebuildItems (see matchSession)
 	sessionFilter := filter.New([]tmux.Session{}, func(s tmux.Session, f string) bool {
 		return fuzzy.Match(s.Name, f)
 	})
 
+	agents := agent.NewRegistry(cfg)
 	m := Model{
 		currentSession:   currentSession,
 		mode:             ParseInitialView(initialView),
@@ -210,8 +287,19 @@ func New(currentSession string, cfg config.Config, initialView string) Model {
 		projectList:      projectList,
 		cloneList:        cloneList,
 		bookmarkList:     bookmarkList,
+		worktreeList:     worktreeList,
 		sessionFilter:    sessionFilter,
 		bookmarkExpanded: make(map[string]bool),
+		agents:           agents,
+		agentKinds:       agents.Enabled(),
+		pins:             session.LoadPins(cfg.CacheDir),
+		frecency:         store,
+		sortMode:         cfg.SessionSort,
+	}
+	// The preview takes the side panel's place when there is no AGENTS panel
+	m.showPreview = len(m.agentKinds) == 0
+	if len(m.agentKinds) > 0 {
+		m.statusWatcher = newStatusWatcher(cfg.CacheDir)
 	}
 
 	// Populate data for non-default initial views
@@ -222,9 +310,16 @@ func New(currentSession string, cfg config.Config, initialView string) Model {
 		m.bookmarkList.SetItems(cfg.Bookmarks)
 	}
 
+	// Seed auto-save from the existing snapshot so reopening the popup
+	// doesn't save on every launch
+	if info, err := os.Stat(snapshot.Path(cfg.CacheDir)); err == nil {
+		m.snapshotSavedAt = info.ModTime()
+	}
+
 	// Load cached sessions for instant startup
 	if cached := m.loadSessionCache(); cached != nil {
 		m.sessions = cached
+		m.orderSessions()
 		m.sessionFilter.SetItems(m.sessions)
 		m.sessionsLoaded = true
 		m.calculateColumnWidths()
@@ -240,7 +335,7 @@ func New(currentSession string, cfg config.Config, initialView string) Model {
 
 // Init implements tea.Model
 func (m Model) Init() tea.Cmd {
-	cmds := []tea.Cmd{m.loadSessions, animationTick(), statusPollTick()}
+	cmds := []tea.Cmd{m.loadSessions, animationTick(), statusPollTick(time.Second, m.statusPollGen), m.watchStatusesCmd()}
 	if m.projectsLoading {
 		cmds = append(cmds, m.scanProjectsCmd())
 	}
@@ -346,13 +441,23 @@ type clearMessageMsg struct{}
 
 type animationTickMsg struct{}
 
-type statusPollMsg struct{}
+type statusPollMsg struct {
+	gen int // generation of the tick, see nextStatusPoll
+}
 
 // gitStatusSingleMsg is sent when a single session's git status is ready
 type gitStatusSingleMsg struct {
 	sessionName string
 	status      git.Status
-	hasStatus   bool // true if status should be shown (repo with changes)
+	hasStatus   bool // false outside a git repo
+}
+
+// daemonStateMsg carries the state of a running helm daemon, asked for
+// the git statuses of stale sessions. err is set when no daemon answered.
+type daemonStateMsg struct {
+	snap  daemon.Snapshot
+	stale []tmux.Session
+	err   error
 }
 
 // gitStatusLoadingMsg is sent after 500ms to show loading indicator
@@ -372,24 +477,48 @@ func animationTick() tea.Cmd {
 	})
 }
 
-// statusPollTick returns a command that re-reads agent status files.
+// statusPollTick drives the periodic work: the liveness sweep of agent
+// statuses, snapshot auto-save and the reply/preview refresh.
 // Separate from animationTick so the 300ms animation never hits disk.
-func statusPollTick() tea.Cmd {
-	return tea.Tick(time.Second, func(time.Time) tea.Msg {
-		return statusPollMsg{}
+// Ticks of an earlier generation are dropped, see nextStatusPoll.
+func statusPollTick(interval time.Duration, gen int) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return statusPollMsg{gen: gen}
 	})
 }
 
+// nextStatusPoll schedules the next status poll. While the watcher delivers
+// status changes only the liveness sweep is left to poll for, so the tick
+// slows down to the sweep interval — unless the reply tail or the preview
+// needs refreshing every second.
+func (m *Model) nextStatusPoll() tea.Cmd {
+	interval := time.Second
+	m.statusPollSlow = m.statusWatcher != nil && !m.fastStatusPoll()
+	if m.statusPollSlow {
+		interval = livenessSweepInterval
+	}
+	m.statusPollGen++
+	return statusPollTick(interval, m.statusPollGen)
+}
+
+// fastStatusPoll reports whether something on screen refreshes with every
+// status poll.
+func (m *Model) fastStatusPoll() bool {
+	return m.mode == ModeReply || m.previewVisible()
+}
+
 // Update implements tea.Model
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case sessionsMsg:
 		m.sessions = msg.sessions
-		m.sessionFilter.SetItems(m.sessions)
 		m.selfSession = msg.selfSession
+		m.loadAgentStatuses() // before ordering: the urgency sort needs them
+		m.orderSessions()
+		m.pruneMarks() // Sessions renamed or killed outside helm
+		m.sessionFilter.SetItems(m.sessions)
 		m.sessionsLoaded = true
 		m.saveSessionCache() // Cache for instant startup next time
-		m.loadAgentStatuses()
 		// Initialize git statuses map (will be populated async)
 		if m.gitStatuses == nil {
 			m.gitStatuses = make(map[string]git.Status)
@@ -401,9 +530,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.calculateColumnWidths()
 		m.rebuildItems()
 		// Place cursor on the first regular (non-self) session
-		if m.selfSession != nil {
+		if m.selfSession != nil || m.grouped() {
 			for i, item := range m.items {
-				if !item.IsSelf {
+				if !item.IsSelf && item.Type == ItemTypeSession {
 					m.cursor = i
 					break
 				}
@@ -430,14 +559,64 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, animationTick()
 
 	case statusPollMsg:
-		return m, tea.Batch(m.pollAgentStatusesCmd(), statusPollTick())
+		if msg.gen != m.statusPollGen {
+			return m, nil // replaced by a faster tick
+		}
+		saveCmd := m.autoSaveSnapshotCmd() // records the save time on m
+		var pollCmd, checkCmd, tailCmd tea.Cmd
+		if m.livenessSweepDue() {
+			pollCmd = m.pollAgentStatusesCmd(true)
+			if m.queriesReapable() {
+				checkCmd = m.checkAgentsCmd()
+			}
+		}
+		if m.mode == ModeReply {
+			tailCmd = m.captureReplyTailCmd()
+		}
+		return m, tea.Batch(pollCmd, checkCmd, saveCmd, tailCmd, m.refreshPreviewCmd(true), m.nextStatusPoll())
+
+	case statusFilesChangedMsg:
+		return m, tea.Batch(m.pollAgentStatusesCmd(false), m.watchStatusesCmd())
+
+	case statusWatcherClosedMsg:
+		m.statusWatcher = nil
+		return m, m.nextStatusPoll()
 
 	case agentStatusesMsg:
-		m.claudeStatuses = msg.claude
-		m.piStatuses = msg.pi
+		m.agentStatuses = msg.statuses
 		if msg.paneAgents != nil {
 			m.paneAgents = msg.paneAgents
 		}
+		if m.sortMode == config.SortUrgency {
+			m.resort()
+		}
+		return m, m.loadTranscriptsCmd()
+
+	case agentLivenessMsg:
+		m.agentAlive = msg
+		if m.queriesReapable() {
+			m.rebuildItems()
+		}
+		return m, nil
+
+	case transcriptsMsg:
+		m.transcripts = msg
+		return m, nil
+
+	case previewMsg:
+		if msg.target == m.previewTarget {
+			m.preview = msg.lines
+		}
+		return m, nil
+
+	case replyTailMsg:
+		if msg.target != m.replyTarget {
+			return m, nil // reply target changed meanwhile
+		}
+		m.replyTail = msg.lines
+		if msg.err != nil {
+			m.replyTail = []string{ui.HelpDescStyle.Render(fmt.Sprintf("Failed to capture pane: %v", msg.err))}
+		}
 		return m, nil
 
 	case projectsLoadedMsg:
@@ -445,6 +624,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.projectList.SetItems(msg.projects)
 		return m, nil
 
+	case worktreesLoadedMsg:
+		m.worktreesLoading = false
+		if msg.err != nil {
+			m.setError("Failed to list worktrees: %v", msg.err)
+		}
+		m.worktreeList.SetItems(msg.worktrees)
+		return m, nil
+
+	case worktreeAddedMsg:
+		return m.handleWorktreeAdded(msg)
+
 	case cloneReposLoadedMsg:
 		m.cloneLoading = false
 		m.cloneList.SetItems(msg.repos)
@@ -455,26 +645,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(msg.repos) == 0 {
 			m.cloneError = "All repositories are already cloned!"
 		}
+		if len(msg.errs) > 0 {
+			m.setError("Some providers failed: %s", joinErrors(msg.errs))
+		}
 		return m, nil
 
 	case cloneErrorMsg:
 		m.cloneLoading = false
-		m.cloneCloning = false
 		m.cloneError = msg.err.Error()
 		return m, nil
 
-	case cloneSuccessMsg:
-		// Store success state and await confirmation
-		m.cloneCloning = false
-		m.cloneSuccess = true
-		m.cloneSuccessPath = msg.repoPath
-		m.cloneSuccessSession = msg.sessionName
+	case cloneProgressMsg:
+		return m, m.handleCloneProgress(msg)
+
+	case cloneDoneMsg:
+		// Once all are done, store success state and await confirmation
+		m.handleCloneDone(msg)
 		return m, nil
 
 	case gitStatusSingleMsg:
 		// Single git status loaded - update incrementally
 		if msg.hasStatus {
 			m.gitStatuses[msg.sessionName] = msg.status
+		} else {
+			delete(m.gitStatuses, msg.sessionName)
 		}
 		delete(m.gitStatusPending, msg.sessionName)
 		if len(m.gitStatusPending) == 0 {
@@ -482,6 +676,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case daemonStateMsg:
+		if msg.err != nil {
+			return m, m.fetchLocalGitStatusesCmd(msg.stale)
+		}
+		missing := m.applyDaemonState(msg.snap, msg.stale)
+		if m.sortMode == config.SortUrgency {
+			m.resort()
+		}
+		return m, tea.Batch(m.fetchLocalGitStatusesCmd(missing), m.loadTranscriptsCmd())
+
 	case gitStatusLoadingMsg:
 		// 500ms elapsed - show loading indicator if still fetching
 		if len(m.gitStatusPending) > 0 {
@@ -495,7 +699,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		return m.handleKey(msg)
+		model, cmd := m.handleKey(msg)
+		// A moved cursor shows a new preview right away, not on the next poll
+		cmds := []tea.Cmd{cmd, m.refreshPreviewCmd(false)}
+		// Opening the preview or reply mode doesn't wait out a slow tick
+		if m.statusPollSlow && m.fastStatusPoll() {
+			cmds = append(cmds, m.nextStatusPoll())
+		}
+		return model, tea.Batch(cmds...)
 	}
 
 	// Handle text input updates in create mode
@@ -535,8 +746,18 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleCreateMode(msg)
 	case ModeCreatePath:
 		return m.handleCreatePathMode(msg)
+	case ModeRename:
+		return m.handleRenameMode(msg)
+	case ModeReply:
+		return m.handleReplyMode(msg)
+	case ModeSend:
+		return m.handleSendMode(msg)
 	case ModePickDirectory:
 		return m.handlePickDirectoryMode(msg)
+	case ModePickWorktree:
+		return m.handlePickWorktreeMode(msg)
+	case ModeConfirmPruneWorktree:
+		return m.handleConfirmPruneWorktreeMode(msg)
 	case ModeConfirmRemoveFolder:
 		return m.handleConfirmRemoveFolderMode(msg)
 	case ModeCloneChoice:
@@ -576,6 +797,9 @@ func (m *Model) allSessions() []tmux.Session {
 // getSession returns the session for a given item (handles self session).
 // Returns nil if the item's index no longer matches the session list.
 func (m *Model) getSession(item Item) *tmux.Session {
+	if item.Type == ItemTypeGroup {
+		return nil
+	}
 	if item.IsSelf {
 		return m.selfSession
 	}
@@ -614,110 +838,169 @@ func (m *Model) findSessionByName(name string) *tmux.Session {
 	return nil
 }
 
+// applyLayout shapes a newly created session via its session template or
+// the global layout script (see layout.Apply).
 func (m *Model) applyLayout(sessionName, workingDir string) {
-	if !m.config.EnableLayouts || m.config.Layout == "" {
-		return
-	}
-
-	scriptPath := fmt.Sprintf("%s/%s.sh", m.config.LayoutDir, m.config.Layout)
-	if _, err := os.Stat(scriptPath); err != nil {
-		m.setError("Layout script not found: %s", scriptPath)
-		return
-	}
-
-	// Run layout script synchronously before switching to the session
-	cmd := exec.Command(scriptPath, sessionName, workingDir)
-	cmd.Env = append(os.Environ(),
-		"TMUX_SESSION="+sessionName,
-		"TMUX_WORKING_DIR="+workingDir,
-	)
-	if err := cmd.Run(); err != nil {
-		m.setError("Layout %q failed: %v", m.config.Layout, err)
+	err := layout.Apply(m.config, sessionName, workingDir)
+	switch {
+	case errors.Is(err, layout.ErrUntrusted):
+		m.setError("%v", err)
+	case err != nil:
+		m.setError("Layout failed: %v", err)
 	}
 }
 
 // loadAgentStatuses refreshes the cached agent statuses for all sessions.
 func (m *Model) loadAgentStatuses() {
-	m.claudeStatuses = m.readAgentStatuses(agent.Claude, m.config.ClaudeStatusEnabled)
-	m.piStatuses = m.readAgentStatuses(agent.Pi, m.config.PiStatusEnabled)
+	m.agentStatuses = m.readAgentStatuses()
 }
 
 // agentStatusesMsg carries freshly polled agent statuses
 type agentStatusesMsg struct {
-	claude     map[string][]agent.Status
-	pi         map[string][]agent.Status
-	paneAgents map[int]string // pane shell PID → agent kind name
+	statuses   map[string]map[string][]agent.Status // kind name → session → instances
+	paneAgents map[int]string                       // pane shell PID → agent kind name
 }
 
-// pollAgentStatusesCmd is the periodic status refresh, run off the UI
-// thread: re-reads status files, prunes files of sessions that no longer
-// exist, and drops statuses whose agent process is gone — hooks don't fire
-// on crash or SIGKILL, so a status file alone proves nothing.
-func (m Model) pollAgentStatusesCmd() tea.Cmd {
-	if !m.config.ClaudeStatusEnabled && !m.config.PiStatusEnabled {
+// pollAgentStatusesCmd refreshes the agent statuses off the UI thread.
+// The periodic sweep also prunes files of sessions that no longer exist
+// and drops statuses whose agent process is gone — hooks don't fire on
+// crash or SIGKILL, so a status file alone proves nothing. A refresh for a
+// status file change skips both, and only checks liveness when a new
+// instance appeared, to attribute it to its pane.
+func (m Model) pollAgentStatusesCmd(sweep bool) tea.Cmd {
+	if len(m.agentKinds) == 0 {
 		return nil
 	}
+	prev := m.agentStatuses
 	return func() tea.Msg {
-		if m.sessionsLoaded {
+		if sweep && m.sessionsLoaded {
 			names := make([]string, 0, len(m.sessions)+1)
 			for _, s := range m.allSessions() {
 				names = append(names, s.Name)
 			}
-			for _, kind := range agent.Kinds {
+			for _, kind := range m.agents.Kinds() {
 				agent.CleanupStale(kind, m.config.CacheDir, names)
 			}
 		}
 
-		claudeStatuses := m.readAgentStatuses(agent.Claude, m.config.ClaudeStatusEnabled)
-		piStatuses := m.readAgentStatuses(agent.Pi, m.config.PiStatusEnabled)
+		statuses := m.readAgentStatuses()
 		var paneAgents map[int]string
 
 		// Only spawn tmux/ps when something claims to be running
-		if len(claudeStatuses)+len(piStatuses) > 0 {
+		running := false
+		for _, bySession := range statuses {
+			running = running || len(bySession) > 0
+		}
+		if running && (sweep || newInstances(prev, statuses)) {
 			if panePIDs, err := tmux.PanePIDs(); err == nil {
-				if live, err := agent.CheckLiveness(panePIDs); err == nil {
-					dropDeadStatuses(agent.Claude, claudeStatuses, live, m.config.CacheDir)
-					dropDeadStatuses(agent.Pi, piStatuses, live, m.config.CacheDir)
+				if live, err := m.agents.CheckLiveness(panePIDs); err == nil {
+					for _, kind := range m.agentKinds {
+						agent.DropDead(kind, statuses[kind.Name], live, m.config.CacheDir)
+					}
 					paneAgents = live.PaneAgents()
 				}
 			}
 		}
 
-		return agentStatusesMsg{claude: claudeStatuses, pi: piStatuses, paneAgents: paneAgents}
+		return agentStatusesMsg{statuses: statuses, paneAgents: paneAgents}
 	}
 }
 
-// dropDeadStatuses removes statuses (and their files) for sessions where no
-// matching agent process is running.
-func dropDeadStatuses(kind agent.Kind, statuses map[string][]agent.Status, live agent.Liveness, cacheDir string) {
-	for name := range statuses {
-		if !live.Alive(kind, name) {
-			delete(statuses, name)
-			agent.RemoveStatuses(kind, name, cacheDir)
+// newInstances reports whether cur has an agent instance prev didn't.
+func newInstances(prev, cur map[string]map[string][]agent.Status) bool {
+	for kind, bySession := range cur {
+		for session, instances := range bySession {
+			if len(instances) > len(prev[kind][session]) {
+				return true
+			}
 		}
 	}
+	return false
 }
 
-func (m *Model) readAgentStatuses(kind agent.Kind, enabled bool) map[string][]agent.Status {
-	statuses := make(map[string][]agent.Status)
-	if !enabled {
-		return statuses
+// transcriptsMsg carries the parsed transcripts of all live agents.
+type transcriptsMsg map[string]agent.Transcript
+
+// loadTranscriptsCmd parses the transcripts of all live agent instances off
+// the UI thread, so the panel preview is ready whichever session the cursor
+// moves to. Reads continue from the previous parse — only appended lines
+// are read — and transcripts of agents that are gone are dropped.
+func (m Model) loadTranscriptsCmd() tea.Cmd {
+	prev := make(map[string]agent.Transcript)
+	for _, bySession := range m.agentStatuses {
+		for _, instances := range bySession {
+			for _, s := range instances {
+				if s.Transcript != "" {
+					prev[s.Transcript] = m.transcripts[s.Transcript]
+				}
+			}
+		}
 	}
-	for _, s := range m.allSessions() {
-		if instances := agent.GetStatuses(kind, s.Name, m.config.CacheDir); len(instances) > 0 {
-			statuses[s.Name] = instances
+	if len(prev) == 0 && len(m.transcripts) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		transcripts := make(transcriptsMsg, len(prev))
+		for path, t := range prev {
+			if t, err := agent.ReadTranscript(path, t); err == nil {
+				transcripts[path] = t
+			}
 		}
+		return transcripts
+	}
+}
+
+// autoSaveSnapshotCmd saves a session snapshot in the background once the
+// configured auto-save interval has passed since the last save.
+func (m *Model) autoSaveSnapshotCmd() tea.Cmd {
+	interval := m.config.Snapshot.AutoSaveInterval
+	if interval <= 0 || time.Since(m.snapshotSavedAt) < interval {
+		return nil
+	}
+	m.snapshotSavedAt = time.Now()
+	path := snapshot.Path(m.config.CacheDir)
+	return func() tea.Msg {
+		if snap, err := snapshot.Capture(); err == nil {
+			_ = snapshot.Save(snap, path)
+		}
+		return nil
+	}
+}
+
+// readAgentStatuses reads the statuses of every enabled kind for all
+// sessions, keyed by kind name.
+func (m *Model) readAgentStatuses() map[string]map[string][]agent.Status {
+	names := make([]string, 0, len(m.sessions)+1)
+	for _, s := range m.allSessions() {
+		names = append(names, s.Name)
+	}
+	statuses := make(map[string]map[string][]agent.Status, len(m.agentKinds))
+	for _, kind := range m.agentKinds {
+		statuses[kind.Name] = agent.ReadAll(kind, names, m.config.CacheDir)
 	}
 	return statuses
 }
 
+// topAgentStatuses returns the most-active status of each agent kind in a
+// session, for the status icon columns.
+func (m *Model) topAgentStatuses(sessionName string) map[string]*agent.Status {
+	top := make(map[string]*agent.Status)
+	for kind, bySession := range m.agentStatuses {
+		// Statuses are sorted most-active first — [0] drives the glyph
+		if statuses := bySession[sessionName]; len(statuses) > 0 {
+			top[kind] = &statuses[0]
+		}
+	}
+	return top
+}
+
 // gitStatusTTL is how long a fetched git status stays fresh. Session
 // reloads within this window reuse the cached result instead of spawning
 // another round of git subprocesses.
 const gitStatusTTL = 10 * time.Second
 
-// fetchGitStatusesCmd returns commands that fetch git statuses in parallel
-// Each session's status is fetched independently and updates the UI as soon as ready
+// fetchGitStatusesCmd returns commands that fetch the git statuses whose
+// cached result has expired
 func (m *Model) fetchGitStatusesCmd() tea.Cmd {
 	if !m.config.GitStatusEnabled {
 		return nil
@@ -747,29 +1030,104 @@ func (m *Model) fetchGitStatusesCmd() tea.Cmd {
 		m.gitStatusFetched[s.Name] = now
 	}
 
-	// Create a command for each session - they run in parallel via tea.Batch
-	cmds := make([]tea.Cmd, 0, len(stale)+1)
+	// A running helm daemon already has every status warm: one state call
+	// answers for all sessions. Without one, each session is fetched on its
+	// own, see fetchLocalGitStatusesCmd.
+	socket := daemon.SocketPath(m.config.CacheDir)
+	stateCmd := func() tea.Msg {
+		snap, err := daemon.State(socket)
+		return daemonStateMsg{snap: snap, stale: stale, err: err}
+	}
 
 	// Add delayed loading indicator (500ms)
-	cmds = append(cmds, tea.Tick(500*time.Millisecond, func(time.Time) tea.Msg {
+	return tea.Batch(stateCmd, tea.Tick(500*time.Millisecond, func(time.Time) tea.Msg {
 		return gitStatusLoadingMsg{}
 	}))
+}
 
+// applyDaemonState takes the git statuses of the stale sessions and all
+// agent statuses from a daemon snapshot. It returns the stale sessions
+// the snapshot doesn't know yet, e.g. ones created since its last refresh.
+func (m *Model) applyDaemonState(snap daemon.Snapshot, stale []tmux.Session) []tmux.Session {
+	var missing []tmux.Session
 	for _, s := range stale {
+		ds, ok := snap.Session(s.Name)
+		if !ok {
+			missing = append(missing, s)
+			continue
+		}
+		if ds.Git != nil {
+			m.gitStatuses[s.Name] = gitStatusFromDaemon(*ds.Git)
+		} else {
+			delete(m.gitStatuses, s.Name)
+		}
+		delete(m.gitStatusPending, s.Name)
+	}
+	if len(m.gitStatusPending) == 0 {
+		m.gitStatusShowLoading = false
+	}
+
+	if len(m.agentKinds) > 0 {
+		statuses := make(map[string]map[string][]agent.Status, len(m.agentKinds))
+		for _, kind := range m.agentKinds {
+			statuses[kind.Name] = make(map[string][]agent.Status)
+		}
+		for _, ds := range snap.Sessions {
+			for _, a := range ds.Agents {
+				if bySession, ok := statuses[a.Kind]; ok {
+					bySession[ds.Name] = append(bySession[ds.Name], agent.Status{
+						State:      a.State,
+						Timestamp:  a.Since,
+						Tool:       a.Tool,
+						SessionID:  a.SessionID,
+						Transcript: a.Transcript,
+						Cwd:        a.Cwd,
+					})
+				}
+			}
+		}
+		m.agentStatuses = statuses
+		if snap.PaneAgents != nil {
+			m.paneAgents = snap.PaneAgents
+		}
+	}
+	return missing
+}
+
+// gitStatusFromDaemon converts the daemon's git status of a repo.
+func gitStatusFromDaemon(g daemon.Git) git.Status {
+	return git.Status{
+		IsRepo:    true,
+		Dirty:     g.Dirty,
+		Additions: g.Additions,
+		Deletions: g.Deletions,
+		Branch:    g.Branch,
+		Upstream:  g.Upstream,
+		Ahead:     g.Ahead,
+		Behind:    g.Behind,
+		Stash:     g.Stash,
+	}
+}
+
+// fetchLocalGitStatusesCmd fetches the given sessions' git statuses with
+// git itself. Each session's status is fetched independently and updates
+// the UI as soon as ready.
+func (m *Model) fetchLocalGitStatusesCmd(sessions []tmux.Session) tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(sessions))
+	for _, s := range sessions {
 		sessionName := s.Name // capture for closure
 		cmds = append(cmds, func() tea.Msg {
 			path, err := git.GetSessionPath(sessionName)
 			if err != nil || path == "" {
 				return gitStatusSingleMsg{sessionName: sessionName, hasStatus: false}
 			}
-			status := git.GetStatus(path)
-			if status.IsRepo && !status.IsClean() {
+			// Clean repos are kept too: the branch columns show them
+			if status := git.GetStatus(path); status.IsRepo {
 				return gitStatusSingleMsg{sessionName: sessionName, status: status, hasStatus: true}
 			}
 			return gitStatusSingleMsg{sessionName: sessionName, hasStatus: false}
 		})
 	}
-
 	return tea.Batch(cmds...)
 }
 
@@ -821,10 +1179,22 @@ func (m *Model) rebuildItems() {
 		}
 	}
 
-	for i, session := range m.sessions {
-		// Apply fuzzy filter if active
-		if !m.matchesFilter(session.Name) {
-			continue
+	// Group headers once any session has a group; pinned sessions come
+	// first, above them. Matches of a filter show even in collapsed groups;
+	// a name filter lists them flat, best match first.
+	listed, ranked := m.listedSessions()
+	grouped := m.grouped() && !ranked
+	group := ""
+	for _, i := range listed {
+		session := m.sessions[i]
+		if grouped && m.pinIndex(session.Name) < 0 {
+			if g := m.groupOf(session); g != group {
+				group = g
+				m.items = append(m.items, Item{Type: ItemTypeGroup, Group: group})
+			}
+			if m.collapsedGroups[group] && m.Filter() == "" {
+				continue
+			}
 		}
 
 		m.items = append(m.items, Item{
@@ -903,19 +1273,30 @@ func (m *Model) borderWidth() int {
 	return m.contentWidth()
 }
 
-// agentPanelVisible reports whether the AGENTS panel is shown: only in the
-// session-list views, with agent statuses enabled, and a viewport large
-// enough — below the thresholds the UI falls back to list-only.
-func (m *Model) agentPanelVisible() bool {
+// sidePanelVisible reports whether a side panel — AGENTS or the pane
+// preview — is shown: only in the session-list views, with something to
+// show, and a viewport large enough — below the thresholds the UI falls
+// back to list-only.
+func (m *Model) sidePanelVisible() bool {
 	switch m.mode {
-	case ModeNormal, ModeCreate, ModeConfirmKill:
+	case ModeNormal, ModeCreate, ModeConfirmKill, ModeSend:
 	default:
 		return false
 	}
-	return (m.config.ClaudeStatusEnabled || m.config.PiStatusEnabled) &&
+	return (len(m.agentKinds) > 0 || m.showPreview) &&
 		m.width >= ui.MinAgentPanelWidth && m.height >= ui.MinAgentPanelHeight
 }
 
+// agentPanelVisible reports whether the side panel shows AGENTS.
+func (m *Model) agentPanelVisible() bool {
+	return m.sidePanelVisible() && !m.showPreview
+}
+
+// previewVisible reports whether the side panel shows the pane preview.
+func (m *Model) previewVisible() bool {
+	return m.sidePanelVisible() && m.showPreview
+}
+
 // windowAgents returns the distinct agent kinds running in the window's
 // panes, in pane order — a window can host claude and pi side by side.
 // Empty if none (or if panes aren't loaded yet).
@@ -942,11 +1323,10 @@ func (m *Model) agentPanelEntries() ([]ui.AgentEntry, string) {
 
 	var entries []ui.AgentEntry
 	cwd := ""
-	for _, s := range m.claudeStatuses[session.Name] {
-		entries = append(entries, ui.AgentEntry{Kind: agent.Claude.Name, Status: s})
-	}
-	for _, s := range m.piStatuses[session.Name] {
-		entries = append(entries, ui.AgentEntry{Kind: agent.Pi.Name, Status: s})
+	for _, kind := range m.agentKinds {
+		for _, s := range m.agentStatuses[kind.Name][session.Name] {
+			entries = append(entries, ui.AgentEntry{Kind: kind.Name, Status: s})
+		}
 	}
 	for _, e := range entries {
 		if e.Status.Cwd != "" {
@@ -954,27 +1334,79 @@ func (m *Model) agentPanelEntries() ([]ui.AgentEntry, string) {
 			break
 		}
 	}
+
+	if len(entries) > 0 {
+		selected := &entries[m.selectedAgentEntry(entries)]
+		if t, ok := m.transcripts[selected.Status.Transcript]; ok {
+			selected.Transcript = &t
+		}
+	}
 	return entries, cwd
 }
 
+// selectedAgentEntry picks the instance whose transcript the panel previews:
+// on a window or pane row the instance running there — matched by kind,
+// preferring the pane's directory — otherwise the first, most active one.
+func (m *Model) selectedAgentEntry(entries []ui.AgentEntry) int {
+	item := m.items[m.cursor]
+	var panes []tmux.Pane
+	switch item.Type {
+	case ItemTypeWindow:
+		if window := m.windowAt(item); window != nil {
+			panes = window.Panes
+		}
+	case ItemTypePane:
+		if pane := m.paneAt(item); pane != nil {
+			panes = []tmux.Pane{*pane}
+		}
+	}
+
+	for _, pane := range panes {
+		kind := m.paneAgents[pane.PID]
+		if kind == "" {
+			continue
+		}
+		match := -1
+		for i, e := range entries {
+			if e.Kind != kind {
+				continue
+			}
+			if e.Status.Cwd == pane.Path {
+				return i
+			}
+			if match < 0 {
+				match = i
+			}
+		}
+		if match >= 0 {
+			return match
+		}
+	}
+	return 0
+}
+
 // sessionListWidth returns the width available for the session list. With
 // the AGENTS panel visible, the content area splits list/panel at
 // AgentPanelRatio (percentage-based, not column-based).
 func (m *Model) sessionListWidth() int {
 	available := m.contentWidth()
-	if !m.agentPanelVisible() {
+	if !m.sidePanelVisible() {
 		return available
 	}
-	panel := available * ui.AgentPanelRatio / 100
+	ratio := ui.AgentPanelRatio
+	if m.showPreview {
+		ratio = ui.PreviewPanelRatio
+	}
+	panel := available * ratio / 100
 	if panel < ui.AgentPanelWidth+3 {
 		panel = ui.AgentPanelWidth + 3
 	}
 	return available - panel
 }
 
-// agentPanelRenderWidth returns the panel's content width: everything the
+// sidePanelRenderWidth returns the panel's content width: everything the
 // list doesn't get, minus the rule separator.
-func (m *Model) agentPanelRenderWidth() int {
+func (m *Model) sidePanelRenderWidth() int {
 	return m.contentWidth() - m.sessionListWidth() - 3
 }
 
@@ -998,11 +1430,10 @@ func (m *Model) statusLine() string {
 // agentCount returns the number of live agent instances across all sessions.
 func (m *Model) agentCount() int {
 	count := 0
-	for _, statuses := range m.claudeStatuses {
-		count += len(statuses)
-	}
-	for _, statuses := range m.piStatuses {
-		count += len(statuses)
+	for _, bySession := range m.agentStatuses {
+		for _, statuses := range bySession {
+			count += len(statuses)
+		}
 	}
 	return count
 }
@@ -1049,6 +1480,9 @@ func (m *Model) isCursorValid() bool {
 // getTargetName returns the tmux target name for the given item
 func (m *Model) getTargetName(item Item) string {
 	session := m.getSession(item)
+	if session == nil {
+		return ""
+	}
 	switch item.Type {
 	case ItemTypeSession:
 		return session.Name
@@ -1084,6 +1518,9 @@ func (m Model) View() string {
 	if m.mode == ModePickDirectory || m.mode == ModeConfirmRemoveFolder {
 		return m.viewPickDirectory()
 	}
+	if m.mode == ModePickWorktree {
+		return m.viewPickWorktree()
+	}
 	if m.mode == ModeCloneChoice {
 		return m.viewCloneChoice()
 	}
@@ -1099,24 +1536,31 @@ func (m Model) View() string {
 	if m.mode == ModeCreatePath {
 		return m.viewCreatePath()
 	}
+	if m.mode == ModeReply {
+		return m.viewReply()
+	}
 	return m.viewSessionList()
 }
 
 // sessionCachePath returns the path to the session cache file
 func (m *Model) sessionCachePath() string {
-	return filepath.Join(m.config.CacheDir, "sessions.json")
+	return session.CacheFile(m.config.CacheDir)
 }
 
 // cachedSession is a simplified session for caching (excludes UI state)
 type cachedSession struct {
 	Name         string    `json:"name"`
 	LastActivity time.Time `json:"last_activity"`
+	Path         string    `json:"path,omitempty"`
+	Group        string    `json:"group,omitempty"`
 }
 
 // sessionCache wraps cached sessions with layout metadata for stable column widths
 type sessionCache struct {
-	Sessions     []cachedSession `json:"sessions"`
-	MaxNameWidth int             `json:"max_name_width"` // Persisted to prevent layout shift
+	Sessions        []cachedSession `json:"sessions"`
+	MaxNameWidth    int             `json:"max_name_width"` // Persisted to prevent layout shift
+	CollapsedGroups []string        `json:"collapsed_groups,omitempty"`
+	Sort            string          `json:"sort,omitempty"` // Sort mode picked with C-s, if not session_sort
 }
 
 // loadSessionCache loads cached sessions from disk
@@ -1131,11 +1575,22 @@ func (m *Model) loadSessionCache() []tmux.Session {
 	var cache sessionCache
 	if err := json.Unmarshal(data, &cache); err == nil && len(cache.Sessions) > 0 {
 		m.maxNameWidth = cache.MaxNameWidth
+		if slices.Contains(m.sortModes(), cache.Sort) {
+			m.sortMode = cache.Sort
+		}
+		for _, group := range cache.CollapsedGroups {
+			if m.collapsedGroups == nil {
+				m.collapsedGroups = make(map[string]bool)
+			}
+			m.collapsedGroups[group] = true
+		}
 		sessions := make([]tmux.Session, len(cache.Sessions))
 		for i, c := range cache.Sessions {
 			sessions[i] = tmux.Session{
 				Name:         c.Name,
 				LastActivity: c.LastActivity,
+				Path:         c.Path,
+				Group:        c.Group,
 			}
 		}
 		return sessions
@@ -1164,6 +1619,8 @@ func (m *Model) saveSessionCache() {
 		cached[i] = cachedSession{
 			Name:         s.Name,
 			LastActivity: s.LastActivity,
+			Path:         s.Path,
+			Group:        s.Group,
 		}
 	}
 
@@ -1171,6 +1628,13 @@ func (m *Model) saveSessionCache() {
 		Sessions:     cached,
 		MaxNameWidth: m.maxNameWidth,
 	}
+	if m.sortMode != m.config.SessionSort {
+		cache.Sort = m.sortMode
+	}
+	for group := range m.collapsedGroups {
+		cache.CollapsedGroups = append(cache.CollapsedGroups, group)
+	}
+	slices.Sort(cache.CollapsedGroups)
 
 	data, err := json.Marshal(cache)
 	if err != nil {
@@ -1,11 +1,19 @@
 package model
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/black-atom-industries/helm/internal/agent"
 	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/daemon"
+	"github.com/black-atom-industries/helm/internal/git"
 	"github.com/black-atom-industries/helm/internal/lib/fuzzy"
 	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/ui"
 )
 
 func TestIsCursorValid(t *testing.T) {
@@ -151,6 +159,95 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestStartRename(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := New("self", cfg, "")
+	m.sessions = []tmux.Session{{
+		Name:     "api",
+		Expanded: true,
+		Windows:  []tmux.Window{{Index: 2, Name: "editor", Expanded: true, Panes: []tmux.Pane{{Index: 0}}}},
+	}}
+	m.items = []Item{
+		{Type: ItemTypeSession, SessionIndex: 0},
+		{Type: ItemTypeWindow, SessionIndex: 0, WindowIndex: 0},
+		{Type: ItemTypePane, SessionIndex: 0, WindowIndex: 0, PaneIndex: 0},
+	}
+
+	tests := []struct {
+		name       string
+		cursor     int
+		wantValue  string
+		wantWindow int
+	}{
+		{name: "session", cursor: 0, wantValue: "api", wantWindow: -1},
+		{name: "window", cursor: 1, wantValue: "editor", wantWindow: 2},
+		{name: "pane renames its window", cursor: 2, wantValue: "editor", wantWindow: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.mode = ModeNormal
+			m.cursor = tt.cursor
+			m.startRename()
+			if m.mode != ModeRename {
+				t.Fatalf("mode = %v, want ModeRename", m.mode)
+			}
+			if got := m.input.Value(); got != tt.wantValue {
+				t.Errorf("input = %q, want %q", got, tt.wantValue)
+			}
+			if m.renameSession != "api" || m.renameWindow != tt.wantWindow {
+				t.Errorf("target = %s:%d, want api:%d", m.renameSession, m.renameWindow, tt.wantWindow)
+			}
+		})
+	}
+}
+
+func TestWorktreeSessionName(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ProjectDirs = []string{"/repos"}
+	m := New("self", cfg, "")
+	m.worktreeRepo = "/repos/org/api"
+
+	tests := []struct {
+		name string
+		wt   git.Worktree
+		want string
+	}{
+		{name: "main worktree", wt: git.Worktree{Path: "/repos/org/api", Branch: "main", Main: true}, want: "org-api"},
+		{name: "linked worktree", wt: git.Worktree{Path: "/repos/org/api@feat-x", Branch: "feat/x"}, want: "org-api@feat-x"},
+		{name: "detached", wt: git.Worktree{Path: "/tmp/wt", Head: "0123456789abcdef", Detached: true}, want: "org-api@0123456"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.worktreeSessionName(tt.wt); got != tt.want {
+				t.Errorf("worktreeSessionName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// git worktree add runs as a command, one at a time; a failure comes back
+// as a message.
+func TestCreateWorktree(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ProjectDirs = []string{"/repos"}
+	m := New("self", cfg, "")
+	m.worktreeRepo = "/repos/org/api"
+	m.mode = ModePickWorktree
+
+	if _, cmd := m.createWorktree("feat x"); cmd == nil || m.worktreeAdding != "feat-x" {
+		t.Fatalf("createWorktree() cmd = %v, adding = %q; want a command for feat-x", cmd, m.worktreeAdding)
+	}
+	if _, cmd := m.createWorktree("other"); cmd != nil {
+		t.Error("second createWorktree() while adding returned a command")
+	}
+
+	m.handleWorktreeAdded(worktreeAddedMsg{err: errors.New("branch exists")})
+	if m.worktreeAdding != "" || !m.messageIsError || m.mode != ModePickWorktree {
+		t.Errorf("after failure: adding = %q, error = %v, mode = %v", m.worktreeAdding, m.messageIsError, m.mode)
+	}
+}
+
 // Layout height calculation tests
 
 func TestContentWidth(t *testing.T) {
@@ -415,3 +512,243 @@ func TestFilterMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestAgentPanelEntriesPreview(t *testing.T) {
+	now := time.Now()
+	m := Model{
+		agentKinds: []agent.Kind{agent.Claude, agent.Pi},
+		sessions: []tmux.Session{{
+			Name: "api",
+			Windows: []tmux.Window{
+				{Index: 1, Panes: []tmux.Pane{{Index: 0, PID: 100, Path: "/api"}}},
+				{Index: 2, Panes: []tmux.Pane{{Index: 0, PID: 200, Path: "/api/web"}, {Index: 1, PID: 201, Path: "/api"}}},
+			},
+		}},
+		items: []Item{
+			{Type: ItemTypeSession, SessionIndex: 0},
+			{Type: ItemTypeWindow, SessionIndex: 0, WindowIndex: 1},
+			{Type: ItemTypePane, SessionIndex: 0, WindowIndex: 1, PaneIndex: 1},
+		},
+		agentStatuses: map[string]map[string][]agent.Status{
+			"claude": {"api": {
+				{State: "working", Timestamp: now, Cwd: "/api", Transcript: "/t/a.jsonl"},
+				{State: "waiting", Timestamp: now, Cwd: "/api/web", Transcript: "/t/b.jsonl"},
+			}},
+			"pi": {"api": {{State: "waiting", Timestamp: now, Transcript: "/t/pi.jsonl"}}},
+		},
+		paneAgents: map[int]string{100: "claude", 200: "claude", 201: "pi"},
+		transcripts: map[string]agent.Transcript{
+			"/t/a.jsonl":  {LastPrompt: "a"},
+			"/t/b.jsonl":  {LastPrompt: "b"},
+			"/t/pi.jsonl": {LastPrompt: "pi"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		cursor int
+		want   string
+	}{
+		{"session row previews most active", 0, "a"},
+		{"window row matches pane cwd", 1, "b"},
+		{"pane row matches kind", 2, "pi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.cursor = tt.cursor
+			entries, _ := m.agentPanelEntries()
+			var previews []string
+			for _, e := range entries {
+				if e.Transcript != nil {
+					previews = append(previews, e.Transcript.LastPrompt)
+				}
+			}
+			if len(previews) != 1 || previews[0] != tt.want {
+				t.Errorf("previewed transcripts = %v, want [%s]", previews, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgentPane(t *testing.T) {
+	panes := map[int][]tmux.Pane{
+		2: {{Index: 0, PID: 300, Path: "/api"}},
+		1: {{Index: 0, PID: 100, Path: "/api"}, {Index: 1, PID: 101, Path: "/api/web"}, {Index: 2, PID: 102, Path: "/api"}},
+	}
+	paneAgents := map[int]string{101: "claude", 102: "claude", 300: "pi"}
+
+	tests := []struct {
+		name       string
+		kind, cwd  string
+		wantWindow int
+		wantPane   int
+		wantOK     bool
+	}{
+		{"cwd match wins", "claude", "/api", 1, 2, true},
+		{"first pane of kind without cwd", "claude", "", 1, 1, true},
+		{"first pane of kind when cwd unmatched", "claude", "/elsewhere", 1, 1, true},
+		{"other kind", "pi", "", 2, 0, true},
+		{"no pane of kind", "codex", "", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, pane, ok := agentPane(panes, paneAgents, tt.kind, tt.cwd)
+			if ok != tt.wantOK || (ok && (window != tt.wantWindow || pane != tt.wantPane)) {
+				t.Errorf("agentPane() = %d.%d, %v; want %d.%d, %v", window, pane, ok, tt.wantWindow, tt.wantPane, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPreviewTargetName(t *testing.T) {
+	m := Model{
+		sessions: []tmux.Session{{
+			Name:    "api",
+			Windows: []tmux.Window{{Index: 3, Panes: []tmux.Pane{{Index: 0}, {Index: 2}}}},
+		}},
+		items: []Item{
+			{Type: ItemTypeSession, SessionIndex: 0},
+			{Type: ItemTypeWindow, SessionIndex: 0, WindowIndex: 0},
+			{Type: ItemTypePane, SessionIndex: 0, WindowIndex: 0, PaneIndex: 1},
+		},
+	}
+	for cursor, want := range []string{"api", "api:3", "api:3.2"} {
+		m.cursor = cursor
+		if got := m.previewTargetName(); got != want {
+			t.Errorf("previewTargetName() at %d = %q, want %q", cursor, got, want)
+		}
+	}
+}
+
+func TestNewInstances(t *testing.T) {
+	one := map[string]map[string][]agent.Status{"claude": {"api": {{State: "working"}}}}
+	two := map[string]map[string][]agent.Status{"claude": {"api": {{State: "working"}, {State: "new"}}}}
+	other := map[string]map[string][]agent.Status{"pi": {"api": {{State: "new"}}}}
+
+	tests := []struct {
+		name      string
+		prev, cur map[string]map[string][]agent.Status
+		want      bool
+	}{
+		{"unchanged", one, one, false},
+		{"first status", nil, one, true},
+		{"second instance", one, two, true},
+		{"instance gone", two, one, false},
+		{"other kind", one, other, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newInstances(tt.prev, tt.cur); got != tt.want {
+				t.Errorf("newInstances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyDaemonState(t *testing.T) {
+	m := Model{
+		agentKinds:       []agent.Kind{{Name: "claude"}},
+		gitStatuses:      map[string]git.Status{"scratch": {IsRepo: true, Branch: "old"}},
+		gitStatusPending: map[string]bool{"api": true, "scratch": true, "new": true},
+	}
+	snap := daemon.Snapshot{
+		Sessions: []daemon.Session{
+			{Name: "api", Git: &daemon.Git{Branch: "main", Dirty: 2}, Agents: []daemon.Agent{
+				{Kind: "claude", State: "waiting", Tool: "Bash"},
+				{Kind: "pi", State: "working"}, // kind not enabled
+			}},
+			{Name: "scratch"}, // not a repo
+		},
+		PaneAgents: map[int]string{42: "claude"},
+	}
+	stale := []tmux.Session{{Name: "api"}, {Name: "scratch"}, {Name: "new"}}
+
+	missing := m.applyDaemonState(snap, stale)
+
+	if len(missing) != 1 || missing[0].Name != "new" {
+		t.Errorf("missing = %v, want [new]", missing)
+	}
+	if got := m.gitStatuses["api"]; !got.IsRepo || got.Branch != "main" || got.Dirty != 2 {
+		t.Errorf("api git status = %+v", got)
+	}
+	if _, ok := m.gitStatuses["scratch"]; ok {
+		t.Error("scratch keeps a git status outside a repo")
+	}
+	if !m.gitStatusPending["new"] || len(m.gitStatusPending) != 1 {
+		t.Errorf("pending = %v, want only new", m.gitStatusPending)
+	}
+	if got := m.agentStatuses["claude"]["api"]; len(got) != 1 || got[0].State != "waiting" || got[0].Tool != "Bash" {
+		t.Errorf("claude statuses = %+v", got)
+	}
+	if _, ok := m.agentStatuses["pi"]; ok {
+		t.Error("statuses of a disabled kind were taken")
+	}
+	if m.paneAgents[42] != "claude" {
+		t.Errorf("paneAgents = %v", m.paneAgents)
+	}
+}
+
+func TestNextStatusPoll(t *testing.T) {
+	tests := []struct {
+		name     string
+		watcher  bool
+		mode     Mode
+		wantSlow bool
+	}{
+		{"no watcher", false, ModeNormal, false},
+		{"watcher", true, ModeNormal, true},
+		{"watcher in reply mode", true, ModeReply, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{mode: tt.mode}
+			if tt.watcher {
+				m.statusWatcher = &fsnotify.Watcher{}
+			}
+			m.nextStatusPoll()
+			if m.statusPollSlow != tt.wantSlow {
+				t.Errorf("statusPollSlow = %v, want %v", m.statusPollSlow, tt.wantSlow)
+			}
+			if m.statusPollGen != 1 {
+				t.Errorf("statusPollGen = %d, want 1", m.statusPollGen)
+			}
+		})
+	}
+}
+
+func TestBranchColumnWidths(t *testing.T) {
+	statuses := map[string]git.Status{
+		"api": {IsRepo: true, Branch: "feat/a-very-long-branch-name-here", Upstream: "origin/feat", Ahead: 2, Behind: 10},
+		"web": {IsRepo: true, Branch: "main"},
+	}
+	layout := ui.RowLayout{NameWidth: 8, GitStatusWidth: 20} // 47 cells besides the branch columns
+
+	tests := []struct {
+		name       string
+		width      int
+		cols       config.SessionColumnsConfig
+		gitStatus  bool
+		wantBranch int
+		wantSync   int
+	}{
+		{"both", 100, config.SessionColumnsConfig{Branch: true, BranchWidth: 20, AheadBehind: true}, true, 20, 5},
+		{"branch only", 100, config.SessionColumnsConfig{Branch: true, BranchWidth: 20}, true, 20, 0},
+		{"branch shrinks", 70, config.SessionColumnsConfig{Branch: true, BranchWidth: 20, AheadBehind: true}, true, 10, 5},
+		{"branch dropped first", 64, config.SessionColumnsConfig{Branch: true, BranchWidth: 20, AheadBehind: true}, true, 0, 5},
+		{"needs git status", 100, config.SessionColumnsConfig{Branch: true, BranchWidth: 20, AheadBehind: true}, false, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{
+				width:       tt.width,
+				sessions:    []tmux.Session{{Name: "api"}, {Name: "web"}, {Name: "scratch"}},
+				gitStatuses: statuses,
+				config:      config.Config{GitStatusEnabled: tt.gitStatus, SessionColumns: tt.cols},
+			}
+			branch, sync := m.branchColumnWidths(layout)
+			if branch != tt.wantBranch || sync != tt.wantSync {
+				t.Errorf("branchColumnWidths() = %d, %d; want %d, %d", branch, sync, tt.wantBranch, tt.wantSync)
+			}
+		})
+	}
+}
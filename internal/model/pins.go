@@ -0,0 +1,79 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/session"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// pinIndex returns a session's slot among the pins, or -1 if unpinned.
+func (m *Model) pinIndex(name string) int {
+	return slices.Index(m.pins, name)
+}
+
+// togglePin pins or unpins the selected session. The cursor follows it to
+// its new position.
+func (m *Model) togglePin() (tea.Model, tea.Cmd) {
+	if !m.isCursorValid() {
+		return m, nil
+	}
+	selected := m.getSession(m.items[m.cursor])
+	if selected == nil {
+		return m, nil
+	}
+	pinned := m.pinIndex(selected.Name) < 0
+	if err := session.SetPinned(m.config.CacheDir, selected.Name, pinned); err != nil {
+		m.setError("Failed to save pins: %v", err)
+		return m, nil
+	}
+	m.pins = session.LoadPins(m.config.CacheDir)
+	m.resort()
+	if pinned {
+		m.setMessage("Pinned \"%s\"", selected.Name)
+	} else {
+		m.setMessage("Unpinned \"%s\"", selected.Name)
+	}
+	return m, clearMessageAfter(3 * time.Second)
+}
+
+// pinnedListed returns how many of m.sessions are pinned. They come first.
+func (m *Model) pinnedListed() int {
+	n := 0
+	for _, s := range m.sessions {
+		if m.pinIndex(s.Name) >= 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// jumpNum returns the jump number of m.sessions[i]. A pinned session's
+// number is its slot, also while sessions pinned before it are missing
+// (killed, or the self session, which isn't in m.sessions): pin N is
+// always key N. The unpinned sessions are numbered after the last slot.
+func (m *Model) jumpNum(i int) int {
+	if slot := m.pinIndex(m.sessions[i].Name); slot >= 0 {
+		return slot
+	}
+	return len(m.pins) + i - m.pinnedListed()
+}
+
+// jumpIndex returns the m.sessions index a jump number selects, or -1 for
+// none (including the slot of a pinned session that is missing).
+func (m *Model) jumpIndex(num int) int {
+	if num < 0 {
+		return -1
+	}
+	if num < len(m.pins) {
+		return slices.IndexFunc(m.sessions, func(s tmux.Session) bool { return s.Name == m.pins[num] })
+	}
+	i := num - len(m.pins) + m.pinnedListed()
+	if i >= len(m.sessions) {
+		return -1
+	}
+	return i
+}
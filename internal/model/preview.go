@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// previewMsg carries a fresh capture of the previewed pane.
+type previewMsg struct {
+	target string
+	lines  []string
+}
+
+// previewTargetName returns the tmux target of the selected item: a pane
+// row's pane, a window row's window, or a session — tmux resolves the
+// latter two to their active pane.
+func (m *Model) previewTargetName() string {
+	if !m.isCursorValid() {
+		return ""
+	}
+	item := m.items[m.cursor]
+	session := m.getSession(item)
+	if session == nil {
+		return ""
+	}
+	switch item.Type {
+	case ItemTypeWindow:
+		if window := m.windowAt(item); window != nil {
+			return fmt.Sprintf("%s:%d", session.Name, window.Index)
+		}
+	case ItemTypePane:
+		if window, pane := m.windowAt(item), m.paneAt(item); window != nil && pane != nil {
+			return fmt.Sprintf("%s:%d.%d", session.Name, window.Index, pane.Index)
+		}
+	}
+	return session.Name
+}
+
+// refreshPreviewCmd captures the selected item's pane off the UI thread.
+// Without force it only does so when the selection moved to another
+// target; the status poll forces a refresh to keep the preview live.
+func (m *Model) refreshPreviewCmd(force bool) tea.Cmd {
+	if !m.previewVisible() {
+		return nil
+	}
+	target := m.previewTargetName()
+	if target == "" || (!force && target == m.previewTarget) {
+		return nil
+	}
+	if target != m.previewTarget {
+		m.previewTarget = target
+		m.preview = nil // don't show the previous target's content
+	}
+	return func() tea.Msg {
+		lines, err := tmux.CapturePaneANSI(target)
+		if err != nil {
+			lines = []string{fmt.Sprintf("Failed to capture pane: %v", err)}
+		}
+		return previewMsg{target: target, lines: lines}
+	}
+}
@@ -0,0 +1,180 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/lib/filter"
+	"github.com/black-atom-industries/helm/internal/lib/fuzzy"
+	"github.com/black-atom-industries/helm/internal/reap"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// sessionStates are the values of is: — each tests a session's git status,
+// agents or pin, or all of them by the reap policies.
+var sessionStates = map[string]func(m *Model, s tmux.Session) bool{
+	"dirty": func(m *Model, s tmux.Session) bool {
+		st, ok := m.gitStatuses[s.Name]
+		return ok && !st.IsClean()
+	},
+	"clean": func(m *Model, s tmux.Session) bool {
+		st, ok := m.gitStatuses[s.Name]
+		return ok && st.IsRepo && st.IsClean()
+	},
+	"ahead": func(m *Model, s tmux.Session) bool {
+		return m.gitStatuses[s.Name].Ahead > 0
+	},
+	"behind": func(m *Model, s tmux.Session) bool {
+		return m.gitStatuses[s.Name].Behind > 0
+	},
+	"conflicted": func(m *Model, s tmux.Session) bool {
+		return m.gitStatuses[s.Name].Conflicted > 0
+	},
+	"waiting": func(m *Model, s tmux.Session) bool {
+		return m.hasAgentState(s.Name, "waiting")
+	},
+	"working": func(m *Model, s tmux.Session) bool {
+		return m.hasAgentState(s.Name, "working")
+	},
+	"pinned": func(m *Model, s tmux.Session) bool {
+		return m.pinIndex(s.Name) >= 0
+	},
+	"reapable": func(m *Model, s tmux.Session) bool {
+		return reap.Check(m.config.Reap, m.reapSession(s), time.Now()) == ""
+	},
+}
+
+// reapSession gathers what the reap policies look at from the model. Agents
+// are alive by their statuses or, for kinds without status tracking, by
+// checkAgentsCmd; until that check is back every session counts as
+// running one, so nothing is reapable by mistake. The git statuses only hold repos, so a session without one is read like
+// helm reap does when it isn't a repo; a repo whose status isn't loaded
+// (or git_status_enabled is off) stays unknown.
+func (m *Model) reapSession(s tmux.Session) reap.Session {
+	r := reap.Session{
+		Name:         s.Name,
+		Path:         s.Path,
+		LastActivity: s.LastActivity,
+		Attached:     s.Attached,
+		Pinned:       m.pinIndex(s.Name) >= 0,
+		AgentAlive:   m.agentAlive == nil || m.agentAlive[s.Name],
+	}
+	for _, bySession := range m.agentStatuses {
+		if len(bySession[s.Name]) > 0 {
+			r.AgentAlive = true
+		}
+	}
+	if st, ok := m.gitStatuses[s.Name]; ok {
+		r.Git = &st
+	} else if s.Path != "" && !git.IsRepo(s.Path) {
+		r.Git = reap.GitStatus(s.Path)
+	}
+	return r
+}
+
+// agentLivenessMsg carries, per session, whether a process of any known
+// agent kind runs in it.
+type agentLivenessMsg map[string]bool
+
+// queriesReapable reports whether the filter uses is:reapable.
+func (m *Model) queriesReapable() bool {
+	reapable := filter.Atom{Key: filter.KeyIs, Value: "reapable"}
+	for _, term := range filter.ParseQuery(m.Filter()).Terms {
+		if slices.Contains(term.Atoms, reapable) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAgentsCmd checks which sessions run an agent of any known kind, for
+// is:reapable: status files only exist for the kinds tracked, and a
+// session with an untracked agent must not be reaped either. A failed
+// check sends nothing.
+func (m Model) checkAgentsCmd() tea.Cmd {
+	names := make([]string, 0, len(m.sessions)+1)
+	for _, s := range m.allSessions() {
+		names = append(names, s.Name)
+	}
+	agents := m.agents
+	return func() tea.Msg {
+		panePIDs, err := tmux.PanePIDs()
+		if err != nil {
+			return nil
+		}
+		live, err := agents.CheckLiveness(panePIDs)
+		if err != nil {
+			return nil
+		}
+		alive := make(agentLivenessMsg, len(names))
+		for _, name := range names {
+			alive[name] = slices.ContainsFunc(agents.Kinds(), func(k agent.Kind) bool { return live.Alive(k, name) })
+		}
+		return alive
+	}
+}
+
+// hasAgentState reports whether an agent instance of any kind in a session
+// is in state.
+func (m *Model) hasAgentState(name, state string) bool {
+	for _, bySession := range m.agentStatuses {
+		for _, s := range bySession[name] {
+			if s.State == state {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchAtom tests one filter atom against a session.
+func (m *Model) matchAtom(s tmux.Session, a filter.Atom) bool {
+	switch a.Key {
+	case "":
+		return fuzzy.Match(s.Name, a.Value)
+	case filter.KeyGroup:
+		return strings.HasPrefix(strings.ToLower(m.groupOf(s)), a.Value)
+	case filter.KeyIs:
+		state, ok := sessionStates[a.Value]
+		return ok && state(m, s)
+	case filter.KeyAgent:
+		for kind, bySession := range m.agentStatuses {
+			if strings.HasPrefix(strings.ToLower(kind), a.Value) && len(bySession[s.Name]) > 0 {
+				return true
+			}
+		}
+		return false
+	case filter.KeyBranch:
+		st, ok := m.gitStatuses[s.Name]
+		return ok && strings.HasPrefix(strings.ToLower(st.Branch), a.Value)
+	case filter.KeyDir:
+		return matchDir(s.Path, a.Value)
+	}
+	return false
+}
+
+// matchDir matches a session path against a dir: value. A path ("/..." or
+// "~/...") matches the directory and everything below it; anything else is
+// matched like the project picker filter.
+func matchDir(path, dir string) bool {
+	if path == "" {
+		return false
+	}
+	if rest, ok := strings.CutPrefix(dir, "~"); ok {
+		home, _ := os.UserHomeDir()
+		dir = home + rest
+	}
+	if !filepath.IsAbs(dir) {
+		return fuzzy.MatchPath(strings.TrimPrefix(path, "/"), dir)
+	}
+	dir = strings.TrimSuffix(strings.ToLower(filepath.Clean(dir)), "/")
+	path = strings.ToLower(filepath.Clean(path))
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/reap"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+func TestQuerySessions(t *testing.T) {
+	m := Model{
+		sessions: []tmux.Session{
+			{Name: "api", Path: "/repos/work/api"},
+			{Name: "web", Path: "/repos/work/web"},
+			{Name: "dots", Path: "/home/dots"},
+			{Name: "scratch"},
+		},
+		sessionFilter: NewFilter[tmux.Session](nil, nil),
+		gitStatuses: map[string]git.Status{
+			"api":  {IsRepo: true, Dirty: 2, Branch: "main"},
+			"web":  {IsRepo: true, Branch: "feature/login"},
+			"dots": {IsRepo: true, Dirty: 1, Branch: "master"},
+		},
+		agentStatuses: map[string]map[string][]agent.Status{
+			"claude": {"api": {{State: "waiting"}}, "web": {{State: "working"}}},
+			"pi":     {"dots": {{State: "waiting"}}},
+		},
+	}
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"is:waiting is:dirty", []string{"api", "dots"}},
+		{"is:clean", []string{"web"}},
+		{"is:waiting agent:claude", []string{"api"}},
+		{"branch:ma", []string{"api", "dots"}},
+		{"branch:feature|is:dirty !dots", []string{"api", "web"}},
+		{"dir:/repos/work", []string{"api", "web"}},
+		{"dir:/repos/wo", nil}, // a path is matched by whole directories
+		{"dir:work a", []string{"api"}},
+		{"!is:dirty !is:clean", []string{"scratch"}},
+		{"is:unknown", nil},
+	}
+	for _, tt := range tests {
+		m.SetFilter(tt.filter)
+		if got := itemLabels(&m); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("items for %q = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestQueryReapable(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	m := Model{
+		config: config.DefaultConfig(),
+		sessions: []tmux.Session{
+			{Name: "idle", LastActivity: old},
+			{Name: "recent", LastActivity: time.Now()},
+			{Name: "attached", LastActivity: old, Attached: 1},
+			{Name: "agent", LastActivity: old},
+			{Name: "untracked", LastActivity: old},
+			{Name: "dirty", LastActivity: old},
+			{Name: "unknown", LastActivity: old},
+			{Name: "pinned", LastActivity: old},
+		},
+		pins:          []string{"pinned"},
+		sessionFilter: NewFilter[tmux.Session](nil, nil),
+		gitStatuses: map[string]git.Status{
+			"idle":      {IsRepo: true},
+			"recent":    {IsRepo: true},
+			"attached":  {IsRepo: true},
+			"agent":     {IsRepo: true},
+			"untracked": {IsRepo: true},
+			"dirty":     {IsRepo: true, Dirty: 1},
+			"pinned":    {},
+		},
+		agentStatuses: map[string]map[string][]agent.Status{
+			"claude": {"agent": {{State: "idle"}}},
+		},
+	}
+
+	// Nothing is reapable until the liveness check is back
+	m.SetFilter("is:reapable")
+	if got := itemLabels(&m); len(got) != 0 {
+		t.Errorf("items before liveness check = %v, want none", got)
+	}
+
+	// An agent without status tracking keeps its session
+	updated, _ := m.Update(agentLivenessMsg{"untracked": true})
+	m = updated.(Model)
+	if got, want := itemLabels(&m), []string{"idle"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+}
+
+// A session outside any repo has no git status in the model; it must be
+// judged like helm reap judges it.
+func TestQueryReapable_notRepo(t *testing.T) {
+	s := tmux.Session{Name: "notes", Path: t.TempDir(), LastActivity: time.Now().Add(-48 * time.Hour)}
+	cfg := config.DefaultConfig()
+	m := Model{config: cfg, sessions: []tmux.Session{s}, sessionFilter: NewFilter[tmux.Session](nil, nil), agentAlive: map[string]bool{}}
+
+	cli := reap.Session{Name: s.Name, Path: s.Path, LastActivity: s.LastActivity, Git: reap.GitStatus(s.Path)}
+	now := time.Now()
+	if tui, cli := reap.Check(cfg.Reap, m.reapSession(s), now), reap.Check(cfg.Reap, cli, now); tui != cli {
+		t.Errorf("TUI says %q, helm reap says %q", tui, cli)
+	}
+	m.SetFilter("is:reapable")
+	if got, want := itemLabels(&m), []string{"notes"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/lib/frecency"
+	"github.com/black-atom-industries/helm/internal/session"
+	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+// startRename enters ModeRename for the selected session or window,
+// pre-filled with its current name. Panes have no name; they rename
+// their window.
+func (m *Model) startRename() (tea.Model, tea.Cmd) {
+	if !m.isCursorValid() {
+		return m, nil
+	}
+
+	item := m.items[m.cursor]
+	session := m.getSession(item)
+	if session == nil {
+		return m, nil
+	}
+
+	name := session.Name
+	m.renameSession = session.Name
+	m.renameWindow = -1
+	if item.Type != ItemTypeSession {
+		window := m.windowAt(item)
+		if window == nil {
+			return m, nil
+		}
+		name = window.Name
+		m.renameWindow = window.Index
+	}
+
+	m.mode = ModeRename
+	m.SetFilter("")
+	m.input.Reset()
+	m.input.CharLimit = 50
+	m.input.SetValue(name)
+	m.input.SetCursor(len(name))
+	m.input.Focus()
+	return m, nil
+}
+
+// renamePrompt returns the notification-line prompt for ModeRename.
+func (m *Model) renamePrompt() string {
+	if m.renameWindow >= 0 {
+		return fmt.Sprintf("Rename window %s:%d: ", m.renameSession, m.renameWindow)
+	}
+	return fmt.Sprintf("Rename \"%s\": ", m.renameSession)
+}
+
+func (m *Model) handleRenameMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := ui.DefaultKeyMap
+
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		m.mode = ModeNormal
+		m.input.Blur()
+		return m, nil
+
+	case msg.Type == tea.KeyEnter:
+		name := strings.TrimSpace(m.input.Value())
+		if name == "" {
+			m.setError("Name cannot be empty")
+			return m, nil
+		}
+		return m.renameCurrent(name)
+
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	}
+
+	// Only pass regular typing and text editing to the input
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace, tea.KeyBackspace, tea.KeyDelete,
+		tea.KeyLeft, tea.KeyRight, tea.KeyHome, tea.KeyEnd,
+		tea.KeyCtrlA, tea.KeyCtrlE, tea.KeyCtrlW, tea.KeyCtrlU:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// renameCurrent applies ModeRename's input to the session or window.
+func (m *Model) renameCurrent(name string) (tea.Model, tea.Cmd) {
+	m.mode = ModeNormal
+	m.input.Blur()
+
+	if m.renameWindow >= 0 {
+		target := fmt.Sprintf("%s:%d", m.renameSession, m.renameWindow)
+		if err := tmux.RenameWindow(target, name); err != nil {
+			m.setError("Rename failed: %v", err)
+			return m, nil
+		}
+		if sess := m.sessionByName(m.renameSession); sess != nil {
+			for i := range sess.Windows {
+				if sess.Windows[i].Index == m.renameWindow {
+					sess.Windows[i].Name = name
+				}
+			}
+		}
+		m.setMessage("Renamed window to \"%s\"", name)
+		return m, clearMessageAfter(5 * time.Second)
+	}
+
+	// Session names go through the same sanitizing as new sessions
+	name = config.SanitizeSessionName(name)
+	oldName := m.renameSession
+	if name == oldName {
+		return m, nil
+	}
+	if err := session.Rename(&m.config, oldName, name); err != nil {
+		m.setError("%v", err)
+		if tmux.SessionExists(oldName) {
+			return m, nil
+		}
+	} else {
+		m.setMessage("Renamed \"%s\" to \"%s\"", oldName, name)
+	}
+
+	// Rename in place so expansion state survives the reload
+	if sess := m.sessionByName(oldName); sess != nil {
+		sess.Name = name
+	}
+	if oldName == m.currentSession {
+		m.currentSession = name
+	}
+	m.pins = session.LoadPins(m.config.CacheDir)
+	m.frecency = frecency.Load(m.config.CacheDir) // Carries the new name's key
+	m.rebuildItems()
+	return m, tea.Batch(m.loadSessions, clearMessageAfter(5*time.Second))
+}
+
+// sessionByName finds a session by name, including the self session.
+func (m *Model) sessionByName(name string) *tmux.Session {
+	if m.selfSession != nil && m.selfSession.Name == name {
+		return m.selfSession
+	}
+	return m.findSessionByName(name)
+}
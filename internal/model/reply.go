@@ -0,0 +1,224 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+// replyTailLines is how much pane history is captured for context; the
+// view shows as much of it as fits.
+const replyTailLines = 100
+
+// replyTailMsg carries a fresh capture of the reply target's pane.
+type replyTailMsg struct {
+	target string
+	lines  []string
+	err    error
+}
+
+// startReply enters ModeReply for the agent instance the AGENTS panel
+// previews: on a session row the most active one, on a window or pane row
+// the one running there.
+func (m *Model) startReply() (tea.Model, tea.Cmd) {
+	if !m.isCursorValid() {
+		return m, nil
+	}
+	item := m.items[m.cursor]
+	session := m.getSession(item)
+	entries, _ := m.agentPanelEntries()
+	if session == nil || len(entries) == 0 {
+		m.setMessage("No agent in this session")
+		return m, clearMessageAfter(3 * time.Second)
+	}
+	entry := entries[m.selectedAgentEntry(entries)]
+
+	panes, err := tmux.ListSessionPanes(session.Name)
+	if err != nil {
+		m.setError("Failed to list panes: %v", err)
+		return m, nil
+	}
+	// On window and pane rows only that window/pane is eligible
+	if item.Type != ItemTypeSession {
+		window := m.windowAt(item)
+		if window == nil {
+			return m, nil
+		}
+		panes = map[int][]tmux.Pane{window.Index: panes[window.Index]}
+		if pane := m.paneAt(item); pane != nil && item.Type == ItemTypePane {
+			panes[window.Index] = slices.DeleteFunc(panes[window.Index], func(p tmux.Pane) bool { return p.Index != pane.Index })
+		}
+	}
+
+	window, pane, ok := agentPane(panes, m.paneAgents, entry.Kind, entry.Status.Cwd)
+	if !ok {
+		m.setError("No %s pane found in %s", entry.Kind, session.Name)
+		return m, nil
+	}
+
+	m.replyKind = entry.Kind
+	m.replyState = entry.Status.State
+	m.replyTarget = fmt.Sprintf("%s:%d.%d", session.Name, window, pane)
+	m.replyTail = nil
+	m.replyCanned = -1
+	m.mode = ModeReply
+	m.SetFilter("")
+	m.input.Reset()
+	m.input.CharLimit = 0
+	m.input.Focus()
+	return m, m.captureReplyTailCmd()
+}
+
+// agentPane finds the pane running an agent of the given kind, in window
+// and pane order, preferring the one whose directory is the agent's cwd.
+func agentPane(panes map[int][]tmux.Pane, paneAgents map[int]string, kind, cwd string) (window, pane int, ok bool) {
+	windows := make([]int, 0, len(panes))
+	for w := range panes {
+		windows = append(windows, w)
+	}
+	slices.Sort(windows)
+
+	for _, w := range windows {
+		for _, p := range panes[w] {
+			if paneAgents[p.PID] != kind {
+				continue
+			}
+			if cwd != "" && p.Path == cwd {
+				return w, p.Index, true
+			}
+			if !ok {
+				window, pane, ok = w, p.Index, true
+			}
+		}
+	}
+	return window, pane, ok
+}
+
+// captureReplyTailCmd captures the end of the reply target's pane off the
+// UI thread.
+func (m *Model) captureReplyTailCmd() tea.Cmd {
+	target := m.replyTarget
+	return func() tea.Msg {
+		lines, err := tmux.CapturePane(target, replyTailLines)
+		return replyTailMsg{target: target, lines: lines, err: err}
+	}
+}
+
+func (m *Model) handleReplyMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := ui.DefaultKeyMap
+
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		m.mode = ModeNormal
+		m.input.Blur()
+		return m, nil
+
+	case msg.Type == tea.KeyEnter:
+		return m.sendReply(strings.TrimSpace(m.input.Value()))
+
+	case msg.Type == tea.KeyTab:
+		m.cycleCannedReply(1)
+		return m, nil
+
+	case msg.Type == tea.KeyShiftTab:
+		m.cycleCannedReply(-1)
+		return m, nil
+
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	}
+
+	// Only pass regular typing and text editing to the input
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace, tea.KeyBackspace, tea.KeyDelete,
+		tea.KeyLeft, tea.KeyRight, tea.KeyHome, tea.KeyEnd,
+		tea.KeyCtrlA, tea.KeyCtrlE, tea.KeyCtrlW, tea.KeyCtrlU:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// cycleCannedReply fills the input with the next (or previous) canned
+// reply from the config.
+func (m *Model) cycleCannedReply(step int) {
+	replies := m.config.Replies
+	if len(replies) == 0 {
+		return
+	}
+	if m.replyCanned < 0 && step < 0 {
+		m.replyCanned = 0
+	}
+	m.replyCanned = (m.replyCanned + step + len(replies)) % len(replies)
+	m.input.SetValue(replies[m.replyCanned])
+	m.input.CursorEnd()
+}
+
+// sendReply types text into the agent's pane and submits it. An empty
+// reply sends a bare Enter, which accepts an agent's default choice.
+func (m *Model) sendReply(text string) (tea.Model, tea.Cmd) {
+	m.mode = ModeNormal
+	m.input.Blur()
+
+	if err := tmux.SendKeys(m.replyTarget, text, true); err != nil {
+		m.setError("Failed to send to %s: %v", m.replyTarget, err)
+		return m, nil
+	}
+	m.setMessage("Sent to %s in %s", m.replyKind, m.replyTarget)
+	return m, tea.Batch(m.pollAgentStatusesCmd(false), clearMessageAfter(3*time.Second))
+}
+
+// viewReply renders the pane tail of the agent being replied to, with the
+// reply input in the notification line.
+func (m Model) viewReply() string {
+	var header strings.Builder
+	header.WriteString(ui.RenderTitleBar(config.AppName, m.mode.String(), m.width))
+	header.WriteString("\n")
+	header.WriteString(ui.RenderPrompt("", m.width))
+	header.WriteString("\n")
+	header.WriteString(ui.RenderBorder(m.borderWidth()))
+	header.WriteString("\n")
+
+	var b strings.Builder
+	b.WriteString(ui.HelpSectionStyle.Render(strings.ToUpper(m.replyKind)))
+	b.WriteString(ui.HelpDescStyle.Render(fmt.Sprintf("  %s · %s", m.replyTarget, m.replyState)))
+	b.WriteString("\n")
+	b.WriteString(ui.RenderDottedBorder(m.contentWidth()))
+	b.WriteString("\n")
+
+	// Title, prompt and border above; agent line, dotted border and canned
+	// replies line around the tail; 4 footer lines below
+	tail := m.replyTail
+	if available := max(m.contentHeight()-3-3-4, 0); len(tail) > available {
+		tail = tail[len(tail)-available:]
+	}
+	for _, line := range tail {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	if replies := m.config.Replies; len(replies) > 0 {
+		parts := make([]string, len(replies))
+		for i, r := range replies {
+			if i == m.replyCanned {
+				parts[i] = ui.HelpKeyStyle.Render(r)
+			} else {
+				parts[i] = ui.HelpDescStyle.Render(r)
+			}
+		}
+		b.WriteString(ui.HelpDescStyle.Render("Tab ") + strings.Join(parts, ui.HelpDescStyle.Render(" · ")))
+		b.WriteString("\n")
+	}
+
+	notification := fmt.Sprintf("Reply to %s: %s", m.replyKind, m.input.View())
+	return m.renderWithSidebar(header.String(), b.String(), ui.ReplyActions, notification, false)
+}
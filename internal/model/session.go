@@ -12,9 +12,11 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/black-atom-industries/helm/internal/config"
 	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/lib/filter"
 	"github.com/black-atom-industries/helm/internal/tmux"
 	"github.com/black-atom-industries/helm/internal/ui"
 )
@@ -27,11 +29,15 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case key.Matches(msg, keys.Cancel):
-		// Escape: clear filter if active, otherwise quit
+		// Escape: clear filter if active, then marks, otherwise quit
 		if m.Filter() != "" {
 			m.SetFilter("")
 			return m, nil
 		}
+		if len(m.marks) > 0 {
+			m.marks = nil
+			return m, nil
+		}
 		return m, tea.Quit
 
 	case key.Matches(msg, keys.Up):
@@ -55,8 +61,10 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, keys.Select):
 		// If filter is active but no results, transition to path input mode
 		if m.Filter() != "" && len(m.items) == 0 {
-			name := strings.TrimSpace(m.Filter())
-			if name == "" {
+			// Only plain text names a session; a query of terms just found nothing
+			q := filter.ParseQuery(m.Filter())
+			name := q.Text
+			if name == "" || len(q.Terms) > 0 {
 				return m, nil
 			}
 			m.pendingSessionName = config.SanitizeSessionName(name)
@@ -78,9 +86,28 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m.selectCurrent()
 
+	// With items marked, these act on the marked set
+	case len(m.marks) > 0 && key.Matches(msg, keys.Kill):
+		return m.confirmKillMarked()
+	case len(m.marks) > 0 && key.Matches(msg, keys.Reply):
+		return m.startSend()
+	case len(m.marks) > 0 && key.Matches(msg, keys.Lazygit):
+		return m.lazygitMarked()
+	case len(m.marks) > 0 && key.Matches(msg, keys.AddBookmark):
+		return m.bookmarkMarked()
+
 	case key.Matches(msg, keys.Kill):
 		return m.confirmKill()
 
+	case key.Matches(msg, keys.Mark):
+		m.toggleMark()
+
+	case key.Matches(msg, keys.MarkAll):
+		m.markAll()
+
+	case key.Matches(msg, keys.Detach):
+		return m.detachTargets()
+
 	case key.Matches(msg, keys.Create):
 		m.mode = ModeCreate
 		m.SetFilter("") // Clear any active filter
@@ -91,14 +118,27 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.input.Focus()
 		return m, textinput.Blink
 
+	case key.Matches(msg, keys.Rename):
+		return m.startRename()
+
+	case key.Matches(msg, keys.NextWaiting):
+		return m.jumpToWaiting()
+
+	case key.Matches(msg, keys.Reply):
+		return m.startReply()
+
+	case key.Matches(msg, keys.Preview):
+		m.showPreview = !m.showPreview
+		m.previewTarget = "" // capture anew when shown again
+
 	case key.Matches(msg, keys.PickDirectory):
 		m.mode = ModePickDirectory
 		m.returnToBookmarks = false // Coming from normal mode, not bookmarks
 		m.projectList.Reset()
 		m.projectsLoading = true
-		// Carry over the active filter
+		// Carry over the active filter's text; its terms only apply to sessions
 		if m.Filter() != "" {
-			m.projectList.SetFilter(m.Filter())
+			m.projectList.SetFilter(filter.ParseQuery(m.Filter()).Text)
 			m.SetFilter("")
 		}
 		// Request window size to get proper height for layout
@@ -124,9 +164,9 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = ModeBookmarks
 		m.bookmarkList.Reset()
 		m.bookmarkList.SetItems(m.config.Bookmarks)
-		// Carry over the active filter
+		// Carry over the active filter's text; its terms only apply to sessions
 		if m.Filter() != "" {
-			m.bookmarkList.SetFilter(m.Filter())
+			m.bookmarkList.SetFilter(filter.ParseQuery(m.Filter()).Text)
 			m.SetFilter("")
 		}
 		return m, tea.WindowSize()
@@ -134,6 +174,12 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, keys.AddBookmark):
 		return m.addSelectedToBookmarks()
 
+	case key.Matches(msg, keys.Pin):
+		return m.togglePin()
+
+	case key.Matches(msg, keys.Sort):
+		return m.cycleSort()
+
 	// Number jumps (only when no filter active)
 	case m.Filter() == "" && key.Matches(msg, keys.Jump0):
 		return m.handleJump(0)
@@ -157,7 +203,11 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleJump(9)
 
 	default:
+		reapable := m.queriesReapable()
 		m.HandleFilterKey(msg)
+		if !reapable && m.queriesReapable() {
+			return m, m.checkAgentsCmd()
+		}
 	}
 
 	return m, nil
@@ -169,6 +219,9 @@ func (m *Model) handleConfirmKillMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, keys.Kill):
 		// Double C-x confirms the kill
+		if len(m.marks) > 0 {
+			return m.killMarked()
+		}
 		return m.killCurrent()
 	case key.Matches(msg, keys.Cancel):
 		m.mode = ModeNormal
@@ -401,6 +454,7 @@ func (m *Model) createSessionFromDir(fullPath string) (tea.Model, tea.Cmd) {
 			m.setError("Failed to switch: %v", err)
 			return m, m.loadSessions
 		}
+		m.recordSelection(name, fullPath)
 		return m, tea.Quit
 	}
 
@@ -412,6 +466,7 @@ func (m *Model) createSessionFromDir(fullPath string) (tea.Model, tea.Cmd) {
 
 	// Apply layout if configured
 	m.applyLayout(name, fullPath)
+	m.recordSelection(name, fullPath)
 
 	// Switch to the new session
 	if err := tmux.SwitchClient(name); err != nil {
@@ -473,7 +528,7 @@ func (m *Model) handleJump(num int) (tea.Model, tea.Cmd) {
 		item := m.items[m.cursor]
 		session := m.getSession(item)
 
-		if session.Expanded {
+		if session != nil && session.Expanded {
 			// Jump to window number within this session
 			for _, w := range session.Windows {
 				if w.Index == num {
@@ -482,6 +537,7 @@ func (m *Model) handleJump(num int) (tea.Model, tea.Cmd) {
 						m.setError("Error: %v", err)
 						return m, nil
 					}
+					m.recordSelection(session.Name, session.Path)
 					return m, tea.Quit
 				}
 			}
@@ -489,12 +545,13 @@ func (m *Model) handleJump(num int) (tea.Model, tea.Cmd) {
 	}
 
 	// Session labels: 0, 1, 2... map to non-self session indices
-	if num >= 0 && num < len(m.sessions) {
-		session := m.sessions[num]
+	if i := m.jumpIndex(num); i >= 0 {
+		session := m.sessions[i]
 		if err := tmux.SwitchClient(session.Name); err != nil {
 			m.setError("Error: %v", err)
 			return m, nil
 		}
+		m.recordSelection(session.Name, session.Path)
 		return m, tea.Quit
 	}
 
@@ -509,6 +566,11 @@ func (m *Model) expandCurrent() {
 	item := m.items[m.cursor]
 
 	switch item.Type {
+	case ItemTypeGroup:
+		if m.collapsedGroups[item.Group] {
+			m.toggleGroup(item.Group)
+		}
+
 	case ItemTypeSession:
 		// Collapse all other sessions first
 		for i := range m.sessions {
@@ -572,9 +634,22 @@ func (m *Model) collapseCurrent() {
 	item := m.items[m.cursor]
 
 	switch item.Type {
+	case ItemTypeGroup:
+		if !m.collapsedGroups[item.Group] {
+			m.toggleGroup(item.Group)
+		}
+
 	case ItemTypeSession:
-		// Collapse the session
-		m.getSession(item).Expanded = false
+		// Collapse the session, or its group when it is collapsed already
+		session := m.getSession(item)
+		if !session.Expanded && !item.IsSelf && m.pinIndex(session.Name) < 0 && m.grouped() && m.Filter() == "" {
+			group := m.groupOf(*session)
+			m.toggleGroup(group)
+			m.cursor = max(m.groupHeaderIndex(group), 0)
+			m.updateScrollOffset()
+			return
+		}
+		session.Expanded = false
 		m.rebuildItems()
 
 	case ItemTypeWindow:
@@ -609,11 +684,20 @@ func (m *Model) selectCurrent() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	target := m.getTargetName(m.items[m.cursor])
+	item := m.items[m.cursor]
+	if item.Type == ItemTypeGroup {
+		m.toggleGroup(item.Group)
+		return m, nil
+	}
+
+	target := m.getTargetName(item)
 	if err := tmux.SwitchClient(target); err != nil {
 		m.setError("Error: %v", err)
 		return m, nil
 	}
+	if session := m.getSession(item); session != nil {
+		m.recordSelection(session.Name, session.Path)
+	}
 
 	return m, tea.Quit
 }
@@ -624,6 +708,9 @@ func (m *Model) openLazygit() (tea.Model, tea.Cmd) {
 	}
 
 	item := m.items[m.cursor]
+	if item.Type == ItemTypeGroup {
+		return m, nil
+	}
 	if item.Type != ItemTypeSession {
 		// For windows/panes, use the parent session
 		item = Item{Type: ItemTypeSession, SessionIndex: item.SessionIndex}
@@ -653,6 +740,9 @@ func (m *Model) openRemote() (tea.Model, tea.Cmd) {
 	}
 
 	item := m.items[m.cursor]
+	if item.Type == ItemTypeGroup {
+		return m, nil
+	}
 	if item.Type != ItemTypeSession {
 		item = Item{Type: ItemTypeSession, SessionIndex: item.SessionIndex}
 	}
@@ -693,6 +783,9 @@ func (m *Model) confirmKill() (tea.Model, tea.Cmd) {
 	}
 
 	item := m.items[m.cursor]
+	if item.Type == ItemTypeGroup {
+		return m, nil
+	}
 	m.killTarget = m.getTargetName(item)
 
 	switch item.Type {
@@ -736,7 +829,14 @@ func (m *Model) killCurrent() (tea.Model, tea.Cmd) {
 
 	switch item.Type {
 	case ItemTypeSession:
+		// Look up the worktree before the session (and its path) is gone
+		worktree, mainRepo := worktreeOfSession(session.Name)
 		err = tmux.KillSession(session.Name)
+		if err == nil && worktree != "" {
+			m.killTarget = ""
+			m.confirmPruneWorktree(session.Name, worktree, mainRepo)
+			return m, m.loadSessions
+		}
 		if err == nil {
 			m.message = fmt.Sprintf("Killed \"%s\"", session.Name)
 		}
@@ -768,6 +868,54 @@ func (m *Model) killCurrent() (tea.Model, tea.Cmd) {
 	return m, tea.Batch(m.loadSessions, clearMessageAfter(5*time.Second))
 }
 
+// minBranchWidth is the narrowest the branch column shrinks to before it
+// is hidden: room for the header and a few characters of the name.
+const minBranchWidth = 6
+
+// branchColumnWidths returns the widths of the optional branch and
+// ahead/behind columns (0 = hidden). The branch column fits the longest
+// branch up to session_columns.branch_width and shrinks to what's left of
+// the row; when even minBranchWidth doesn't fit it is dropped, then the
+// ahead/behind column.
+func (m *Model) branchColumnWidths(layout ui.RowLayout) (branch, sync int) {
+	cols := m.config.SessionColumns
+	if !m.config.GitStatusEnabled || (!cols.Branch && !cols.AheadBehind) {
+		return 0, 0
+	}
+
+	repos := 0
+	for _, s := range m.allSessions() {
+		status, ok := m.gitStatuses[s.Name]
+		if !ok {
+			continue
+		}
+		repos++
+		branch = max(branch, ansi.StringWidth(status.Branch))
+		if status.Upstream != "" {
+			sync = max(sync, ui.GitSyncWidth(status.Ahead, status.Behind))
+		}
+	}
+	if repos == 0 {
+		return 0, 0
+	}
+
+	free := m.rowWidth() - layout.FixedWidth()
+
+	sync = max(sync, 2) // fits the ↑↓ header
+	if !cols.AheadBehind || free < 1+sync {
+		sync = 0
+	} else {
+		free -= 1 + sync
+	}
+
+	minWidth := min(minBranchWidth, cols.BranchWidth)
+	branch = min(max(branch, minWidth), cols.BranchWidth, free-1)
+	if !cols.Branch || branch < minWidth {
+		branch = 0
+	}
+	return branch, sync
+}
+
 // viewSessionList renders the main session list view
 func (m Model) viewSessionList() string {
 	var b strings.Builder
@@ -787,7 +935,9 @@ func (m Model) viewSessionList() string {
 	layout := ui.RowLayout{
 		NameWidth:      m.maxNameWidth,
 		GitStatusWidth: m.maxGitStatusWidth,
+		AgentKinds:     m.agents.Kinds(),
 	}
+	layout.BranchWidth, layout.GitSyncWidth = m.branchColumnWidths(layout)
 
 	// --- Build session list content ---
 	var listBuilder strings.Builder
@@ -799,8 +949,6 @@ func (m Model) viewSessionList() string {
 			ShowExpandIcon: true,
 			ShowTime:       true,
 			ShowGit:        m.maxGitStatusWidth > 0,
-			ShowCC:         true, // Always show CC in header when data rows have it reserved
-			ShowPi:         true, // Always show Pi in header when data rows have it reserved
 			NameLabel:      "SESS",
 		})
 		listBuilder.WriteString(header)
@@ -855,11 +1003,17 @@ func (m Model) viewSessionList() string {
 				break
 			}
 
+			// Jump keys index m.sessions, which collapsed groups skip rows of
+			num := sessionNum
+			if !item.IsSelf && m.Filter() == "" {
+				num = m.jumpNum(item.SessionIndex)
+			}
+
 			// Build options for this row
 			lastActivity := session.LastActivity
 			opts := ui.SessionRowOpts{
 				RowOpts: ui.RowOpts{
-					Num:            sessionNum,
+					Num:            num,
 					Name:           session.Name,
 					Selected:       selected,
 					ShowExpandIcon: true,
@@ -867,30 +1021,34 @@ func (m Model) viewSessionList() string {
 					LastActivity:   &lastActivity,
 					AnimFrame:      m.animationFrame,
 					IsSelf:         item.IsSelf,
+					Pinned:         m.pinIndex(session.Name) >= 0,
+					Marked:         m.isMarked(item),
 				},
 			}
+			if !item.IsSelf && m.Filter() != "" {
+				if r, ok := m.matchSession(*session); ok {
+					opts.Matches = r.Positions
+				}
+			}
 			if status, ok := m.gitStatuses[session.Name]; ok {
 				opts.GitStatus = &status
 			}
 			if m.gitStatusShowLoading && m.gitStatusPending[session.Name] {
 				opts.GitStatusLoading = true
 			}
-			// Statuses are sorted most-active first — [0] drives the glyph
-			if statuses := m.claudeStatuses[session.Name]; len(statuses) > 0 {
-				opts.ClaudeStatus = &statuses[0]
-			}
-			if statuses := m.piStatuses[session.Name]; len(statuses) > 0 {
-				opts.PiStatus = &statuses[0]
-			}
+			opts.AgentStatuses = m.topAgentStatuses(session.Name)
 
 			listBuilder.WriteString(ui.RenderSessionRow(session.Name, session.LastActivity, layout, opts, m.rowWidth()))
 			if !item.IsSelf {
 				sessionNum++
 			}
 
+		case ItemTypeGroup:
+			listBuilder.WriteString(ui.RenderGroupRow(item.Group, m.groupSize(item.Group), m.collapsedGroups[item.Group] && m.Filter() == "", selected, m.rowWidth()))
+
 		case ItemTypeWindow:
 			if window := m.windowAt(item); window != nil {
-				opts := ui.WindowRowOpts{Selected: selected, Expanded: window.Expanded, AgentKinds: m.windowAgents(window)}
+				opts := ui.WindowRowOpts{Selected: selected, Marked: m.isMarked(item), Expanded: window.Expanded, AgentKinds: m.windowAgents(window)}
 				listBuilder.WriteString(ui.RenderWindowRow(window.Index, window.Name, opts, m.rowWidth()))
 			}
 
@@ -923,13 +1081,20 @@ func (m Model) viewSessionList() string {
 	header := b.String()
 	listContent := listBuilder.String()
 
-	// Join the AGENTS panel to the right of the list. Rows are hard-truncated
+	// Join the side panel to the right of the list. Rows are hard-truncated
 	// to the list width first — lipgloss Width would wrap overlong rows,
 	// breaking line counting and alignment.
-	if m.agentPanelVisible() {
-		entries, cwd := m.agentPanelEntries()
-		panelHeight := strings.Count(listContent, "\n")
-		panel := ui.RenderAgentPanel(entries, cwd, m.agentPanelRenderWidth(), panelHeight)
+	if m.sidePanelVisible() {
+		var panel string
+		if m.showPreview {
+			// The preview fills the content area, however short the list
+			height := max(strings.Count(listContent, "\n"), m.contentHeight()-7)
+			panel = ui.RenderPreviewPanel(m.previewTarget, m.preview, m.sidePanelRenderWidth(), height)
+		} else {
+			entries, cwd := m.agentPanelEntries()
+			panelHeight := strings.Count(listContent, "\n")
+			panel = ui.RenderAgentPanel(entries, cwd, m.sidePanelRenderWidth(), panelHeight)
+		}
 		truncated := ui.TruncateLines(strings.TrimRight(listContent, "\n"), listWidth)
 		listBlock := lipgloss.NewStyle().Width(listWidth).Render(truncated)
 		listContent = lipgloss.JoinHorizontal(lipgloss.Top, listBlock, panel) + "\n"
@@ -945,9 +1110,24 @@ func (m Model) viewSessionList() string {
 	case ModeConfirmKill:
 		actions = ui.ConfirmKillActions
 		notification = m.message
+	case ModeConfirmPruneWorktree:
+		actions = ui.ConfirmPruneActions
+		notification = m.message
+	case ModeRename:
+		actions = ui.RenameActions
+		notification = m.renamePrompt() + m.input.View()
+	case ModeSend:
+		actions = ui.SendActions
+		notification = fmt.Sprintf("Send to %s: ", m.marksSummary()) + m.input.View()
 	default:
 		actions = ui.SessionActions
 		notification = m.message
+		if len(m.marks) > 0 {
+			actions = ui.MarkActions
+			if notification == "" {
+				notification = m.marksSummary() + " marked"
+			}
+		}
 		if notification == "" {
 			notification = m.statusLine()
 		}
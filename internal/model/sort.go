@@ -0,0 +1,148 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// urgencyRank orders agent states for the urgency sort: an agent waiting
+// for input needs you first.
+var urgencyRank = map[string]int{"waiting": 0, "working": 1, "new": 2}
+
+// urgency returns the rank of a session's most urgent agent instance;
+// sessions without agents rank last.
+func (m *Model) urgency(name string) int {
+	rank := len(urgencyRank)
+	for _, bySession := range m.agentStatuses {
+		for _, s := range bySession[name] {
+			if r, ok := urgencyRank[s.State]; ok {
+				rank = min(rank, r)
+			}
+		}
+	}
+	return rank
+}
+
+// compareSessions orders two sessions by the sort mode. Ties — and the
+// activity mode — go to the most recently active.
+func (m *Model) compareSessions(a, b tmux.Session) int {
+	var c int
+	switch m.sortMode {
+	case config.SortName:
+		c = cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case config.SortPath:
+		// Sessions without a known path go last
+		switch {
+		case a.Path == "" && b.Path != "":
+			c = 1
+		case a.Path != "" && b.Path == "":
+			c = -1
+		default:
+			c = cmp.Compare(a.Path, b.Path)
+		}
+	case config.SortUrgency:
+		c = cmp.Compare(m.urgency(a.Name), m.urgency(b.Name))
+	}
+	if c != 0 {
+		return c
+	}
+	return b.LastActivity.Compare(a.LastActivity)
+}
+
+// orderSessions puts the sessions in list order: pinned ones first in slot
+// order, then group by group, each sorted by the sort mode. Groups come in
+// the order of their first session. Jump numbers follow the order the list
+// shows (see jumpNum).
+func (m *Model) orderSessions() {
+	slices.SortStableFunc(m.sessions, m.compareSessions)
+
+	groupRank := make(map[string]int)
+	if m.grouped() {
+		for _, s := range m.sessions {
+			if group := m.sessionGroup(s); group != "" && m.pinIndex(s.Name) < 0 {
+				if _, ok := groupRank[group]; !ok {
+					groupRank[group] = len(groupRank)
+				}
+			}
+		}
+	}
+	rankOf := func(s tmux.Session) (pin, group int) {
+		if i := m.pinIndex(s.Name); i >= 0 {
+			return i, 0
+		}
+		group, ok := groupRank[m.sessionGroup(s)]
+		if !ok {
+			group = len(groupRank) // ungrouped last
+		}
+		return len(m.pins), group
+	}
+	slices.SortStableFunc(m.sessions, func(a, b tmux.Session) int {
+		pinA, groupA := rankOf(a)
+		pinB, groupB := rankOf(b)
+		return cmp.Or(cmp.Compare(pinA, pinB), cmp.Compare(groupA, groupB))
+	})
+}
+
+// resort re-orders the sessions and rebuilds the list, keeping the cursor
+// on the row it was on.
+func (m *Model) resort() {
+	var prev Item
+	var name string
+	hadCursor := m.isCursorValid()
+	if hadCursor {
+		prev = m.items[m.cursor]
+		if s := m.getSession(prev); s != nil {
+			name = s.Name
+		}
+	}
+
+	m.orderSessions()
+	m.sessionFilter.SetItems(m.sessions)
+	m.rebuildItems()
+	if !hadCursor {
+		return
+	}
+
+	for i, it := range m.items {
+		if it.Type != prev.Type || it.IsSelf != prev.IsSelf || it.Group != prev.Group ||
+			it.WindowIndex != prev.WindowIndex || it.PaneIndex != prev.PaneIndex {
+			continue
+		}
+		if s := m.getSession(it); it.Type != ItemTypeGroup && (s == nil || s.Name != name) {
+			continue
+		}
+		m.cursor = i
+		break
+	}
+	m.updateScrollOffset()
+}
+
+// sortModes returns the sort modes C-s cycles through; urgency only with
+// agent tracking enabled.
+func (m *Model) sortModes() []string {
+	if len(m.agentKinds) > 0 {
+		return config.SortModes
+	}
+	return slices.DeleteFunc(slices.Clone(config.SortModes), func(mode string) bool {
+		return mode == config.SortUrgency
+	})
+}
+
+// cycleSort switches to the next sort mode. A choice other than
+// session_sort is kept in the session cache, so the next popup opens with it.
+func (m *Model) cycleSort() (tea.Model, tea.Cmd) {
+	modes := m.sortModes()
+	next := (slices.Index(modes, m.sortMode) + 1) % len(modes)
+	m.sortMode = modes[next]
+	m.resort()
+	m.saveSessionCache()
+	m.setMessage("Sorted by %s", m.sortMode)
+	return m, clearMessageAfter(3 * time.Second)
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+func TestOrderSessions(t *testing.T) {
+	now := time.Now()
+	sessions := []tmux.Session{
+		{Name: "web", LastActivity: now, Path: "/src/web"},
+		{Name: "Api", LastActivity: now.Add(-time.Minute), Path: "/src/api"},
+		{Name: "notes", LastActivity: now.Add(-2 * time.Minute)},
+		{Name: "dots", LastActivity: now.Add(-3 * time.Minute), Path: "/home/dots"},
+	}
+	statuses := map[string]map[string][]agent.Status{
+		"claude": {
+			"notes": {{State: "working"}},
+			"dots":  {{State: "working"}, {State: "waiting"}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		mode  string
+		pins  []string
+		group map[string]string // session → tag
+		want  []string
+	}{
+		{"activity", config.SortActivity, nil, nil, []string{"web", "Api", "notes", "dots"}},
+		{"name ignores case", config.SortName, nil, nil, []string{"Api", "dots", "notes", "web"}},
+		{"path, unknown last", config.SortPath, nil, nil, []string{"dots", "Api", "web", "notes"}},
+		{"urgency", config.SortUrgency, nil, nil, []string{"dots", "notes", "web", "Api"}},
+		{"pins first in slot order", config.SortName, []string{"web", "gone", "notes"}, nil, []string{"web", "notes", "Api", "dots"}},
+		{
+			"groups after pins", config.SortActivity, []string{"dots"},
+			map[string]string{"notes": "b", "Api": "a", "dots": "a"},
+			[]string{"dots", "Api", "notes", "web"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{
+				sessions:      append([]tmux.Session(nil), sessions...),
+				agentStatuses: statuses,
+				sortMode:      tt.mode,
+				pins:          tt.pins,
+			}
+			for i := range m.sessions {
+				m.sessions[i].Group = tt.group[m.sessions[i].Name]
+			}
+			m.orderSessions()
+
+			var got []string
+			for _, s := range m.sessions {
+				got = append(got, s.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJumpNumbers(t *testing.T) {
+	m := Model{
+		sessions: []tmux.Session{{Name: "a"}, {Name: "c"}, {Name: "e"}, {Name: "f"}},
+		pins:     []string{"a", "b", "c", "d"},
+	}
+
+	// "b" (the self session) and "d" (killed) keep their slots, so every
+	// pin stays on its key and unpinned sessions follow the last slot
+	m.selfSession = &tmux.Session{Name: "b"}
+	for i, want := range []int{0, 2, 4, 5} {
+		if got := m.jumpNum(i); got != want {
+			t.Errorf("jumpNum(%d) = %d, want %d", i, got, want)
+		}
+		if got := m.jumpIndex(want); got != i {
+			t.Errorf("jumpIndex(%d) = %d, want %d", want, got, i)
+		}
+	}
+	for _, num := range []int{-1, 1, 3, 6} {
+		if got := m.jumpIndex(num); got != -1 {
+			t.Errorf("jumpIndex(%d) = %d, want -1", num, got)
+		}
+	}
+
+	// Without pins, numbers are the list positions
+	m.pins = nil
+	m.sessions = m.sessions[2:]
+	if got := m.jumpNum(1); got != 1 {
+		t.Errorf("unpinned jumpNum(1) = %d, want 1", got)
+	}
+}
+
+func TestPinnedOutsideGroups(t *testing.T) {
+	m := Model{
+		sessions: []tmux.Session{
+			{Name: "api", Group: "work"},
+			{Name: "web", Group: "work"},
+		},
+		pins:          []string{"web"},
+		sessionFilter: NewFilter[tmux.Session](nil, nil),
+	}
+	m.orderSessions()
+	m.rebuildItems()
+
+	want := []string{"web", "@work", "api"}
+	if got := itemLabels(&m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+	if got := m.groupSize("work"); got != 1 {
+		t.Errorf("groupSize(work) = %d, want 1", got)
+	}
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+)
+
+// livenessSweepInterval is how often the status files are re-read with a
+// liveness check while the watcher runs. Hooks don't fire when an agent
+// crashes, so only the sweep notices those; status changes arrive through
+// the watcher right away.
+const livenessSweepInterval = 10 * time.Second
+
+// statusFilesChangedMsg reports that a status file in the cache dir changed.
+type statusFilesChangedMsg struct{}
+
+// statusWatcherClosedMsg reports that the watcher stopped delivering
+// events. The TUI falls back to polling.
+type statusWatcherClosedMsg struct{}
+
+// newStatusWatcher watches the cache dir for status file changes. Returns
+// nil if watching isn't possible (e.g. inotify limits reached); the TUI
+// then polls instead.
+func newStatusWatcher(cacheDir string) *fsnotify.Watcher {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil
+	}
+	if err := watcher.Add(cacheDir); err != nil {
+		watcher.Close()
+		return nil
+	}
+	return watcher
+}
+
+// watchStatusesCmd waits for the next status file change. The handler
+// re-issues it, so exactly one wait is pending at a time.
+func (m Model) watchStatusesCmd() tea.Cmd {
+	watcher := m.statusWatcher
+	if watcher == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return statusWatcherClosedMsg{}
+				}
+				if !m.agents.IsStatusFile(event.Name) {
+					continue
+				}
+				// Swallow the rest of the burst one hook write produces
+				settled := time.After(agent.WriteDebounce)
+				for waiting := true; waiting; {
+					select {
+					case _, ok := <-watcher.Events:
+						if !ok {
+							return statusWatcherClosedMsg{}
+						}
+					case <-settled:
+						waiting = false
+					}
+				}
+				return statusFilesChangedMsg{}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return statusWatcherClosedMsg{}
+				}
+			}
+		}
+	}
+}
+
+// livenessSweepDue reports whether the periodic status poll should run.
+// Without a watcher every poll tick is due. Records the sweep time on m.
+func (m *Model) livenessSweepDue() bool {
+	if m.statusWatcher != nil && time.Since(m.livenessSweptAt) < livenessSweepInterval {
+		return false
+	}
+	m.livenessSweptAt = time.Now()
+	return true
+}
+
+// Close stops the status watcher. Call it once the program has exited.
+func (m Model) Close() {
+	if m.statusWatcher != nil {
+		_ = m.statusWatcher.Close()
+	}
+}
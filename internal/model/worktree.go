@@ -0,0 +1,298 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/tmux"
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+// worktreesLoadedMsg carries the async worktree listing of a repo
+type worktreesLoadedMsg struct {
+	worktrees []git.Worktree
+	err       error
+}
+
+// worktreeAddedMsg reports the end of an async `git worktree add`
+type worktreeAddedMsg struct {
+	worktree git.Worktree
+	err      error
+}
+
+// openWorktreePicker enters ModePickWorktree for a repo from the project
+// picker. The project filter is cleared; the worktree filter starts empty.
+func (m *Model) openWorktreePicker(repo string) (tea.Model, tea.Cmd) {
+	m.worktreeRepo = repo
+	m.worktreeList.Reset()
+	m.worktreesLoading = true
+	m.mode = ModePickWorktree
+	return m, m.loadWorktreesCmd(repo)
+}
+
+// loadWorktreesCmd lists a repo's worktrees off the UI thread. Worktrees
+// whose directory is gone are left out.
+func (m Model) loadWorktreesCmd(repo string) tea.Cmd {
+	return func() tea.Msg {
+		worktrees, err := git.ListWorktrees(repo)
+		live := worktrees[:0]
+		for _, wt := range worktrees {
+			if !wt.Prunable {
+				live = append(live, wt)
+			}
+		}
+		return worktreesLoadedMsg{worktrees: live, err: err}
+	}
+}
+
+func (m *Model) handlePickWorktreeMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := ui.DefaultKeyMap
+
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		if m.worktreeList.Filter() != "" {
+			m.worktreeList.SetFilter("")
+			return m, nil
+		}
+		m.mode = ModePickDirectory
+		return m, nil
+
+	case key.Matches(msg, keys.Collapse):
+		m.mode = ModePickDirectory
+		return m, nil
+
+	case key.Matches(msg, keys.Up):
+		m.worktreeList.MoveCursor(-1)
+
+	case key.Matches(msg, keys.Down):
+		m.worktreeList.MoveCursor(1)
+
+	case key.Matches(msg, keys.Select):
+		if selected, ok := m.worktreeList.SelectedItem(); ok {
+			return m.openWorktree(selected)
+		}
+		// No match: the filter names a new branch
+		return m.createWorktree(m.worktreeList.Filter())
+
+	case key.Matches(msg, keys.Create):
+		return m.createWorktree(m.worktreeList.Filter())
+
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+
+	default:
+		m.worktreeList.HandleKey(msg)
+	}
+
+	return m, nil
+}
+
+// worktreeLabel is the name a worktree is listed and filtered by.
+func worktreeLabel(wt git.Worktree) string {
+	if wt.Branch != "" {
+		return wt.Branch
+	}
+	return shortHead(wt.Head)
+}
+
+// shortHead abbreviates a commit hash for display and session names.
+func shortHead(head string) string {
+	if len(head) > 7 {
+		return head[:7]
+	}
+	return head
+}
+
+// worktreeSessionName returns the session name for a worktree of the
+// browsed repo: the repo's own name for the main worktree, repo@branch
+// for linked ones.
+func (m *Model) worktreeSessionName(wt git.Worktree) string {
+	repoSession := m.extractSessionName(m.worktreeRepo)
+	if wt.Main {
+		return repoSession
+	}
+	return config.WorktreeSessionName(repoSession, worktreeLabel(wt))
+}
+
+// createWorktree creates a worktree with a new (or existing) branch off
+// the UI thread — the checkout can take a while — and opens it as a
+// session once it's there.
+func (m *Model) createWorktree(branch string) (tea.Model, tea.Cmd) {
+	if m.worktreeAdding != "" {
+		return m, nil
+	}
+	branch = strings.ReplaceAll(strings.TrimSpace(branch), " ", "-")
+	if branch == "" {
+		m.setError("Type a branch name to create a worktree")
+		return m, nil
+	}
+
+	repo := m.worktreeRepo
+	path := config.WorktreePath(m.config.WorktreeDir, repo, branch)
+	m.worktreeAdding = branch
+	m.setMessage("Creating worktree %s...", branch)
+	return m, func() tea.Msg {
+		err := git.AddWorktree(repo, path, branch)
+		return worktreeAddedMsg{worktree: git.Worktree{Path: path, Branch: branch}, err: err}
+	}
+}
+
+// handleWorktreeAdded opens a created worktree, unless the picker was left
+// while it was being added.
+func (m *Model) handleWorktreeAdded(msg worktreeAddedMsg) (tea.Model, tea.Cmd) {
+	m.worktreeAdding = ""
+	if msg.err != nil {
+		m.setError("Worktree failed: %v", msg.err)
+		return m, nil
+	}
+	if m.mode != ModePickWorktree {
+		m.setMessage("Created worktree %s", msg.worktree.Branch)
+		return m, clearMessageAfter(5 * time.Second)
+	}
+	m.message = ""
+	return m.openWorktree(msg.worktree)
+}
+
+// openWorktree switches to the worktree's session, creating it first.
+func (m *Model) openWorktree(wt git.Worktree) (tea.Model, tea.Cmd) {
+	name := m.worktreeSessionName(wt)
+
+	if !tmux.SessionExists(name) {
+		if err := tmux.CreateSession(name, wt.Path); err != nil {
+			m.setError("Error: %v", err)
+			return m, nil
+		}
+		m.applyLayout(name, wt.Path)
+	}
+
+	if err := tmux.SwitchClient(name); err != nil {
+		m.setError("Failed to switch: %v", err)
+		return m, nil
+	}
+	return m, tea.Quit
+}
+
+// worktreeOfSession returns the linked worktree a session was started in
+// and its main worktree, or "" if the session isn't a worktree session.
+func worktreeOfSession(sessionName string) (worktree, mainRepo string) {
+	dir, err := tmux.DisplayMessage(sessionName, "#{session_path}")
+	if err != nil || dir == "" {
+		return "", ""
+	}
+	mainRepo, err = git.MainWorktree(dir)
+	if err != nil {
+		return "", ""
+	}
+	return dir, mainRepo
+}
+
+// confirmPruneWorktree offers to remove a killed session's worktree.
+func (m *Model) confirmPruneWorktree(sessionName, worktree, mainRepo string) {
+	m.pruneTarget = worktree
+	m.pruneRepo = mainRepo
+	m.message = fmt.Sprintf("Killed \"%s\". Prune worktree %s?", sessionName, filepath.Base(worktree))
+	m.messageIsError = false
+	m.mode = ModeConfirmPruneWorktree
+}
+
+func (m *Model) handleConfirmPruneWorktreeMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := ui.DefaultKeyMap
+
+	switch {
+	case key.Matches(msg, keys.Kill):
+		m.mode = ModeNormal
+		if err := git.RemoveWorktree(m.pruneRepo, m.pruneTarget); err != nil {
+			m.setError("Prune failed: %v", err)
+		} else {
+			m.setMessage("Pruned worktree %s", filepath.Base(m.pruneTarget))
+		}
+		m.pruneTarget, m.pruneRepo = "", ""
+		return m, clearMessageAfter(5 * time.Second)
+
+	case key.Matches(msg, keys.Cancel):
+		m.mode = ModeNormal
+		m.message = ""
+		m.pruneTarget, m.pruneRepo = "", ""
+
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+// viewPickWorktree renders the worktree list of the browsed repo
+func (m Model) viewPickWorktree() string {
+	var header strings.Builder
+	var b strings.Builder
+
+	header.WriteString(ui.RenderTitleBar(config.AppName, m.mode.String(), m.width))
+	header.WriteString("\n")
+
+	filter := m.worktreeList.Filter()
+	header.WriteString(ui.RenderPrompt(filter, m.width))
+	header.WriteString("\n")
+
+	header.WriteString(ui.RenderBorder(m.borderWidth()))
+	header.WriteString("\n")
+
+	maxItems := m.projectMaxVisibleItems()
+	m.worktreeList.SetHeight(maxItems)
+
+	visibleItems := m.worktreeList.VisibleItems()
+	scrollOffset := m.worktreeList.ScrollOffset()
+	totalItems := m.worktreeList.Len()
+
+	scrollbar := ui.ScrollbarChars(totalItems, maxItems, scrollOffset, len(visibleItems))
+
+	for i, wt := range visibleItems {
+		selected := m.worktreeList.IsSelected(scrollOffset + i)
+
+		if i < len(scrollbar) {
+			b.WriteString(scrollbar[i])
+			b.WriteString(" ")
+		}
+
+		label := worktreeLabel(wt)
+		if wt.Main {
+			label += " (main)"
+		}
+		// Mark worktrees that already have a session
+		if m.findSessionByName(m.worktreeSessionName(wt)) != nil {
+			label = "● " + label
+		} else {
+			label = "  " + label
+		}
+		if selected {
+			b.WriteString(ui.FilterStyle.Render(label))
+		} else {
+			b.WriteString(label)
+		}
+		b.WriteString("  " + ui.TimeStyle.Render(m.extractDisplayPath(wt.Path)))
+		b.WriteString("\n")
+	}
+
+	if totalItems == 0 {
+		switch {
+		case m.worktreesLoading:
+			b.WriteString("  Loading worktrees...\n")
+		case filter != "":
+			fmt.Fprintf(&b, "  Enter to create worktree on branch \"%s\"\n", filter)
+		default:
+			b.WriteString("  No worktrees\n")
+		}
+	}
+
+	notification := m.message
+	if notification == "" {
+		notification = m.extractDisplayPath(m.worktreeRepo)
+	}
+	return m.renderWithSidebar(header.String(), b.String(), ui.WorktreeActions, notification, m.messageIsError)
+}
@@ -0,0 +1,178 @@
+// Package reap finds idle sessions to clean up by the reap policies of the
+// config, and kills them — after saving them to a snapshot, so they can be
+// restored.
+package reap
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/git"
+	"github.com/black-atom-industries/helm/internal/snapshot"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// Session is what the policies look at for one tmux session.
+type Session struct {
+	Name         string
+	Path         string
+	LastActivity time.Time
+	Attached     int         // Attached clients
+	AgentAlive   bool        // A live agent process runs in it
+	Git          *git.Status // nil when unknown
+	Pinned       bool
+}
+
+// Check applies the policies to a session. It returns "" when the session
+// is eligible, else the reason it is kept.
+func Check(p config.ReapConfig, s Session, now time.Time) string {
+	idle := now.Sub(s.LastActivity)
+	switch {
+	case s.Pinned:
+		return "pinned"
+	case slices.ContainsFunc(p.Keep, func(pattern string) bool {
+		ok, _ := filepath.Match(pattern, s.Name)
+		return ok
+	}):
+		return "kept by reap.keep"
+	case idle < p.IdleAfter:
+		return fmt.Sprintf("active %s ago", formatIdle(idle))
+	case p.Detached && s.Attached > 0:
+		return "attached"
+	case p.NoAgent && s.AgentAlive:
+		return "agent running"
+	case p.Clean && s.Git == nil:
+		return "git status unknown"
+	case p.Clean && !s.Git.IsClean():
+		return "uncommitted changes"
+	}
+	return ""
+}
+
+// formatIdle formats an idle time coarsely: "3d", "5h", "12m".
+func formatIdle(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// Collect reads the facts the policies need for every session but the
+// current one: tmux state, agent liveness and git status (the repo at the
+// active pane's directory, else the session's start directory).
+func Collect(cfg config.Config, current string, pins []string) ([]Session, error) {
+	listed, err := tmux.ListSessions(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	// Untracked agents count too; without a liveness check every session
+	// counts as running one
+	var live agent.Liveness
+	checked := false
+	agents := agent.NewRegistry(cfg)
+	kinds := agents.Kinds()
+	if panePIDs, err := tmux.PanePIDs(); err == nil {
+		live, err = agents.CheckLiveness(panePIDs)
+		checked = err == nil
+	}
+
+	sessions := make([]Session, len(listed))
+	for i, s := range listed {
+		sessions[i] = Session{
+			Name:         s.Name,
+			Path:         s.Path,
+			LastActivity: s.LastActivity,
+			Attached:     s.Attached,
+			AgentAlive:   !checked || slices.ContainsFunc(kinds, func(k agent.Kind) bool { return live.Alive(k, s.Name) }),
+			Pinned:       slices.Contains(pins, s.Name),
+		}
+	}
+
+	var wg sync.WaitGroup
+	const maxParallel = 8
+	sem := make(chan struct{}, maxParallel)
+	for i := range sessions {
+		wg.Add(1)
+		go func(s *Session) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if path, err := git.GetSessionPath("=" + s.Name + ":"); err == nil && path != "" {
+				s.Path = path
+			}
+			s.Git = GitStatus(s.Path)
+		}(&sessions[i])
+	}
+	wg.Wait()
+	return sessions, nil
+}
+
+// GitStatus reads the git status the policies judge a session at path by;
+// nil (unknown) without a path. A directory that is no repo counts as clean.
+func GitStatus(path string) *git.Status {
+	if path == "" {
+		return nil
+	}
+	status := git.GetStatus(path)
+	return &status
+}
+
+// SnapshotPath returns the snapshot reaped sessions are saved to.
+func SnapshotPath(cacheDir string) string {
+	return filepath.Join(cacheDir, config.ReapedFileName)
+}
+
+// Result reports what Kill did with one session.
+type Result struct {
+	Session string
+	Saved   bool // Added to the snapshot
+	Err     error
+}
+
+// Kill kills the named sessions. With save, their windows and panes are
+// added to the snapshot at SnapshotPath first; a session that can't be
+// saved isn't killed.
+func Kill(cacheDir string, names []string, save bool) []Result {
+	results := make([]Result, 0, len(names))
+	kill := names
+	if save {
+		kill = nil
+		var saved []snapshot.Session
+		for _, name := range names {
+			s, err := snapshot.CaptureSession("=" + name)
+			if err != nil {
+				results = append(results, Result{Session: name, Err: err})
+				continue
+			}
+			s.Name = name
+			saved = append(saved, s)
+			kill = append(kill, name)
+		}
+		if err := snapshot.Add(SnapshotPath(cacheDir), saved...); err != nil {
+			for _, name := range kill {
+				results = append(results, Result{Session: name, Err: fmt.Errorf("failed to save snapshot: %w", err)})
+			}
+			return results
+		}
+	}
+
+	for _, name := range kill {
+		err := tmux.KillSession("=" + name)
+		if err != nil {
+			err = fmt.Errorf("failed to kill: %w", err)
+		}
+		results = append(results, Result{Session: name, Saved: save, Err: err})
+	}
+	return results
+}
@@ -0,0 +1,73 @@
+package reap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/git"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	policy := config.DefaultConfig().Reap
+	policy.Keep = []string{"main", "dot*"}
+
+	clean := &git.Status{IsRepo: true, Branch: "main"}
+	dirty := &git.Status{IsRepo: true, Branch: "main", Dirty: 2}
+	idle := Session{Name: "api", LastActivity: now.Add(-48 * time.Hour), Git: clean}
+
+	with := func(change func(s *Session)) Session {
+		s := idle
+		change(&s)
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		policy  config.ReapConfig
+		session Session
+		want    string
+	}{
+		{"idle and clean", policy, idle, ""},
+		{"not a repo", policy, with(func(s *Session) { s.Git = &git.Status{} }), ""},
+		{"recently active", policy, with(func(s *Session) { s.LastActivity = now.Add(-3 * time.Hour) }), "active 3h ago"},
+		{"attached", policy, with(func(s *Session) { s.Attached = 1 }), "attached"},
+		{"agent running", policy, with(func(s *Session) { s.AgentAlive = true }), "agent running"},
+		{"dirty", policy, with(func(s *Session) { s.Git = dirty }), "uncommitted changes"},
+		{"git unknown", policy, with(func(s *Session) { s.Git = nil }), "git status unknown"},
+		{"pinned", policy, with(func(s *Session) { s.Pinned = true }), "pinned"},
+		{"kept by name", policy, with(func(s *Session) { s.Name = "main" }), "kept by reap.keep"},
+		{"kept by glob", policy, with(func(s *Session) { s.Name = "dotfiles" }), "kept by reap.keep"},
+		{
+			"policies off",
+			config.ReapConfig{IdleAfter: time.Hour},
+			with(func(s *Session) { s.Attached, s.AgentAlive, s.Git = 2, true, dirty }),
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(tt.policy, tt.session, now); got != tt.want {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatIdle(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{12 * time.Minute, "12m"},
+		{5*time.Hour + 30*time.Minute, "5h"},
+		{75 * time.Hour, "3d"},
+	}
+	for _, tt := range tests {
+		if got := formatIdle(tt.d); got != tt.want {
+			t.Errorf("formatIdle(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// ErrNoneWaiting is returned by NextWaiting when no agent waits for input.
+var ErrNoneWaiting = errors.New("no agent is waiting")
+
+// waitingTarget is the pane of an agent instance waiting for input.
+type waitingTarget struct {
+	session string
+	window  int
+	pane    int // -1 when the agent's pane couldn't be found
+	kind    string
+	since   time.Time
+}
+
+// String returns the tmux target: session:window.pane, or just the
+// session when the pane is unknown.
+func (t waitingTarget) String() string {
+	if t.pane < 0 {
+		return t.session
+	}
+	return fmt.Sprintf("%s:%d.%d", t.session, t.window, t.pane)
+}
+
+// NextWaiting switches the client to the pane of the longest-waiting agent
+// and returns its target. When that pane is already the current one it
+// moves on to the next-longest, so repeated calls cycle through all
+// waiting agents.
+func NextWaiting(cfg config.Config) (string, error) {
+	sessions, err := tmux.ListSessions("")
+	if err != nil {
+		return "", fmt.Errorf("failed to list sessions: %w", err)
+	}
+	names := make([]string, len(sessions))
+	for i, s := range sessions {
+		names[i] = s.Name
+	}
+
+	agents := agent.NewRegistry(cfg)
+	kinds := agents.Enabled()
+	statuses := make(map[string]map[string][]agent.Status) // kind → session → instances
+	for _, kind := range kinds {
+		statuses[kind.Name] = agent.ReadAll(kind, names, cfg.CacheDir)
+	}
+
+	var paneAgents map[int]string
+	if panePIDs, err := tmux.PanePIDs(); err == nil {
+		if live, err := agents.CheckLiveness(panePIDs); err == nil {
+			for _, kind := range kinds {
+				agent.DropDead(kind, statuses[kind.Name], live, cfg.CacheDir)
+			}
+			paneAgents = live.PaneAgents()
+		}
+	}
+
+	// Panes are only needed for sessions with a waiting agent
+	panes := make(map[string]map[int][]tmux.Pane)
+	for _, bySession := range statuses {
+		for name, instances := range bySession {
+			if _, done := panes[name]; done {
+				continue
+			}
+			if slices.ContainsFunc(instances, func(s agent.Status) bool { return s.State == "waiting" }) {
+				panes[name], _ = tmux.ListSessionPanes(name)
+			}
+		}
+	}
+
+	current, _ := tmux.CurrentPane()
+	target, ok := nextWaiting(waitingTargets(statuses, panes, paneAgents), current)
+	if !ok {
+		return "", ErrNoneWaiting
+	}
+
+	if target.pane < 0 {
+		err = tmux.SwitchClient(target.session)
+	} else {
+		err = tmux.SelectPane(target.session, target.window, target.pane)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to switch to %s: %w", target, err)
+	}
+	return target.String(), nil
+}
+
+// waitingTargets locates the pane of every waiting agent instance, longest
+// waiting first. Instances are matched to panes running their agent kind,
+// preferring the pane whose directory is the agent's cwd; working
+// instances claim their panes too, so a waiting agent isn't attributed to
+// a busy sibling's pane.
+func waitingTargets(statuses map[string]map[string][]agent.Status, panes map[string]map[int][]tmux.Pane, paneAgents map[int]string) []waitingTarget {
+	var targets []waitingTarget
+
+	for kind, bySession := range statuses {
+		for session, instances := range bySession {
+			// Candidate panes in window order
+			type candidate struct {
+				window int
+				pane   tmux.Pane
+			}
+			var candidates []candidate
+			windows := make([]int, 0, len(panes[session]))
+			for w := range panes[session] {
+				windows = append(windows, w)
+			}
+			slices.Sort(windows)
+			for _, w := range windows {
+				for _, p := range panes[session][w] {
+					if paneAgents[p.PID] == kind {
+						candidates = append(candidates, candidate{window: w, pane: p})
+					}
+				}
+			}
+
+			claimed := make([]bool, len(candidates))
+			assigned := make([]int, len(instances))
+			for i, s := range instances {
+				assigned[i] = -1
+				if s.Cwd == "" {
+					continue
+				}
+				for c := range candidates {
+					if !claimed[c] && candidates[c].pane.Path == s.Cwd {
+						claimed[c], assigned[i] = true, c
+						break
+					}
+				}
+			}
+
+			for i, s := range instances {
+				if s.State != "waiting" {
+					continue
+				}
+				if assigned[i] < 0 {
+					for c := range candidates {
+						if !claimed[c] {
+							claimed[c], assigned[i] = true, c
+							break
+						}
+					}
+				}
+				t := waitingTarget{session: session, pane: -1, kind: kind, since: s.Timestamp}
+				if c := assigned[i]; c >= 0 {
+					t.window, t.pane = candidates[c].window, candidates[c].pane.Index
+				}
+				targets = append(targets, t)
+			}
+		}
+	}
+
+	slices.SortFunc(targets, func(a, b waitingTarget) int {
+		if c := a.since.Compare(b.since); c != 0 {
+			return c
+		}
+		return strings.Compare(a.String(), b.String())
+	})
+	return targets
+}
+
+// nextWaiting returns the target after the current pane, wrapping around,
+// or the longest-waiting target when the current pane isn't one of them.
+func nextWaiting(targets []waitingTarget, current string) (waitingTarget, bool) {
+	if len(targets) == 0 {
+		return waitingTarget{}, false
+	}
+	currentSession, _, _ := strings.Cut(current, ":")
+	for i, t := range targets {
+		if t.String() == current || (t.pane < 0 && t.session == currentSession) {
+			return targets[(i+1)%len(targets)], true
+		}
+	}
+	return targets[0], true
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+func TestWaitingTargets(t *testing.T) {
+	now := time.Now()
+	statuses := map[string]map[string][]agent.Status{
+		"claude": {
+			// Two agents in one session: the working one sits in /api,
+			// the waiting one has no cwd and gets the other claude pane
+			"api": {
+				{State: "working", Timestamp: now, Cwd: "/api"},
+				{State: "waiting", Timestamp: now.Add(-2 * time.Minute)},
+			},
+			"web": {{State: "waiting", Timestamp: now.Add(-10 * time.Minute), Cwd: "/web/ui"}},
+		},
+		"pi": {
+			"docs": {{State: "waiting", Timestamp: now.Add(-time.Minute)}}, // pane not found
+		},
+	}
+	panes := map[string]map[int][]tmux.Pane{
+		"api": {
+			1: {{Index: 0, PID: 100, Path: "/api"}, {Index: 1, PID: 101, Path: "/api"}},
+			2: {{Index: 0, PID: 102, Path: "/api/sub"}},
+		},
+		"web": {
+			1: {{Index: 0, PID: 200, Path: "/web"}, {Index: 1, PID: 201, Path: "/web/ui"}},
+		},
+	}
+	paneAgents := map[int]string{100: "claude", 102: "claude", 200: "claude", 201: "claude"}
+
+	got := waitingTargets(statuses, panes, paneAgents)
+	var targets []string
+	for _, target := range got {
+		targets = append(targets, target.String())
+	}
+	want := []string{"web:1.1", "api:2.0", "docs"}
+	if len(targets) != len(want) {
+		t.Fatalf("waitingTargets() = %v, want %v", targets, want)
+	}
+	for i := range want {
+		if targets[i] != want[i] {
+			t.Errorf("waitingTargets() = %v, want %v", targets, want)
+			break
+		}
+	}
+}
+
+func TestNextWaiting(t *testing.T) {
+	targets := []waitingTarget{
+		{session: "web", window: 1, pane: 1},
+		{session: "api", window: 2, pane: 0},
+		{session: "docs", pane: -1},
+	}
+
+	tests := []struct {
+		name    string
+		current string
+		want    string
+	}{
+		{"elsewhere starts with longest waiting", "other:1.0", "web:1.1"},
+		{"on first target cycles to second", "web:1.1", "api:2.0"},
+		{"same session other pane starts over", "api:1.0", "web:1.1"},
+		{"session-level target matches by session", "docs:3.2", "web:1.1"},
+		{"no current pane", "", "web:1.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := nextWaiting(targets, tt.current)
+			if !ok || got.String() != tt.want {
+				t.Errorf("nextWaiting(%q) = %v, %v; want %q", tt.current, got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := nextWaiting(nil, "web:1.1"); ok {
+		t.Error("nextWaiting() with no targets reported ok")
+	}
+}
@@ -0,0 +1,108 @@
+// Package session holds what helm does with tmux sessions outside the TUI
+// as much as in it: groups, pins, renames that carry along everything keyed
+// by session name, and cycling through agents waiting for input.
+package session
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// Group returns the group a session is listed under: its manual tag,
+// else the one group_by derives from its path. Empty if neither applies.
+func Group(cfg config.Config, s tmux.Session) string {
+	if s.Group != "" {
+		return s.Group
+	}
+	switch cfg.GroupBy {
+	case config.GroupByProjectDir:
+		return projectDirGroup(s.Path, cfg.ProjectDirs)
+	case config.GroupByGitHost:
+		return gitHostGroup(s.Path, cfg.ProjectDirs, cfg.GitProviders)
+	}
+	return ""
+}
+
+// projectRoot returns the project dir a path lies in (the deepest, when
+// they nest) and the path relative to it.
+func projectRoot(path string, dirs []string) (root, rel string, ok bool) {
+	if path == "" {
+		return "", "", false
+	}
+	for _, dir := range dirs {
+		r, err := filepath.Rel(dir, path)
+		if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if !ok || len(dir) > len(root) {
+			root, rel, ok = dir, r, true
+		}
+	}
+	return root, rel, ok
+}
+
+// projectDirGroup groups by project dir root: ~/work/api → "work".
+func projectDirGroup(path string, dirs []string) string {
+	root, _, ok := projectRoot(path, dirs)
+	if !ok {
+		return ""
+	}
+	return filepath.Base(root)
+}
+
+// gitHostGroup groups by the git host a repo was cloned from, following the
+// clone layout of giturl.ResolveRepoDir: <alias>/owner/repo for hosts with a
+// git_providers alias, <host>/owner/repo for unknown hosts, and bare
+// owner/repo for hosts mapped to "" (github.com by default). A path too
+// short for the layout (a project that isn't a clone, like ~/repos/notes)
+// has no host.
+func gitHostGroup(path string, dirs []string, providers map[string]string) string {
+	_, rel, ok := projectRoot(path, dirs)
+	if !ok || rel == "." {
+		return ""
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	first := segments[0]
+	hostLayout := len(segments) >= 3 // <alias or host>/owner/repo
+
+	hosts := make([]string, 0, len(providers))
+	for host := range providers {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		if alias := providers[host]; alias != "" && alias == first {
+			if hostLayout {
+				return alias
+			}
+			return ""
+		}
+	}
+
+	// The host owning the bare layout: github.com unless it got an alias
+	bare := "github.com"
+	if alias := providers[bare]; alias != "" {
+		bare = ""
+		for _, host := range hosts {
+			if providers[host] == "" {
+				bare = host
+				break
+			}
+		}
+	}
+	if strings.Contains(first, ".") {
+		if hostLayout {
+			return first // <host>/owner/repo
+		}
+		return ""
+	}
+	if len(segments) < 2 {
+		return "" // Not owner/repo
+	}
+	return bare
+}
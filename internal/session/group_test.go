@@ -0,0 +1,42 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+func TestGroup(t *testing.T) {
+	cfg := config.Config{
+		ProjectDirs:  []string{"/home/u/repos", "/home/u/work"},
+		GitProviders: map[string]string{"git.corp.example.com": "corp"},
+	}
+
+	tests := []struct {
+		name    string
+		groupBy string
+		session tmux.Session
+		want    string
+	}{
+		{"tag wins", config.GroupByProjectDir, tmux.Session{Path: "/home/u/work/api", Group: "oss"}, "oss"},
+		{"project dir", config.GroupByProjectDir, tmux.Session{Path: "/home/u/work/api"}, "work"},
+		{"outside project dirs", config.GroupByProjectDir, tmux.Session{Path: "/tmp"}, ""},
+		{"no group_by", "", tmux.Session{Path: "/home/u/work/api"}, ""},
+		{"github layout", config.GroupByGitHost, tmux.Session{Path: "/home/u/repos/nik/helm"}, "github.com"},
+		{"provider alias", config.GroupByGitHost, tmux.Session{Path: "/home/u/repos/corp/team/api/src"}, "corp"},
+		{"unknown host", config.GroupByGitHost, tmux.Session{Path: "/home/u/repos/gitlab.com/x/y"}, "gitlab.com"},
+		{"project dir itself", config.GroupByGitHost, tmux.Session{Path: "/home/u/repos"}, ""},
+		{"not a clone", config.GroupByGitHost, tmux.Session{Path: "/home/u/repos/notes"}, ""},
+		{"alias without owner/repo", config.GroupByGitHost, tmux.Session{Path: "/home/u/repos/corp/api"}, ""},
+		{"host without owner/repo", config.GroupByGitHost, tmux.Session{Path: "/home/u/repos/gitlab.com/x"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.GroupBy = tt.groupBy
+			if got := Group(cfg, tt.session); got != tt.want {
+				t.Errorf("Group() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// pinsFile returns the file pinned session names are kept in. Separate
+// from the session cache: pins are user intent, not a cache.
+func pinsFile(cacheDir string) string {
+	return filepath.Join(cacheDir, "pins.json")
+}
+
+// LoadPins returns the pinned session names in slot order, nil if none.
+func LoadPins(cacheDir string) []string {
+	data, err := os.ReadFile(pinsFile(cacheDir))
+	if err != nil {
+		return nil
+	}
+	var pins []string
+	if err := json.Unmarshal(data, &pins); err != nil {
+		return nil
+	}
+	return pins
+}
+
+// savePins writes the pinned session names.
+func savePins(cacheDir string, pins []string) error {
+	data, err := json.Marshal(pins)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(pinsFile(cacheDir), data, 0644)
+}
+
+// SetPinned pins a session into the next free slot, or unpins it. Pins are
+// kept by name, also while the session doesn't exist: recreating it puts
+// it back in its slot. Shared by the TUI and `helm sessions pin`.
+func SetPinned(cacheDir, name string, pinned bool) error {
+	pins := LoadPins(cacheDir)
+	if slices.Contains(pins, name) == pinned {
+		return nil
+	}
+	if pinned {
+		pins = append(pins, name)
+	} else {
+		pins = slices.DeleteFunc(pins, func(p string) bool { return p == name })
+	}
+	return savePins(cacheDir, pins)
+}
+
+// renamePin moves a renamed session's pin to its new name, keeping its slot.
+func renamePin(cacheDir, oldName, newName string) error {
+	pins := LoadPins(cacheDir)
+	i := slices.Index(pins, oldName)
+	if i < 0 {
+		return nil
+	}
+	pins[i] = newName
+	return savePins(cacheDir, pins)
+}
@@ -0,0 +1,26 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetPinned(t *testing.T) {
+	dir := t.TempDir()
+	for _, step := range []struct {
+		name   string
+		pinned bool
+	}{{"a", true}, {"b", true}, {"a", true}, {"c", true}, {"b", false}} {
+		if err := SetPinned(dir, step.name, step.pinned); err != nil {
+			t.Fatalf("SetPinned(%s, %v): %v", step.name, step.pinned, err)
+		}
+	}
+	if err := renamePin(dir, "c", "d"); err != nil {
+		t.Fatalf("renamePin: %v", err)
+	}
+
+	want := []string{"a", "d"}
+	if got := LoadPins(dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadPins() = %v, want %v", got, want)
+	}
+}
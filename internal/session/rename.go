@@ -0,0 +1,93 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/lib/frecency"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// CacheFile returns the file the TUI caches the session list in, for an
+// instant first paint.
+func CacheFile(cacheDir string) string {
+	return filepath.Join(cacheDir, "sessions.json")
+}
+
+// FrecencyKey is the frecency store key of a session.
+func FrecencyKey(name string) string { return "session:" + name }
+
+// Rename renames a tmux session and migrates everything helm keys by
+// session name: agent status files, bookmarks opening the session, its
+// pin, its frecency and the session cache.
+func Rename(cfg *config.Config, oldName, newName string) error {
+	if tmux.SessionExists(newName) {
+		return fmt.Errorf("session %q already exists", newName)
+	}
+	if err := tmux.RenameSession(oldName, newName); err != nil {
+		return fmt.Errorf("failed to rename session: %w", err)
+	}
+
+	// The session is renamed at this point; migration failures are
+	// reported but don't undo it
+	var errs []string
+	for _, kind := range agent.NewRegistry(*cfg).Kinds() {
+		if err := agent.RenameStatuses(kind, oldName, newName, cfg.CacheDir); err != nil {
+			errs = append(errs, fmt.Sprintf("%s status: %v", kind.Name, err))
+		}
+	}
+	if cfg.RenameBookmarkSession(oldName, newName) {
+		if err := cfg.SaveBookmarks(); err != nil {
+			errs = append(errs, fmt.Sprintf("bookmarks: %v", err))
+		}
+	}
+	if err := renamePin(cfg.CacheDir, oldName, newName); err != nil {
+		errs = append(errs, fmt.Sprintf("pin: %v", err))
+	}
+	if store := frecency.Load(cfg.CacheDir); store.Rename(FrecencyKey(oldName), FrecencyKey(newName)) {
+		if err := store.Save(); err != nil {
+			errs = append(errs, fmt.Sprintf("frecency: %v", err))
+		}
+	}
+	renameCached(cfg.CacheDir, oldName, newName)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("renamed, but migration failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// renameCached renames a session in the session cache so the first paint
+// after a rename doesn't show the old name. The rest of the cache is the
+// TUI's and kept as is.
+func renameCached(cacheDir, oldName, newName string) {
+	path := CacheFile(cacheDir)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var cache map[string]json.RawMessage
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return
+	}
+	var sessions []map[string]any
+	if err := json.Unmarshal(cache["sessions"], &sessions); err != nil {
+		return
+	}
+	for _, s := range sessions {
+		if s["name"] == oldName {
+			s["name"] = newName
+		}
+	}
+	if cache["sessions"], err = json.Marshal(sessions); err != nil {
+		return
+	}
+	if data, err := json.Marshal(cache); err == nil {
+		_ = os.WriteFile(path, data, 0644)
+	}
+}
@@ -0,0 +1,35 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestRenameCached(t *testing.T) {
+	cacheDir := t.TempDir()
+	cache := `{"sessions":[{"name":"old","path":"/repos/old"},{"name":"other"}],"max_name_width":12,"sort":"name"}`
+	if err := os.WriteFile(CacheFile(cacheDir), []byte(cache), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	renameCached(cacheDir, "old", "new")
+
+	data, err := os.ReadFile(CacheFile(cacheDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"sessions":       []any{map[string]any{"name": "new", "path": "/repos/old"}, map[string]any{"name": "other"}},
+		"max_name_width": 12.0,
+		"sort":           "name",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cache = %v, want %v", got, want)
+	}
+}
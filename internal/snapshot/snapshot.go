@@ -0,0 +1,269 @@
+// Package snapshot saves the tmux server's sessions — windows, pane
+// layouts, working directories and running programs — to a JSON file and
+// recreates them after a tmux server restart.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// version is bumped on incompatible format changes.
+const version = 1
+
+// Snapshot is the on-disk representation of all sessions.
+type Snapshot struct {
+	Version  int       `json:"version"`
+	SavedAt  time.Time `json:"saved_at"`
+	Sessions []Session `json:"sessions"`
+}
+
+// Session is one saved tmux session.
+type Session struct {
+	Name    string   `json:"name"`
+	Windows []Window `json:"windows"`
+}
+
+// Window is one saved tmux window.
+type Window struct {
+	Index  int    `json:"index"`
+	Name   string `json:"name"`
+	Layout string `json:"layout"`
+	Active bool   `json:"active,omitempty"`
+	Panes  []Pane `json:"panes"`
+}
+
+// Pane is one saved tmux pane.
+type Pane struct {
+	Path    string `json:"path"`
+	Command string `json:"command,omitempty"`
+	Active  bool   `json:"active,omitempty"`
+}
+
+// Path returns the default snapshot file in the cache dir.
+func Path(cacheDir string) string {
+	return filepath.Join(cacheDir, config.SnapshotFileName)
+}
+
+// Capture reads all sessions from the running tmux server. Popup sessions
+// are skipped, like in the session list.
+func Capture() (Snapshot, error) {
+	sessions, err := tmux.ListSessions("")
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	snap := Snapshot{Version: version, SavedAt: time.Now()}
+	for _, s := range sessions {
+		session, err := CaptureSession(s.Name)
+		if err != nil {
+			continue // session vanished mid-capture
+		}
+		snap.Sessions = append(snap.Sessions, session)
+	}
+	return snap, nil
+}
+
+// CaptureSession reads one session from the running tmux server.
+func CaptureSession(name string) (Session, error) {
+	windows, err := tmux.ListWindows(name)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to list windows of %s: %w", name, err)
+	}
+	panesByWindow, err := tmux.ListSessionPanes(name)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to list panes of %s: %w", name, err)
+	}
+	return buildSession(name, windows, panesByWindow), nil
+}
+
+// buildSession converts listed tmux windows and panes to a saved session.
+func buildSession(name string, windows []tmux.Window, panesByWindow map[int][]tmux.Pane) Session {
+	session := Session{Name: name}
+	for _, w := range windows {
+		window := Window{Index: w.Index, Name: w.Name, Layout: w.Layout, Active: w.Active}
+		for _, p := range panesByWindow[w.Index] {
+			window.Panes = append(window.Panes, Pane{Path: p.Path, Command: p.Command, Active: p.Active})
+		}
+		if len(window.Panes) > 0 {
+			session.Windows = append(session.Windows, window)
+		}
+	}
+	return session
+}
+
+// Save writes the snapshot to path, replacing it atomically so a crash
+// mid-write never destroys the previous snapshot.
+func Save(snap Snapshot, path string) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Add saves sessions into the snapshot at path, replacing saved sessions of
+// the same name and keeping the others. A missing file is started anew.
+func Add(path string, sessions ...Session) error {
+	snap, err := Load(path)
+	if os.IsNotExist(err) {
+		snap, err = Snapshot{Version: version}, nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		snap.Sessions = slices.DeleteFunc(snap.Sessions, func(saved Session) bool { return saved.Name == s.Name })
+		snap.Sessions = append(snap.Sessions, s)
+	}
+	snap.SavedAt = time.Now()
+	return Save(snap, path)
+}
+
+// Load reads a snapshot from path.
+func Load(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if snap.Version != version {
+		return Snapshot{}, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	return snap, nil
+}
+
+// Result reports what Restore did with one saved session.
+type Result struct {
+	Session string
+	Status  string // "restored", "skipped" (already running), "failed"
+	Err     error
+}
+
+// Restore recreates every saved session that isn't already running.
+// Programs listed in restoreCommands are restarted in their panes.
+func Restore(snap Snapshot, restoreCommands []string) []Result {
+	results := make([]Result, 0, len(snap.Sessions))
+	for _, s := range snap.Sessions {
+		switch {
+		case len(s.Windows) == 0:
+			continue
+		case tmux.SessionExists(s.Name):
+			results = append(results, Result{Session: s.Name, Status: "skipped"})
+		default:
+			if err := restoreSession(s, restoreCommands); err != nil {
+				results = append(results, Result{Session: s.Name, Status: "failed", Err: err})
+			} else {
+				results = append(results, Result{Session: s.Name, Status: "restored"})
+			}
+		}
+	}
+	return results
+}
+
+// restoreSession creates one session with its windows and panes. A
+// session that fails halfway is killed rather than left half-built.
+func restoreSession(s Session, restoreCommands []string) error {
+	if err := tmux.CreateSession(s.Name, s.Windows[0].Panes[0].Path); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	if err := restoreWindows(s, restoreCommands); err != nil {
+		_ = tmux.KillSession(s.Name)
+		return err
+	}
+	return nil
+}
+
+// restoreWindows recreates a new session's windows in their saved order.
+// Windows go to the next free index rather than their saved one: the
+// saved indexes can collide with the first window when base-index
+// differs from the one the snapshot was taken with.
+func restoreWindows(s Session, restoreCommands []string) error {
+
+	var activeWindow string
+	for i, w := range s.Windows {
+		var windowID string
+		var err error
+		if i == 0 {
+			windowID, err = tmux.DisplayMessage(s.Name+":^", "#{window_id}")
+			if err == nil {
+				err = tmux.RenameWindow(windowID, w.Name)
+			}
+		} else {
+			windowID, err = tmux.NewWindow(s.Name+":", w.Name, w.Panes[0].Path)
+		}
+		if err != nil {
+			return fmt.Errorf("window %q: %w", w.Name, err)
+		}
+		if err := restoreWindow(windowID, w, restoreCommands); err != nil {
+			return fmt.Errorf("window %q: %w", w.Name, err)
+		}
+		if w.Active {
+			activeWindow = windowID
+		}
+	}
+
+	if activeWindow != "" {
+		return tmux.FocusWindow(activeWindow)
+	}
+	return nil
+}
+
+// restoreWindow splits the window into its saved panes, reapplies the
+// saved geometry and restarts restorable programs.
+func restoreWindow(windowID string, w Window, restoreCommands []string) error {
+	firstPane, err := tmux.DisplayMessage(windowID, "#{pane_id}")
+	if err != nil {
+		return err
+	}
+	paneIDs := []string{firstPane}
+	for _, p := range w.Panes[1:] {
+		id, err := tmux.SplitWindow(paneIDs[len(paneIDs)-1], false, "", p.Path)
+		if err != nil {
+			return err
+		}
+		paneIDs = append(paneIDs, id)
+	}
+
+	// The layout string encodes every pane's exact geometry; best effort,
+	// since tmux rejects it if the client is too small
+	if w.Layout != "" {
+		_ = tmux.SelectLayout(windowID, w.Layout)
+	}
+
+	for i, p := range w.Panes {
+		if ShouldRestore(p.Command, restoreCommands) {
+			if err := tmux.SendKeys(paneIDs[i], p.Command, true); err != nil {
+				return err
+			}
+		}
+		if p.Active {
+			_ = tmux.FocusPane(paneIDs[i])
+		}
+	}
+	return nil
+}
+
+// ShouldRestore reports whether a pane's saved command is restarted on
+// restore. Only allow-listed programs are: replaying arbitrary commands
+// (a half-finished deploy, rm) would be dangerous.
+func ShouldRestore(command string, restoreCommands []string) bool {
+	return command != "" && slices.Contains(restoreCommands, command)
+}
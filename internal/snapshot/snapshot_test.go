@@ -0,0 +1,118 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+func TestBuildSession(t *testing.T) {
+	windows := []tmux.Window{
+		{Index: 1, Name: "editor", Layout: "b7e2,200x50,0,0{100x50,0,0,1,99x50,101,0,2}", Active: true},
+		{Index: 2, Name: "gone"}, // no panes listed — dropped
+	}
+	panes := map[int][]tmux.Pane{
+		1: {
+			{Index: 0, Command: "nvim", Path: "/repo", Active: true},
+			{Index: 1, Command: "zsh", Path: "/repo/web"},
+		},
+	}
+
+	got := buildSession("work", windows, panes)
+	if got.Name != "work" || len(got.Windows) != 1 {
+		t.Fatalf("buildSession() = %+v, want one window in session work", got)
+	}
+	w := got.Windows[0]
+	if w.Name != "editor" || !w.Active || w.Layout != windows[0].Layout {
+		t.Errorf("window = %+v, want active editor window with layout", w)
+	}
+	if len(w.Panes) != 2 || w.Panes[0].Command != "nvim" || !w.Panes[0].Active || w.Panes[1].Path != "/repo/web" {
+		t.Errorf("panes = %+v, want nvim (active) in /repo and zsh in /repo/web", w.Panes)
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "snapshot.json")
+	snap := Snapshot{
+		Version: version,
+		SavedAt: time.Unix(1700000000, 0).UTC(),
+		Sessions: []Session{{
+			Name:    "work",
+			Windows: []Window{{Index: 1, Name: "editor", Panes: []Pane{{Path: "/repo", Command: "nvim"}}}},
+		}},
+	}
+
+	if err := Save(snap, path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file left behind after Save()")
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !got.SavedAt.Equal(snap.SavedAt) || len(got.Sessions) != 1 || got.Sessions[0].Windows[0].Panes[0].Command != "nvim" {
+		t.Errorf("Load() = %+v, want %+v", got, snap)
+	}
+}
+
+func TestLoadRejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(`{"version":99,"sessions":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() error = nil, want version error")
+	}
+}
+
+func TestAdd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reaped.json")
+	window := func(name string) []Window {
+		return []Window{{Index: 1, Name: name, Panes: []Pane{{Path: "/repo"}}}}
+	}
+
+	if err := Add(path, Session{Name: "api", Windows: window("old")}, Session{Name: "web", Windows: window("web")}); err != nil {
+		t.Fatalf("Add to a new file: %v", err)
+	}
+	if err := Add(path, Session{Name: "api", Windows: window("new")}); err != nil {
+		t.Fatalf("Add to an existing file: %v", err)
+	}
+
+	snap, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var got []string
+	for _, s := range snap.Sessions {
+		got = append(got, s.Name+"/"+s.Windows[0].Name)
+	}
+	if want := []string{"web/web", "api/new"}; !slices.Equal(got, want) {
+		t.Errorf("sessions = %v, want %v", got, want)
+	}
+}
+
+func TestShouldRestore(t *testing.T) {
+	allow := []string{"nvim", "lazygit"}
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{command: "nvim", want: true},
+		{command: "lazygit", want: true},
+		{command: "zsh", want: false},
+		{command: "rm", want: false},
+		{command: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := ShouldRestore(tt.command, allow); got != tt.want {
+			t.Errorf("ShouldRestore(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Package statusline renders a compact agent and git summary for the tmux
+// status bar (`status-right '#(helm statusline)'`). tmux re-runs status
+// commands every status-interval, so the collected data is cached in a
+// JSON file and only refreshed once it is older than MaxAge.
+package statusline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+// MaxAge is how long cached data is reused before collecting again.
+const MaxAge = 5 * time.Second
+
+// Summary is the cached state of all sessions.
+type Summary struct {
+	UpdatedAt time.Time      `json:"updated_at"`
+	Agents    []Agent        `json:"agents"`
+	Git       map[string]Git `json:"git"` // Sessions in a git repo, by name
+}
+
+// Agent is one live agent instance.
+type Agent struct {
+	Session string    `json:"session"`
+	State   string    `json:"state"`
+	Since   time.Time `json:"since"`
+}
+
+// Git is the working tree state of a session's directory.
+type Git struct {
+	Branch    string `json:"branch,omitempty"` // "" on a detached HEAD
+	Dirty     int    `json:"dirty"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// Path returns the cache file in the cache dir.
+func Path(cacheDir string) string {
+	return filepath.Join(cacheDir, config.StatuslineFileName)
+}
+
+// Load reads the cached summary. ok is false if there is none or it is
+// older than maxAge.
+func Load(path string, maxAge time.Duration, now time.Time) (s Summary, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Summary{}, false
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Summary{}, false
+	}
+	return s, now.Sub(s.UpdatedAt) <= maxAge
+}
+
+// Save writes the summary to path atomically. Several tmux clients may
+// refresh at once, so each writer uses its own temp file.
+func Save(s Summary, path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal statusline cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), config.StatuslineFileName+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write statusline cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write statusline cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write statusline cache: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Render formats the summary for session as tmux style codes, e.g.
+// "⠤2 ?1 !1 3 dirty main 4 files +12 -3": working, waiting and
+// long-waiting agents, the number of dirty sessions, and the current
+// session's branch and changes, or ✓ if it is clean. Empty parts are left
+// out. Colors come from ui.Colors, so call
+// ui.InitColors first.
+func Render(s Summary, session string, now time.Time) string {
+	var working, waiting, urgent int
+	for _, a := range s.Agents {
+		switch ui.StatusIconChar(a.State, 0, now.Sub(a.Since)) {
+		case "?":
+			waiting++
+		case "!":
+			urgent++
+		case " ", "Z":
+			// new or idle: nothing to look at
+		default:
+			working++
+		}
+	}
+
+	fg := ui.Colors.Fg
+	var parts []string
+	if working > 0 {
+		parts = append(parts, segment(fg.ClaudeWorking, fmt.Sprintf("%s%d", ui.ClaudeSpinnerFrames[0], working)))
+	}
+	if waiting > 0 {
+		parts = append(parts, segment(fg.ClaudeWaiting, fmt.Sprintf("?%d", waiting)))
+	}
+	if urgent > 0 {
+		parts = append(parts, segment(fg.ClaudeUrgent, fmt.Sprintf("!%d", urgent)))
+	}
+	dirty := 0
+	for _, g := range s.Git {
+		if g.Dirty > 0 {
+			dirty++
+		}
+	}
+	if dirty > 0 {
+		parts = append(parts, segment(fg.Muted, fmt.Sprintf("%d dirty", dirty)))
+	}
+	if g, ok := s.Git[session]; ok {
+		if g.Branch != "" {
+			parts = append(parts, segment(fg.Subtle, g.Branch))
+		}
+		if g.Dirty == 0 {
+			parts = append(parts, segment(fg.GitAdd, "✓"))
+		}
+		if g.Dirty > 0 {
+			label := "files"
+			if g.Dirty == 1 {
+				label = "file"
+			}
+			parts = append(parts, segment(fg.GitFiles, fmt.Sprintf("%d %s", g.Dirty, label)))
+		}
+		if g.Additions > 0 {
+			parts = append(parts, segment(fg.GitAdd, fmt.Sprintf("+%d", g.Additions)))
+		}
+		if g.Deletions > 0 {
+			parts = append(parts, segment(fg.GitDel, fmt.Sprintf("-%d", g.Deletions)))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// segment wraps text in a tmux foreground style, restoring the status
+// bar's own color afterwards.
+func segment(c lipgloss.TerminalColor, text string) string {
+	return "#[fg=" + tmuxColor(c) + "]" + text + "#[fg=default]"
+}
+
+// tmuxColor converts a lipgloss color to tmux syntax: ANSI indexes become
+// colourN, hex colors pass through, anything else is the default color.
+func tmuxColor(c lipgloss.TerminalColor) string {
+	color, ok := c.(lipgloss.Color)
+	if !ok || color == "" {
+		return "default"
+	}
+	s := string(color)
+	if strings.HasPrefix(s, "#") {
+		return s
+	}
+	return "colour" + s
+}
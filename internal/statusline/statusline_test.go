@@ -0,0 +1,128 @@
+package statusline
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/black-atom-industries/helm/internal/ui"
+)
+
+func TestRender(t *testing.T) {
+	ui.InitColors("dark", "")
+	now := time.Now()
+	s := Summary{
+		Agents: []Agent{
+			{Session: "api", State: "working", Since: now},
+			{Session: "web", State: "working", Since: now},
+			{Session: "web", State: "waiting", Since: now.Add(-time.Minute)},
+			{Session: "docs", State: "waiting", Since: now.Add(-10 * time.Minute)},
+			{Session: "old", State: "waiting", Since: now.Add(-time.Hour)}, // idle
+			{Session: "new", State: "new", Since: now},
+		},
+		Git: map[string]Git{
+			"api":  {Branch: "main", Dirty: 1, Additions: 4},
+			"web":  {Branch: "feat/login", Dirty: 3, Additions: 12, Deletions: 3},
+			"docs": {Branch: "main"},
+			"cli":  {}, // detached HEAD
+		},
+	}
+
+	tests := []struct {
+		name    string
+		summary Summary
+		session string
+		want    string
+	}{
+		{
+			name:    "current session dirty",
+			summary: s,
+			session: "web",
+			want: "#[fg=colour3]⠤2#[fg=default] #[fg=colour2]?1#[fg=default] #[fg=colour1]!1#[fg=default] " +
+				"#[fg=colour8]2 dirty#[fg=default] #[fg=colour7]feat/login#[fg=default] " +
+				"#[fg=#5cb2fb]3 files#[fg=default] #[fg=#89be61]+12#[fg=default] #[fg=#f4868c]-3#[fg=default]",
+		},
+		{
+			name:    "current session clean",
+			summary: Summary{Git: s.Git},
+			session: "docs",
+			want:    "#[fg=colour8]2 dirty#[fg=default] #[fg=colour7]main#[fg=default] #[fg=#89be61]✓#[fg=default]",
+		},
+		{
+			name:    "current session detached",
+			summary: Summary{Git: s.Git},
+			session: "cli",
+			want:    "#[fg=colour8]2 dirty#[fg=default] #[fg=#89be61]✓#[fg=default]",
+		},
+		{
+			name:    "current session not a repo",
+			summary: Summary{Git: s.Git},
+			session: "scratch",
+			want:    "#[fg=colour8]2 dirty#[fg=default]",
+		},
+		{
+			name:    "singular file",
+			summary: Summary{Git: map[string]Git{"api": {Dirty: 1}}},
+			session: "api",
+			want:    "#[fg=colour8]1 dirty#[fg=default] #[fg=#5cb2fb]1 file#[fg=default]",
+		},
+		{
+			name:    "nothing to show",
+			summary: Summary{},
+			session: "api",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.summary, tt.session, now); got != tt.want {
+				t.Errorf("Render() =\n  %q\nwant\n  %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTmuxColor(t *testing.T) {
+	tests := []struct {
+		in   lipgloss.TerminalColor
+		want string
+	}{
+		{lipgloss.Color("3"), "colour3"},
+		{lipgloss.Color("#f38b6a"), "#f38b6a"},
+		{lipgloss.NoColor{}, "default"},
+	}
+	for _, tt := range tests {
+		if got := tmuxColor(tt.in); got != tt.want {
+			t.Errorf("tmuxColor(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "statusline.json")
+	now := time.Now()
+
+	if _, ok := Load(path, MaxAge, now); ok {
+		t.Fatal("Load() of missing file reported ok")
+	}
+
+	want := Summary{UpdatedAt: now, Git: map[string]Git{"api": {Dirty: 2}}}
+	if err := Save(want, path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, ok := Load(path, MaxAge, now.Add(time.Second))
+	if !ok {
+		t.Fatal("Load() of fresh cache reported stale")
+	}
+	if got.Git["api"].Dirty != 2 {
+		t.Errorf("Load() = %+v, want api dirty 2", got)
+	}
+
+	if _, ok := Load(path, MaxAge, now.Add(MaxAge+time.Second)); ok {
+		t.Error("Load() of expired cache reported fresh")
+	}
+}
@@ -14,14 +14,22 @@ import (
 type Session struct {
 	Name         string
 	LastActivity time.Time
+	Path         string // Start directory (session_path)
+	Group        string // Manual group tag (the GroupOption user option)
+	Attached     int    // Number of attached clients
 	Windows      []Window
 	Expanded     bool
 }
 
+// GroupOption is the session user option holding a session's group tag
+const GroupOption = "@helm-group"
+
 // Window represents a tmux window
 type Window struct {
 	Index    int
 	Name     string
+	Layout   string // tmux layout string (pane geometry), restorable via select-layout
+	Active   bool   // Current window of the session
 	Panes    []Pane
 	Expanded bool
 }
@@ -31,6 +39,7 @@ type Pane struct {
 	Index   int
 	PID     int    // Pane shell process PID (for agent attribution)
 	Command string // Current command running in the pane
+	Path    string // Current working directory of the pane
 	Active  bool   // Active pane in the window
 }
 
@@ -43,6 +52,15 @@ func CurrentSession() (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// CurrentPane returns the current pane as a session:window.pane target
+func CurrentPane() (string, error) {
+	out, err := exec.Command("tmux", "display-message", "-p", "#{session_name}:#{window_index}.#{pane_index}").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // GetSessionActivity returns the last activity time for a named session
 func GetSessionActivity(name string) (time.Time, error) {
 	out, err := exec.Command("tmux", "display-message", "-t", name, "-p", "#{session_activity}").Output()
@@ -59,7 +77,9 @@ func GetSessionActivity(name string) (time.Time, error) {
 // ListSessions returns all tmux sessions sorted by activity (most recent first)
 // Excludes the current session and popup sessions
 func ListSessions(excludeCurrent string) ([]Session, error) {
-	out, err := exec.Command("tmux", "list-sessions", "-F", "#{session_activity} #{session_name}").Output()
+	// Name is the last field — it may contain the separator itself
+	format := "#{session_activity}\t#{session_attached}\t#{session_path}\t#{" + GroupOption + "}\t#{session_name}"
+	out, err := exec.Command("tmux", "list-sessions", "-F", format).Output()
 	if err != nil {
 		return nil, err
 	}
@@ -72,12 +92,12 @@ func ListSessions(excludeCurrent string) ([]Session, error) {
 	var sessions []Session
 
 	for _, line := range lines {
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) != 2 {
+		parts := strings.SplitN(line, "\t", 5)
+		if len(parts) != 5 {
 			continue
 		}
 
-		name := parts[1]
+		name := parts[4]
 
 		// Skip current session and popup sessions
 		if name == excludeCurrent || strings.HasPrefix(name, "_popup_") {
@@ -89,9 +109,14 @@ func ListSessions(excludeCurrent string) ([]Session, error) {
 			continue
 		}
 
+		attached, _ := strconv.Atoi(parts[1])
+
 		sessions = append(sessions, Session{
 			Name:         name,
 			LastActivity: time.Unix(activityUnix, 0),
+			Path:         parts[2],
+			Group:        parts[3],
+			Attached:     attached,
 		})
 	}
 
@@ -105,7 +130,8 @@ func ListSessions(excludeCurrent string) ([]Session, error) {
 
 // ListWindows returns all windows for a given session
 func ListWindows(sessionName string) ([]Window, error) {
-	out, err := exec.Command("tmux", "list-windows", "-t", sessionName, "-F", "#{window_index}:#{window_name}").Output()
+	// Name is the last field — it may contain the separator itself
+	out, err := exec.Command("tmux", "list-windows", "-t", sessionName, "-F", "#{window_index}\t#{window_active}\t#{window_layout}\t#{window_name}").Output()
 	if err != nil {
 		return nil, err
 	}
@@ -117,30 +143,51 @@ func ListWindows(sessionName string) ([]Window, error) {
 
 	var windows []Window
 	for _, line := range lines {
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) != 2 {
+		parts := strings.SplitN(line, "\t", 4)
+		if len(parts) != 4 {
 			continue
 		}
-
 		index, err := strconv.Atoi(parts[0])
 		if err != nil {
 			continue
 		}
-
 		windows = append(windows, Window{
-			Index: index,
-			Name:  parts[1],
+			Index:  index,
+			Name:   parts[3],
+			Layout: parts[2],
+			Active: parts[1] == "1",
 		})
 	}
 
 	return windows, nil
 }
 
+// SetSessionGroup tags a session with a group; an empty group removes the tag
+func SetSessionGroup(name, group string) error {
+	target := "=" + name + ":" // exact match; set-option wants a full target
+	if group == "" {
+		return exec.Command("tmux", "set-option", "-u", "-t", target, GroupOption).Run()
+	}
+	return exec.Command("tmux", "set-option", "-t", target, GroupOption, group).Run()
+}
+
 // KillSession kills a tmux session by name
 func KillSession(name string) error {
 	return exec.Command("tmux", "kill-session", "-t", name).Run()
 }
 
+// DetachClients detaches every client attached to a session. The "="
+// prefix makes tmux match the name exactly instead of by prefix.
+func DetachClients(name string) error {
+	return exec.Command("tmux", "detach-client", "-s", "="+name).Run()
+}
+
+// RenameSession renames a tmux session. The "=" prefix makes tmux match
+// the old name exactly instead of by prefix.
+func RenameSession(oldName, newName string) error {
+	return exec.Command("tmux", "rename-session", "-t", "="+oldName, newName).Run()
+}
+
 // KillWindow kills a tmux window
 func KillWindow(sessionName string, windowIndex int) error {
 	target := fmt.Sprintf("%s:%d", sessionName, windowIndex)
@@ -239,11 +286,35 @@ func SelectWindow(sessionName string, windowIndex int) error {
 	return exec.Command("tmux", "switch-client", "-t", target).Run()
 }
 
+// paneFormat is the list-panes format shared by ListPanes and
+// ListSessionPanes. Command is the last field — it may contain the
+// separator itself.
+const paneFormat = "#{pane_index}\t#{pane_pid}\t#{pane_active}\t#{pane_current_path}\t#{pane_current_command}"
+
+// parsePane parses one line of paneFormat output.
+func parsePane(line string) (Pane, bool) {
+	parts := strings.SplitN(line, "\t", 5)
+	if len(parts) != 5 {
+		return Pane{}, false
+	}
+	index, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Pane{}, false
+	}
+	pid, _ := strconv.Atoi(parts[1])
+	return Pane{
+		Index:   index,
+		PID:     pid,
+		Command: parts[4],
+		Path:    parts[3],
+		Active:  parts[2] == "1",
+	}, true
+}
+
 // ListPanes returns all panes for a given session and window
 func ListPanes(sessionName string, windowIndex int) ([]Pane, error) {
 	target := fmt.Sprintf("%s:%d", sessionName, windowIndex)
-	// Command is the last field — it may contain the separator itself
-	out, err := exec.Command("tmux", "list-panes", "-t", target, "-F", "#{pane_index}:#{pane_pid}:#{pane_active}:#{pane_current_command}").Output()
+	out, err := exec.Command("tmux", "list-panes", "-t", target, "-F", paneFormat).Output()
 	if err != nil {
 		return nil, err
 	}
@@ -255,23 +326,9 @@ func ListPanes(sessionName string, windowIndex int) ([]Pane, error) {
 
 	var panes []Pane
 	for _, line := range lines {
-		parts := strings.SplitN(line, ":", 4)
-		if len(parts) != 4 {
-			continue
+		if pane, ok := parsePane(line); ok {
+			panes = append(panes, pane)
 		}
-
-		index, err := strconv.Atoi(parts[0])
-		if err != nil {
-			continue
-		}
-		pid, _ := strconv.Atoi(parts[1])
-
-		panes = append(panes, Pane{
-			Index:   index,
-			PID:     pid,
-			Command: parts[3],
-			Active:  parts[2] == "1",
-		})
 	}
 
 	return panes, nil
@@ -281,30 +338,24 @@ func ListPanes(sessionName string, windowIndex int) ([]Pane, error) {
 // one tmux call, so expanded sessions can attribute agents to collapsed
 // windows without per-window pane fetches.
 func ListSessionPanes(sessionName string) (map[int][]Pane, error) {
-	out, err := exec.Command("tmux", "list-panes", "-s", "-t", sessionName, "-F", "#{window_index}:#{pane_index}:#{pane_pid}:#{pane_active}:#{pane_current_command}").Output()
+	out, err := exec.Command("tmux", "list-panes", "-s", "-t", sessionName, "-F", "#{window_index}\t"+paneFormat).Output()
 	if err != nil {
 		return nil, err
 	}
 
 	panes := make(map[int][]Pane)
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		parts := strings.SplitN(line, ":", 5)
-		if len(parts) != 5 {
+		windowPart, paneLine, ok := strings.Cut(line, "\t")
+		if !ok {
 			continue
 		}
-		windowIndex, err1 := strconv.Atoi(parts[0])
-		paneIndex, err2 := strconv.Atoi(parts[1])
-		if err1 != nil || err2 != nil {
+		windowIndex, err := strconv.Atoi(windowPart)
+		if err != nil {
 			continue
 		}
-		pid, _ := strconv.Atoi(parts[2])
-
-		panes[windowIndex] = append(panes[windowIndex], Pane{
-			Index:   paneIndex,
-			PID:     pid,
-			Command: parts[4],
-			Active:  parts[3] == "1",
-		})
+		if pane, ok := parsePane(paneLine); ok {
+			panes[windowIndex] = append(panes[windowIndex], pane)
+		}
 	}
 	return panes, nil
 }
@@ -343,3 +394,124 @@ func SelectPane(sessionName string, windowIndex, paneIndex int) error {
 	target := fmt.Sprintf("%s:%d.%d", sessionName, windowIndex, paneIndex)
 	return exec.Command("tmux", "switch-client", "-t", target).Run()
 }
+
+// DisplayMessage expands a tmux format string against a target
+// (session, window or pane) and returns the result.
+func DisplayMessage(target, format string) (string, error) {
+	out, err := exec.Command("tmux", "display-message", "-p", "-t", target, format).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ShowMessage shows text in the status line of the attached client(s).
+func ShowMessage(text string) error {
+	// Escape # so tmux doesn't expand the text as a format
+	return exec.Command("tmux", "display-message", strings.ReplaceAll(text, "#", "##")).Run()
+}
+
+// NewWindow creates a detached window and returns its window ID (e.g.
+// "@12"), which stays valid across index renumbering. target is
+// "session:" to append at the next free index, or "session:N" for index N.
+func NewWindow(target, name, dir string) (string, error) {
+	args := []string{"new-window", "-d", "-t", target, "-P", "-F", "#{window_id}"}
+	if name != "" {
+		args = append(args, "-n", name)
+	}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	out, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// RenameWindow renames the target window
+func RenameWindow(target, name string) error {
+	return exec.Command("tmux", "rename-window", "-t", target, name).Run()
+}
+
+// SplitWindow splits the target pane and returns the new pane's ID
+// (e.g. "%31"). horizontal places the new pane to the right instead of
+// below. size is passed to -l as-is ("30%" or a cell count); empty lets
+// tmux halve the pane.
+func SplitWindow(target string, horizontal bool, size, dir string) (string, error) {
+	args := []string{"split-window", "-d", "-t", target, "-P", "-F", "#{pane_id}"}
+	if horizontal {
+		args = append(args, "-h")
+	} else {
+		args = append(args, "-v")
+	}
+	if size != "" {
+		args = append(args, "-l", size)
+	}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	out, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SendKeys types text literally into the target pane, followed by Enter
+// when enter is true. Literal mode keeps tmux from interpreting words like
+// "Enter" or "C-c" inside the text as key names.
+func SendKeys(target, text string, enter bool) error {
+	if text != "" {
+		if err := exec.Command("tmux", "send-keys", "-t", target, "-l", "--", text).Run(); err != nil {
+			return err
+		}
+	}
+	if enter {
+		return exec.Command("tmux", "send-keys", "-t", target, "Enter").Run()
+	}
+	return nil
+}
+
+// CapturePane returns the last lines of the target pane's visible content
+// and scrollback, with wrapped lines joined and trailing blank lines
+// dropped.
+func CapturePane(target string, lines int) ([]string, error) {
+	out, err := exec.Command("tmux", "capture-pane", "-p", "-J", "-t", target, "-S", fmt.Sprintf("-%d", lines)).Output()
+	if err != nil {
+		return nil, err
+	}
+	captured := strings.Split(strings.TrimRight(string(out), "\n "), "\n")
+	if len(captured) > lines {
+		captured = captured[len(captured)-lines:]
+	}
+	return captured, nil
+}
+
+// CapturePaneANSI returns the visible content of the target pane with its
+// colors and attributes as escape sequences, for previews.
+func CapturePaneANSI(target string) ([]string, error) {
+	out, err := exec.Command("tmux", "capture-pane", "-p", "-e", "-t", target).Output()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimRight(string(out), "\n"), "\n"), nil
+}
+
+// SelectLayout applies a tmux layout (e.g. "tiled", "main-vertical") to
+// the target window.
+func SelectLayout(target, layout string) error {
+	return exec.Command("tmux", "select-layout", "-t", target, layout).Run()
+}
+
+// FocusWindow makes the target window current within its session without
+// touching any client.
+func FocusWindow(target string) error {
+	return exec.Command("tmux", "select-window", "-t", target).Run()
+}
+
+// FocusPane makes the target pane active within its window without
+// touching any client.
+func FocusPane(target string) error {
+	return exec.Command("tmux", "select-pane", "-t", target).Run()
+}
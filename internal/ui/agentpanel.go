@@ -27,15 +27,25 @@ const (
 	MinAgentPanelHeight = 15
 )
 
+// Transcript preview limits, in wrapped lines.
+const (
+	previewPromptLines = 3
+	previewReplyLines  = 6
+)
+
 // AgentEntry is one live agent instance shown in the panel.
 type AgentEntry struct {
 	Kind   string // "claude", "pi"
 	Status agent.Status
+	// Transcript is the parsed session transcript of the selected instance,
+	// nil for the others or when the agent doesn't report one.
+	Transcript *agent.Transcript
 }
 
 // RenderAgentPanel renders the right-hand AGENTS panel for the selected
 // session: one block per live agent instance (state dot, kind, elapsed,
-// tool) plus a shared cwd line. width is the panel's content width (it
+// tool), a transcript preview for the entry that carries one, plus a shared
+// cwd line. width is the panel's content width (it
 // grows with whatever the list doesn't need). The block is padded to height
 // lines, each carrying the left rule separator.
 func RenderAgentPanel(entries []AgentEntry, cwd string, width, height int) string {
@@ -62,6 +72,9 @@ func RenderAgentPanel(entries []AgentEntry, cwd string, width, height int) strin
 			lines = append(lines, "  "+HelpDescStyle.Render(label)+" "+
 				HelpKeyStyle.Render(truncateTo(e.Status.Tool, width-8)))
 		}
+		if e.Transcript != nil {
+			lines = append(lines, renderTranscriptPreview(*e.Transcript, width-2)...)
+		}
 		lines = append(lines, "")
 	}
 
@@ -69,10 +82,14 @@ func RenderAgentPanel(entries []AgentEntry, cwd string, width, height int) strin
 		lines = append(lines, HelpDescStyle.Render(truncateTo("cwd "+tildePath(cwd), width)))
 	}
 
-	// Pad to the list height so the rule runs the full column
+	// Pad to the list height so the rule runs the full column; a long
+	// preview must not push the panel past it either
 	for len(lines) < height {
 		lines = append(lines, "")
 	}
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
 
 	rule := BorderStyle.Render("│")
 	var b strings.Builder
@@ -87,6 +104,61 @@ func RenderAgentPanel(entries []AgentEntry, cwd string, width, height int) strin
 	return b.String()
 }
 
+// renderTranscriptPreview renders the last prompt, an excerpt of the last
+// reply and the turn/token counts, indented under the entry's header line.
+func renderTranscriptPreview(t agent.Transcript, width int) []string {
+	var lines []string
+	section := func(label, text string, maxLines int, style lipgloss.Style) {
+		if text == "" {
+			return
+		}
+		lines = append(lines, "  "+HelpDescStyle.Render(label))
+		for _, l := range wrapExcerpt(text, width-2, maxLines) {
+			lines = append(lines, "    "+style.Render(l))
+		}
+	}
+	section("prompt", t.LastPrompt, previewPromptLines, HelpKeyStyle)
+	section("reply", t.LastReply, previewReplyLines, lipgloss.NewStyle())
+
+	if t.Turns > 0 || t.Output > 0 {
+		stats := fmt.Sprintf("%d turns · %s ctx · %s out", t.Turns, compactTokens(t.Context), compactTokens(t.Output))
+		if t.Turns == 1 {
+			stats = strings.Replace(stats, "turns", "turn", 1)
+		}
+		lines = append(lines, "  "+HelpDescStyle.Render(truncateTo(stats, width)))
+	}
+	return lines
+}
+
+// wrapExcerpt flattens text to a single paragraph and word-wraps it to
+// width, keeping at most maxLines lines; cut text ends in an ellipsis.
+func wrapExcerpt(text string, width, maxLines int) []string {
+	if width < 1 {
+		width = 1
+	}
+	lines := strings.Split(ansi.Wrap(strings.Join(strings.Fields(text), " "), width, ""), "\n")
+	if len(lines) <= maxLines {
+		return lines
+	}
+	last := []rune(strings.TrimRight(lines[maxLines-1], " "))
+	if len(last) >= width {
+		last = last[:width-1]
+	}
+	return append(lines[:maxLines-1], string(last)+"…")
+}
+
+// compactTokens renders a token count as "850 / 12k / 1.2M".
+func compactTokens(n int) string {
+	switch {
+	case n < 1000:
+		return fmt.Sprintf("%d", n)
+	case n < 1_000_000:
+		return fmt.Sprintf("%dk", n/1000)
+	default:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	}
+}
+
 // agentStateStyle maps an agent state to its display style.
 func agentStateStyle(state string) lipgloss.Style {
 	switch state {
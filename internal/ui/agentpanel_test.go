@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWrapExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		maxLines int
+		want     []string
+	}{
+		{
+			name:     "fits",
+			text:     "all  green\nnow",
+			width:    20,
+			maxLines: 2,
+			want:     []string{"all green now"},
+		},
+		{
+			name:     "wraps at words",
+			text:     "the quick brown fox jumps",
+			width:    10,
+			maxLines: 3,
+			want:     []string{"the quick", "brown fox", "jumps"},
+		},
+		{
+			name:     "cut with ellipsis",
+			text:     "the quick brown fox jumps over the lazy dog",
+			width:    10,
+			maxLines: 2,
+			want:     []string{"the quick", "brown fox…"},
+		},
+		{
+			name:     "cut full line",
+			text:     "aaaaaaaaaa bbbbbbbbbb cc",
+			width:    10,
+			maxLines: 2,
+			want:     []string{"aaaaaaaaaa", "bbbbbbbbb…"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapExcerpt(tt.text, tt.width, tt.maxLines); !slices.Equal(got, tt.want) {
+				t.Errorf("wrapExcerpt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
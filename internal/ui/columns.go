@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/black-atom-industries/helm/internal/agent"
 	"github.com/black-atom-industries/helm/internal/git"
@@ -15,6 +16,9 @@ import (
 type RowLayout struct {
 	NameWidth      int
 	GitStatusWidth int
+	BranchWidth    int          // 0 hides the branch column
+	GitSyncWidth   int          // 0 hides the ahead/behind column
+	AgentKinds     []agent.Kind // One status column per kind, in order
 }
 
 // RowOpts contains options for rendering a generic row
@@ -27,20 +31,23 @@ type RowOpts struct {
 	Selected bool
 
 	// Optional - set to enable
-	ShowExpandIcon   bool          // Show ▸/▾ expand indicator
-	Expanded         bool          // Expansion state
-	LastActivity     *time.Time    // Show time ago if set
-	GitStatus        *git.Status   // Show git status if set
-	GitStatusLoading bool          // Show loading indicator for git status
-	ClaudeStatus     *agent.Status // Show claude status if set
-	PiStatus         *agent.Status // Show pi status if set
-	AnimFrame        int           // Animation frame for status icons
-	IsSelf           bool          // True for the pinned current/self session
+	ShowExpandIcon   bool                     // Show ▸/▾ expand indicator
+	Expanded         bool                     // Expansion state
+	LastActivity     *time.Time               // Show time ago if set
+	GitStatus        *git.Status              // Show git status if set
+	GitStatusLoading bool                     // Show loading indicator for git status
+	AgentStatuses    map[string]*agent.Status // Most-active status per agent kind name
+	AnimFrame        int                      // Animation frame for status icons
+	IsSelf           bool                     // True for the pinned current/self session
+	Pinned           bool                     // Pinned to a fixed slot (C-f)
+	Matches          []int                    // Name runes matching the filter, highlighted
+	Marked           bool                     // Marked for a bulk action (Tab)
 }
 
 // WindowRowOpts contains per-row options for rendering a window
 type WindowRowOpts struct {
 	Selected   bool
+	Marked     bool     // Marked for a bulk action (Tab)
 	Expanded   bool     // Window is expanded to show panes
 	AgentKinds []string // Agents running in the window's panes (a window can host several)
 }
@@ -70,6 +77,25 @@ func RenderIndex(num int, selected bool) string {
 	return IndexStyle.Render(label)
 }
 
+// RenderPinnedIndex renders the index of a pinned session, marked so the
+// fixed slots stand out from the ones that follow the sort order
+func RenderPinnedIndex(num int, selected bool) string {
+	label := fmt.Sprintf("%d•", num)
+	if selected {
+		return PinnedIndexSelectedStyle.Render(label)
+	}
+	return PinnedIndexStyle.Render(label)
+}
+
+// RenderMarkedIndex renders the index column of a row marked for a bulk
+// action; the mark takes the number's place
+func RenderMarkedIndex(selected bool) string {
+	if selected {
+		return MarkSelectedStyle.Width(3).Render(MarkIcon)
+	}
+	return MarkStyle.Width(3).Render(MarkIcon)
+}
+
 // RenderExpandIcon renders the expand/collapse indicator
 func RenderExpandIcon(expanded, selected bool) string {
 	if expanded {
@@ -84,18 +110,47 @@ func RenderExpandIcon(expanded, selected bool) string {
 	return CollapsedIcon
 }
 
-// RenderName renders a name with padding to a fixed width
-func RenderName(name string, width int, selected bool, selectedStyle, defaultStyle lipgloss.Style) string {
+// RenderName renders a name with padding to a fixed width. The runes at
+// matches (fuzzy match positions) are highlighted.
+func RenderName(name string, width int, selected bool, matches []int, selectedStyle, defaultStyle lipgloss.Style) string {
 	padded := fmt.Sprintf("%-*s", width, name)
 	if selected {
-		return selectedStyle.Render(padded)
+		return RenderMatches(padded, matches, selectedStyle, MatchSelectedStyle.Inherit(selectedStyle))
+	}
+	return RenderMatches(padded, matches, defaultStyle, MatchStyle.Inherit(defaultStyle))
+}
+
+// RenderMatches renders text in style, with the runes at positions in
+// match. Runs of matched and unmatched runes are rendered in one piece.
+func RenderMatches(text string, positions []int, style, match lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(text)
+	}
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+
+	var b strings.Builder
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && matched[end] == matched[start] {
+			end++
+		}
+		if matched[start] {
+			b.WriteString(match.Render(string(runes[start:end])))
+		} else {
+			b.WriteString(style.Render(string(runes[start:end])))
+		}
+		start = end
 	}
-	return defaultStyle.Render(padded)
+	return b.String()
 }
 
-// RenderSessionName renders the session name
-func RenderSessionName(name string, width int, selected bool) string {
-	return RenderName(name, width, selected, SessionNameSelectedStyle, SessionNameStyle)
+// RenderSessionName renders the session name, highlighting filter matches
+func RenderSessionName(name string, width int, selected bool, matches []int) string {
+	return RenderName(name, width, selected, matches, SessionNameSelectedStyle, SessionNameStyle)
 }
 
 // RenderWindowName renders the window name with index
@@ -107,10 +162,13 @@ func RenderWindowName(index int, name string, selected bool) string {
 	return WindowNameStyle.Render(text)
 }
 
+// timeAgoWidth is the width RenderTimeAgo pads to
+const timeAgoWidth = 8
+
 // RenderTimeAgo renders the time since last activity
 func RenderTimeAgo(t time.Time, selected bool) string {
 	timeAgo := FormatTimeAgo(t)
-	padded := fmt.Sprintf("%-8s", timeAgo)
+	padded := fmt.Sprintf("%-*s", timeAgoWidth, timeAgo)
 	if selected {
 		return TimeSelectedStyle.Render(padded)
 	}
@@ -164,30 +222,77 @@ func RenderGitStatusColumn(status *git.Status, maxWidth int, selected bool, load
 	return formatted
 }
 
-// RenderClaudeIcon renders a single-character Claude status icon
-// Returns a space for no status to preserve column alignment
-func RenderClaudeIcon(status *agent.Status, animFrame int, selected bool) string {
-	if status == nil || status.State == "" || status.IsStale() {
-		return SpacerStyle(" ", selected) // Reserved space for alignment
+// RenderBranchColumn renders the branch name, truncated with "…" to fit a
+// fixed width
+func RenderBranchColumn(status *git.Status, width int, selected bool) string {
+	name := ""
+	if status != nil {
+		name = TruncateBranch(status.Branch, width)
 	}
-	waitDuration := time.Since(status.Timestamp)
+	padded := name + strings.Repeat(" ", width-ansi.StringWidth(name))
 	if selected {
-		return FormatClaudeIcon(status.State, animFrame, waitDuration, true)
+		return selectedBase(GitBranchStyle).Render(padded)
+	}
+	return GitBranchStyle.Render(padded)
+}
+
+// TruncateBranch shortens a branch name to width cells. The end is cut
+// rather than the start: "feat/login-…" still says what the branch is for.
+func TruncateBranch(name string, width int) string {
+	if ansi.StringWidth(name) <= width {
+		return name
 	}
-	return FormatClaudeIcon(status.State, animFrame, waitDuration, false)
+	return ansi.Truncate(name, width, "…")
 }
 
-// RenderPiIcon renders a single-character Pi status icon
+// RenderGitSyncColumn renders ahead/behind arrows with padding to a fixed width
+func RenderGitSyncColumn(status *git.Status, width int, selected bool) string {
+	if status == nil || status.Upstream == "" {
+		return SpacerStyle(strings.Repeat(" ", width), selected)
+	}
+	formatted := FormatGitSync(status.Ahead, status.Behind, selected)
+	if actual := GitSyncWidth(status.Ahead, status.Behind); actual < width {
+		return formatted + SpacerStyle(strings.Repeat(" ", width-actual), selected)
+	}
+	return formatted
+}
+
+// RenderAgentIcon renders a single-character status icon for an agent kind
 // Returns a space for no status to preserve column alignment
-func RenderPiIcon(status *agent.Status, animFrame int, selected bool) string {
+func RenderAgentIcon(kind agent.Kind, status *agent.Status, animFrame int, selected bool) string {
 	if status == nil || status.State == "" || status.IsStale() {
 		return SpacerStyle(" ", selected) // Reserved space for alignment
 	}
-	waitDuration := time.Since(status.Timestamp)
-	if selected {
-		return FormatPiIcon(status.State, animFrame, waitDuration, true)
+	return FormatAgentIcon(kind, status.State, animFrame, time.Since(status.Timestamp), selected)
+}
+
+// AgentHeaderStyle returns the status column header style of an agent
+// kind: the built-in CC/Pi styles, or the kind's configured color.
+func AgentHeaderStyle(kind agent.Kind) lipgloss.Style {
+	switch {
+	case kind.Color != "":
+		return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(kind.Color))
+	case kind.Name == "pi":
+		return PiHeaderStyle
+	default:
+		return CCHeaderStyle
+	}
+}
+
+// renderAgentColumns renders one status icon per agent column. Spacers
+// between icons are 2 chars wide to align under the 2-char headers.
+func renderAgentColumns(layout RowLayout, opts RowOpts) []string {
+	var cols []string
+	for i, kind := range layout.AgentKinds {
+		if i > 0 {
+			cols = append(cols, SpacerStyle("  ", opts.Selected))
+		}
+		cols = append(cols, RenderAgentIcon(kind, opts.AgentStatuses[kind.Name], opts.AnimFrame, opts.Selected))
 	}
-	return FormatPiIcon(status.State, animFrame, waitDuration, false)
+	if len(cols) > 0 {
+		cols = append(cols, SpacerStyle(" ", opts.Selected))
+	}
+	return cols
 }
 
 // SessionRowOpts wraps RowOpts with session-specific settings
@@ -212,11 +317,34 @@ func RenderSelfSessionName(name string, width int, selected bool) string {
 	return SelfNameStyle.Render(padded)
 }
 
+// FixedWidth returns the width of a session row, as the session list
+// draws it (with expand icon and time), without the branch and
+// ahead/behind columns: what's left of the row is theirs to share.
+func (l RowLayout) FixedWidth() int {
+	width := SessionStyle.GetHorizontalPadding()
+	width += IndexStyle.GetWidth() + 1
+	if n := len(l.AgentKinds); n > 0 {
+		width += n + 2*(n-1) + 1 // 1-cell icons, 2-cell gaps, spacer
+	}
+	width += 2 + l.NameWidth // Expand icon and spacer
+	width += 2 + timeAgoWidth
+	if l.GitStatusWidth > 0 {
+		width += 1 + l.GitStatusWidth
+	}
+	return width
+}
+
 // RenderSessionRow composes all columns into a complete session row
 func RenderSessionRow(name string, lastActivity time.Time, layout RowLayout, opts SessionRowOpts, width int) string {
 	// Self session uses distinct index marker and name style
 	renderIndex := RenderIndex(opts.Num, opts.Selected)
-	renderName := RenderSessionName(name, layout.NameWidth, opts.Selected)
+	renderName := RenderSessionName(name, layout.NameWidth, opts.Selected, opts.Matches)
+	if opts.Pinned {
+		renderIndex = RenderPinnedIndex(opts.Num, opts.Selected)
+	}
+	if opts.Marked {
+		renderIndex = RenderMarkedIndex(opts.Selected)
+	}
 	if opts.IsSelf {
 		renderIndex = RenderSelfIndex(opts.Selected)
 		renderName = RenderSelfSessionName(name, layout.NameWidth, opts.Selected)
@@ -227,14 +355,8 @@ func RenderSessionRow(name string, lastActivity time.Time, layout RowLayout, opt
 		SpacerStyle(" ", opts.Selected),
 	}
 
-	// Status columns: always show both CC and Pi together (reserved space for alignment).
-	// Spacer between icons is 2 chars wide to align under the 2-char "CC"/"Pi" headers.
-	cols = append(cols,
-		RenderClaudeIcon(opts.ClaudeStatus, opts.AnimFrame, opts.Selected),
-		SpacerStyle("  ", opts.Selected),
-		RenderPiIcon(opts.PiStatus, opts.AnimFrame, opts.Selected),
-		SpacerStyle(" ", opts.Selected),
-	)
+	// Status columns: one per agent kind (reserved space for alignment)
+	cols = append(cols, renderAgentColumns(layout, opts.RowOpts)...)
 
 	// Expand icon (optional)
 	if opts.ShowExpandIcon {
@@ -249,6 +371,14 @@ func RenderSessionRow(name string, lastActivity time.Time, layout RowLayout, opt
 		cols = append(cols, SpacerStyle("  ", opts.Selected), RenderTimeAgo(*opts.LastActivity, opts.Selected))
 	}
 
+	// Branch and ahead/behind (optional columns)
+	if layout.BranchWidth > 0 {
+		cols = append(cols, SpacerStyle(" ", opts.Selected), RenderBranchColumn(opts.GitStatus, layout.BranchWidth, opts.Selected))
+	}
+	if layout.GitSyncWidth > 0 {
+		cols = append(cols, SpacerStyle(" ", opts.Selected), RenderGitSyncColumn(opts.GitStatus, layout.GitSyncWidth, opts.Selected))
+	}
+
 	// Git status (optional column)
 	if layout.GitStatusWidth > 0 {
 		cols = append(cols, SpacerStyle(" ", opts.Selected), RenderGitStatusColumn(opts.GitStatus, layout.GitStatusWidth, opts.Selected, opts.GitStatusLoading, opts.AnimFrame))
@@ -266,14 +396,10 @@ func RenderBookmarkRow(name string, layout RowLayout, opts RowOpts, width int) s
 	cols := []string{
 		RenderIndex(opts.Num, opts.Selected),
 		SpacerStyle(" ", opts.Selected),
-		// Status columns: always show both CC and Pi together (reserved space for alignment).
-		// Spacer between icons is 2 chars wide to align under the 2-char "CC"/"Pi" headers.
-		RenderClaudeIcon(opts.ClaudeStatus, opts.AnimFrame, opts.Selected),
-		SpacerStyle("  ", opts.Selected),
-		RenderPiIcon(opts.PiStatus, opts.AnimFrame, opts.Selected),
-		SpacerStyle(" ", opts.Selected),
-		RenderSessionName(name, layout.NameWidth, opts.Selected),
 	}
+	// Status columns: one per agent kind (reserved space for alignment)
+	cols = append(cols, renderAgentColumns(layout, opts)...)
+	cols = append(cols, RenderSessionName(name, layout.NameWidth, opts.Selected, opts.Matches))
 
 	// Git status (optional)
 	if layout.GitStatusWidth > 0 {
@@ -292,8 +418,6 @@ type TableHeaderOpts struct {
 	ShowExpandIcon bool
 	ShowTime       bool
 	ShowGit        bool
-	ShowCC         bool   // Show Claude Code status column
-	ShowPi         bool   // Show Pi status column
 	NameLabel      string // e.g., "Session" or "Bookmark"
 }
 
@@ -306,12 +430,9 @@ func RenderTableHeader(layout RowLayout, opts TableHeaderOpts) string {
 		dim.Render(fmt.Sprintf("%-3s", "#")),
 	}
 
-	// Status columns: show which ones are enabled (data rows always reserve both)
-	if opts.ShowCC {
-		cols = append(cols, " ", CCHeaderStyle.Render("CC"))
-	}
-	if opts.ShowPi {
-		cols = append(cols, " ", PiHeaderStyle.Render("Pi"))
+	// Status columns, matching the icons reserved in data rows
+	for _, kind := range layout.AgentKinds {
+		cols = append(cols, " ", AgentHeaderStyle(kind).Render(fmt.Sprintf("%-2s", kind.Header)))
 	}
 
 	// Expand icon placeholder
@@ -331,6 +452,14 @@ func RenderTableHeader(layout RowLayout, opts TableHeaderOpts) string {
 		cols = append(cols, "  ", dim.Render(fmt.Sprintf("%-8s", "ACT")))
 	}
 
+	// Branch and ahead/behind column headers, cut to narrow columns
+	if layout.BranchWidth > 0 {
+		cols = append(cols, " ", dim.Render(fmt.Sprintf("%-*s", layout.BranchWidth, ansi.Truncate("BRANCH", layout.BranchWidth, ""))))
+	}
+	if layout.GitSyncWidth > 0 {
+		cols = append(cols, " ", dim.Render(fmt.Sprintf("%-*s", layout.GitSyncWidth, ansi.Truncate("↑↓", layout.GitSyncWidth, ""))))
+	}
+
 	// Git column header
 	if opts.ShowGit && layout.GitStatusWidth > 0 {
 		cols = append(cols, " ", dim.Render(fmt.Sprintf("%-*s", layout.GitStatusWidth, "GIT")))
@@ -340,13 +469,37 @@ func RenderTableHeader(layout RowLayout, opts TableHeaderOpts) string {
 	return TableHeaderStyle.Render(content)
 }
 
+// RenderGroupRow composes a group header row: expand icon, group name, and
+// the number of sessions in the group
+func RenderGroupRow(name string, count int, collapsed, selected bool, width int) string {
+	nameStyle, countStyle := GroupNameStyle, GroupCountStyle
+	if selected {
+		nameStyle, countStyle = GroupNameSelectedStyle, selectedBase(GroupCountStyle)
+	}
+	content := RenderExpandIcon(!collapsed, selected) +
+		SpacerStyle(" ", selected) +
+		nameStyle.Render(name) +
+		countStyle.Render(fmt.Sprintf(" (%d)", count))
+	if selected {
+		return SessionSelectedStyle.Width(width).Render(content)
+	}
+	return SessionStyle.Width(width).Render(content)
+}
+
 // RenderWindowRow composes a window row
 func RenderWindowRow(index int, name string, opts WindowRowOpts, width int) string {
 	var parts []string
 
 	// Expand icon for windows (shows if window can be expanded to show panes)
 	parts = append(parts, RenderExpandIcon(opts.Expanded, opts.Selected))
-	parts = append(parts, SpacerStyle(" ", opts.Selected))
+	switch {
+	case opts.Marked && opts.Selected:
+		parts = append(parts, MarkSelectedStyle.Render(MarkIcon))
+	case opts.Marked:
+		parts = append(parts, MarkStyle.Render(MarkIcon))
+	default:
+		parts = append(parts, SpacerStyle(" ", opts.Selected))
+	}
 
 	// Window name with index
 	parts = append(parts, RenderWindowName(index, name, opts.Selected))
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/git"
+)
+
+func TestAgentColumnsAlign(t *testing.T) {
+	codex := agent.Kind{Name: "codex", Header: "Co", Color: "#ff8800"}
+	layout := RowLayout{NameWidth: 8, AgentKinds: []agent.Kind{agent.Claude, agent.Pi, codex}}
+
+	header := ansi.Strip(RenderTableHeader(layout, TableHeaderOpts{NameLabel: "SESS"}))
+	if !strings.Contains(header, "CC Pi Co") {
+		t.Fatalf("header = %q, want CC Pi Co columns", header)
+	}
+
+	now := time.Now()
+	opts := RowOpts{
+		Num:  1,
+		Name: "api",
+		AgentStatuses: map[string]*agent.Status{
+			"claude": {State: "waiting", Timestamp: now},
+			"codex":  {State: "waiting", Timestamp: now},
+		},
+	}
+	// Data rows start after the 2-char scrollbar column the header pads for
+	row := "  " + ansi.Strip(RenderBookmarkRow("api", layout, opts, 60))
+
+	for _, label := range []string{"CC", "Co"} {
+		col := strings.Index(header, label)
+		if col >= len(row) || row[col] != '?' {
+			t.Errorf("%s icon not under its header:\n%s\n%s", label, header, row)
+		}
+	}
+	if pi := strings.Index(header, "Pi"); row[pi] != ' ' {
+		t.Errorf("Pi column should be blank without a status:\n%s\n%s", header, row)
+	}
+}
+
+// FixedWidth must follow RenderSessionRow: the branch columns are fitted
+// into what it leaves of the row.
+func TestRowLayoutFixedWidth(t *testing.T) {
+	activity := time.Now()
+	status := &git.Status{IsRepo: true, Branch: "main", Upstream: "origin/main", Ahead: 1}
+	opts := SessionRowOpts{RowOpts: RowOpts{Num: 1, ShowExpandIcon: true, LastActivity: &activity, GitStatus: status}}
+
+	layouts := []RowLayout{
+		{NameWidth: 8},
+		{NameWidth: 12, GitStatusWidth: 6, BranchWidth: 10, GitSyncWidth: 4},
+		{NameWidth: 8, BranchWidth: 6, AgentKinds: []agent.Kind{agent.Claude, agent.Pi}},
+	}
+	for _, layout := range layouts {
+		want := layout.FixedWidth()
+		if layout.BranchWidth > 0 {
+			want += 1 + layout.BranchWidth
+		}
+		if layout.GitSyncWidth > 0 {
+			want += 1 + layout.GitSyncWidth
+		}
+		// A width of 0 leaves the row as wide as its columns
+		if got := ansi.StringWidth(RenderSessionRow("api", activity, layout, opts, 0)); got != want {
+			t.Errorf("%+v: row width = %d, want %d", layout, got, want)
+		}
+	}
+}
+
+func TestBranchColumnsAlign(t *testing.T) {
+	layout := RowLayout{NameWidth: 8, BranchWidth: 10, GitSyncWidth: 4, AgentKinds: []agent.Kind{agent.Claude}}
+	header := []rune(ansi.Strip(RenderTableHeader(layout, TableHeaderOpts{ShowExpandIcon: true, ShowTime: true, NameLabel: "SESS"})))
+
+	activity := time.Now()
+	status := &git.Status{IsRepo: true, Branch: "feat/login-form", Upstream: "origin/feat/login-form", Ahead: 2, Behind: 1}
+	opts := SessionRowOpts{RowOpts: RowOpts{Num: 1, ShowExpandIcon: true, LastActivity: &activity, GitStatus: status}}
+	row := []rune("  " + ansi.Strip(RenderSessionRow("api", activity, layout, opts, 80)))
+
+	branch := strings.Index(string(header), "BRANCH")
+	branch = len([]rune(string(header)[:branch]))
+	if got := string(row[branch : branch+11]); got != "feat/logi… " {
+		t.Errorf("branch column = %q:\n%s\n%s", got, string(header), string(row))
+	}
+	sync := strings.Index(string(header), "↑↓")
+	sync = len([]rune(string(header)[:sync]))
+	if got := string(row[sync : sync+4]); got != "↑2↓1" {
+		t.Errorf("ahead/behind column = %q:\n%s\n%s", got, string(header), string(row))
+	}
+}
+
+func TestTruncateBranch(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{"main", 10, "main"},
+		{"feature/login", 13, "feature/login"},
+		{"feature/login", 8, "feature…"},
+		{"feature/login", 1, "…"},
+	}
+	for _, tt := range tests {
+		if got := TruncateBranch(tt.name, tt.width); got != tt.want {
+			t.Errorf("TruncateBranch(%q, %d) = %q, want %q", tt.name, tt.width, got, tt.want)
+		}
+	}
+}
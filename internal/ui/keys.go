@@ -11,12 +11,21 @@ type KeyMap struct {
 	Select        key.Binding
 	Kill          key.Binding
 	Create        key.Binding
+	Rename        key.Binding
+	NextWaiting   key.Binding
+	Reply         key.Binding
+	Preview       key.Binding
 	PickDirectory key.Binding
 	OpenRemote    key.Binding
 	DownloadRepo  key.Binding
 	Lazygit       key.Binding
 	Bookmarks     key.Binding
 	AddBookmark   key.Binding
+	Pin           key.Binding
+	Sort          key.Binding
+	Mark          key.Binding
+	MarkAll       key.Binding
+	Detach        key.Binding
 	Quit          key.Binding
 	Help          key.Binding
 	Cancel        key.Binding
@@ -64,6 +73,22 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("ctrl+n"),
 		key.WithHelp("C-n", "New"),
 	),
+	Rename: key.NewBinding(
+		key.WithKeys("ctrl+e"),
+		key.WithHelp("C-e", "Rename"),
+	),
+	NextWaiting: key.NewBinding(
+		key.WithKeys("ctrl+w"),
+		key.WithHelp("C-w", "Next waiting agent"),
+	),
+	Reply: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("C-t", "Reply to agent"),
+	),
+	Preview: key.NewBinding(
+		key.WithKeys("ctrl+v"),
+		key.WithHelp("C-v", "Toggle pane preview"),
+	),
 	PickDirectory: key.NewBinding(
 		key.WithKeys("ctrl+p"),
 		key.WithHelp("C-p", "Projects"),
@@ -88,6 +113,26 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("ctrl+a"),
 		key.WithHelp("C-a", "Add bookmark"),
 	),
+	Pin: key.NewBinding(
+		key.WithKeys("ctrl+f"),
+		key.WithHelp("C-f", "Pin"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("C-s", "Sort"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("Tab", "Mark"),
+	),
+	MarkAll: key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("S-Tab", "Mark all"),
+	),
+	Detach: key.NewBinding(
+		key.WithKeys("ctrl+o"),
+		key.WithHelp("C-o", "Detach other clients"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("ctrl+c"),
 		key.WithHelp("C-c", "Quit"),
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// PreviewPanelRatio is the preview panel's share of the content area, in
+// percent. Pane content needs more room than the AGENTS panel.
+const PreviewPanelRatio = 50
+
+// sgrPattern matches SGR (color/attribute) escape sequences.
+var sgrPattern = regexp.MustCompile(`\x1b\[[0-9;:]*m`)
+
+// RenderPreviewPanel renders captured pane content as the right-hand
+// PREVIEW panel: a header naming the target, then the content lines with
+// their colors, hard-truncated to width. Like the AGENTS panel it is padded
+// to height lines, each carrying the left rule separator.
+func RenderPreviewPanel(target string, content []string, width, height int) string {
+	header := HelpSectionStyle.Render("PREVIEW")
+	if target != "" {
+		header += " " + HelpDescStyle.Render(truncateTo(target, width-8))
+	}
+	lines := []string{header, ""}
+
+	if content == nil {
+		lines = append(lines, HelpDescStyle.Render("loading…"))
+	} else {
+		// Show the bottom of the pane, where the prompt and latest output
+		// are; colors are carried before cutting so none get lost
+		content = carrySGR(content)
+		if room := height - len(lines); room > 0 && len(content) > room {
+			content = content[len(content)-room:]
+		}
+		for _, line := range content {
+			// Reset so colors don't bleed into the rule or the next row
+			lines = append(lines, ansi.Truncate(line, width, "")+"\x1b[0m")
+		}
+	}
+
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
+
+	rule := BorderStyle.Render("│")
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(rule)
+		b.WriteString(" ")
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// carrySGR makes every line self-contained: tmux only emits an attribute
+// change where it happens, so a color started on one line stays active on
+// the following ones. Each line is prefixed with the SGR sequences still in
+// effect from the lines before it.
+func carrySGR(lines []string) []string {
+	out := make([]string, len(lines))
+	var active []string
+	for i, line := range lines {
+		out[i] = strings.Join(active, "") + line
+		for _, seq := range sgrPattern.FindAllString(line, -1) {
+			params := seq[2 : len(seq)-1]
+			switch {
+			case params == "" || params == "0":
+				active = nil
+			case strings.HasPrefix(params, "0;"):
+				active = []string{seq}
+			default:
+				active = append(active, seq)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCarrySGR(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "plain",
+			lines: []string{"a", "b"},
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "color spans lines",
+			lines: []string{"\x1b[31mred", "still red", "\x1b[1mbold\x1b[0m", "plain"},
+			want:  []string{"\x1b[31mred", "\x1b[31mstill red", "\x1b[31m\x1b[1mbold\x1b[0m", "plain"},
+		},
+		{
+			name:  "reset with new color",
+			lines: []string{"\x1b[31mred", "\x1b[0;32mgreen", "next"},
+			want:  []string{"\x1b[31mred", "\x1b[31m\x1b[0;32mgreen", "\x1b[0;32mnext"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := carrySGR(tt.lines); !slices.Equal(got, tt.want) {
+				t.Errorf("carrySGR() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,6 +25,15 @@ func NewScrollList[T any](filterFn func(T, string) bool) *ScrollList[T] {
 	}
 }
 
+// NewRankedScrollList creates a new ScrollList that lists the best matches
+// of the filter first (see filter.NewRanked).
+func NewRankedScrollList[T any](scoreFn func(T, string) (int, bool)) *ScrollList[T] {
+	return &ScrollList[T]{
+		filter: filter.NewRanked[T](nil, scoreFn),
+		height: 10, // Default fallback
+	}
+}
+
 // SetItems replaces all items and re-applies the current filter
 func (s *ScrollList[T]) SetItems(items []T) {
 	s.items = items
@@ -46,10 +55,11 @@ func (s *ScrollList[T]) Height() int {
 	return s.height
 }
 
-// SetFilter sets the filter text and re-filters the items
+// SetFilter sets the filter text and re-filters the items. The cursor goes
+// back to the top, the best match.
 func (s *ScrollList[T]) SetFilter(filter string) {
 	s.filter.SetFilter(filter)
-	s.clampCursor()
+	s.cursor = 0
 	s.updateScrollOffset()
 }
 
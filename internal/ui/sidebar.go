@@ -17,15 +17,42 @@ type Action struct {
 // SessionActions are the actions shown in ModeNormal (session list)
 var SessionActions = []Action{
 	{Label: "SWITCH", Keybind: "Enter"},
+	{Label: "WAITING", Keybind: "C-w"},
+	{Label: "REPLY", Keybind: "C-t"},
+	{Label: "PREVIEW", Keybind: "C-v"},
 	{Label: "BOOKMARKS", Keybind: "C-b"},
 	{Label: "PROJECTS", Keybind: "C-p"},
 	{Label: "DOWNLOAD", Keybind: "C-d"},
 	{Label: "NEW", Keybind: "C-n"},
+	{Label: "RENAME", Keybind: "C-e"},
+	{Label: "PIN", Keybind: "C-f"},
+	{Label: "SORT", Keybind: "C-s"},
+	{Label: "MARK", Keybind: "Tab"},
+	{Label: "DETACH", Keybind: "C-o"},
 	{Label: "LAZYGIT", Keybind: "C-g"},
 	{Label: "REMOTE", Keybind: "C-r"},
 	{Label: "KILL", Keybind: "C-x", Warning: true},
 }
 
+// MarkActions are the actions shown in ModeNormal while items are marked:
+// they apply to the marked set
+var MarkActions = []Action{
+	{Label: "MARK", Keybind: "Tab"},
+	{Label: "MARK ALL", Keybind: "S-Tab"},
+	{Label: "SEND", Keybind: "C-t"},
+	{Label: "DETACH", Keybind: "C-o"},
+	{Label: "LAZYGIT", Keybind: "C-g"},
+	{Label: "BOOKMARK", Keybind: "C-a"},
+	{Label: "UNMARK", Keybind: "Esc"},
+	{Label: "KILL", Keybind: "C-x", Warning: true},
+}
+
+// SendActions are the actions shown in ModeSend
+var SendActions = []Action{
+	{Label: "SEND", Keybind: "Enter"},
+	{Label: "CANCEL", Keybind: "Esc"},
+}
+
 // BookmarkActions are the actions shown in ModeBookmarks
 var BookmarkActions = []Action{
 	{Label: "OPEN", Keybind: "Enter"},
@@ -38,13 +65,28 @@ var BookmarkActions = []Action{
 // ProjectActions are the actions shown in ModePickDirectory
 var ProjectActions = []Action{
 	{Label: "SELECT", Keybind: "Enter"},
+	{Label: "WORKTREES", Keybind: "→"},
 	{Label: "BOOKMARK", Keybind: "C-a"},
 	{Label: "REMOVE", Keybind: "C-x", Warning: true},
 }
 
+// WorktreeActions are the actions shown in ModePickWorktree
+var WorktreeActions = []Action{
+	{Label: "OPEN", Keybind: "Enter"},
+	{Label: "NEW FROM FILTER", Keybind: "C-n"},
+	{Label: "BACK", Keybind: "←"},
+}
+
+// ConfirmPruneActions are the actions shown in ModeConfirmPruneWorktree
+var ConfirmPruneActions = []Action{
+	{Label: "PRUNE", Keybind: "C-x", Warning: true},
+	{Label: "KEEP", Keybind: "Esc"},
+}
+
 // CloneActions are the actions shown in ModeCloneRepo/ModeCloneChoice/ModeCloneURL
 var CloneActions = []Action{
 	{Label: "CLONE", Keybind: "Enter"},
+	{Label: "MODE", Keybind: "Tab"},
 }
 
 // CreateActions are the actions shown in ModeCreate/ModeCreatePath
@@ -52,6 +94,19 @@ var CreateActions = []Action{
 	{Label: "CREATE", Keybind: "Enter"},
 }
 
+// RenameActions are the actions shown in ModeRename
+var RenameActions = []Action{
+	{Label: "RENAME", Keybind: "Enter"},
+	{Label: "CANCEL", Keybind: "Esc"},
+}
+
+// ReplyActions are the actions shown in ModeReply
+var ReplyActions = []Action{
+	{Label: "SEND", Keybind: "Enter"},
+	{Label: "CANNED", Keybind: "Tab"},
+	{Label: "CANCEL", Keybind: "Esc"},
+}
+
 // ConfirmKillActions are the actions shown in ModeConfirmKill
 var ConfirmKillActions = []Action{
 	{Label: "CONFIRM", Keybind: "C-x", Warning: true},
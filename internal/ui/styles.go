@@ -2,10 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/black-atom-industries/helm/internal/agent"
 )
 
 // Border and padding overhead for the app container
@@ -18,6 +21,9 @@ const (
 	ScrollbarColumnWidth = 2 // scrollbar char + space
 )
 
+// MarkIcon marks rows picked for a bulk action
+const MarkIcon = "✓"
+
 // Styles — initialized with dark defaults, call InitColors() to reinitialize
 // for light mode or a Black Atom theme
 var (
@@ -40,9 +46,20 @@ var (
 	PaneStyle         lipgloss.Style
 	PaneSelectedStyle lipgloss.Style
 
+	// Group header row styles
+	GroupNameStyle         lipgloss.Style
+	GroupNameSelectedStyle lipgloss.Style
+	GroupCountStyle        lipgloss.Style
+
 	// Text styles
 	IndexStyle               lipgloss.Style
 	IndexSelectedStyle       lipgloss.Style
+	PinnedIndexStyle         lipgloss.Style
+	PinnedIndexSelectedStyle lipgloss.Style
+	MatchStyle               lipgloss.Style // Filter matches in names, over the name's style
+	MatchSelectedStyle       lipgloss.Style
+	MarkStyle                lipgloss.Style
+	MarkSelectedStyle        lipgloss.Style
 	SessionNameStyle         lipgloss.Style
 	SessionNameSelectedStyle lipgloss.Style
 	WindowNameStyle          lipgloss.Style
@@ -77,6 +94,9 @@ var (
 	GitAddStyle     lipgloss.Style
 	GitDelStyle     lipgloss.Style
 	GitLoadingStyle lipgloss.Style
+	GitBranchStyle  lipgloss.Style
+	GitAheadStyle   lipgloss.Style
+	GitBehindStyle  lipgloss.Style
 
 	// Input styles
 	InputPromptStyle lipgloss.Style
@@ -95,6 +115,9 @@ var (
 	BorderStyle    lipgloss.Style
 	SeparatorStyle lipgloss.Style
 
+	// Progress bar style (the done part; the rest uses SeparatorStyle)
+	ProgressStyle lipgloss.Style
+
 	// Statusline style
 	StatuslineStyle lipgloss.Style
 
@@ -203,6 +226,16 @@ func initStyles() {
 		PaddingLeft(14).
 		Bold(true)
 
+	GroupNameStyle = lipgloss.NewStyle().
+		Foreground(Colors.Fg.Accent).
+		Bold(true)
+
+	GroupNameSelectedStyle = selectedBase(GroupNameStyle).
+		Bold(true)
+
+	GroupCountStyle = lipgloss.NewStyle().
+		Foreground(Colors.Fg.Muted)
+
 	IndexStyle = lipgloss.NewStyle().
 		Foreground(Colors.Fg.Subtle).
 		Width(3)
@@ -210,6 +243,28 @@ func initStyles() {
 	IndexSelectedStyle = selectedBase(IndexStyle).
 		Bold(true)
 
+	PinnedIndexStyle = lipgloss.NewStyle().
+		Foreground(Colors.Fg.Accent).
+		Width(3)
+
+	PinnedIndexSelectedStyle = selectedBase(PinnedIndexStyle).
+		Bold(true)
+
+	MarkStyle = lipgloss.NewStyle().
+		Foreground(Colors.Fg.Accent).
+		Bold(true)
+
+	MarkSelectedStyle = selectedBase(MarkStyle)
+
+	MatchStyle = lipgloss.NewStyle().
+		Foreground(Colors.Fg.Accent).
+		Bold(true)
+
+	// Selected rows keep their colors; the match is underlined instead
+	MatchSelectedStyle = lipgloss.NewStyle().
+		Bold(true).
+		Underline(true)
+
 	SessionNameStyle = lipgloss.NewStyle().
 		Foreground(Colors.Fg.SessionName)
 
@@ -285,6 +340,15 @@ func initStyles() {
 	GitLoadingStyle = lipgloss.NewStyle().
 		Foreground(Colors.Fg.Muted)
 
+	GitBranchStyle = lipgloss.NewStyle().
+		Foreground(Colors.Fg.Subtle)
+
+	GitAheadStyle = lipgloss.NewStyle().
+		Foreground(Colors.Fg.GitAdd)
+
+	GitBehindStyle = lipgloss.NewStyle().
+		Foreground(Colors.Fg.GitDel)
+
 	InputPromptStyle = lipgloss.NewStyle().
 		Foreground(Colors.Fg.Accent)
 
@@ -317,6 +381,9 @@ func initStyles() {
 	SeparatorStyle = lipgloss.NewStyle().
 		Foreground(Colors.Fg.Separator)
 
+	ProgressStyle = lipgloss.NewStyle().
+		Foreground(Colors.Fg.Accent)
+
 	StatuslineStyle = lipgloss.NewStyle().
 		Foreground(Colors.Fg.Muted).
 		Padding(0, 1)
@@ -387,6 +454,13 @@ func RenderDottedBorder(width int) string {
 	return SeparatorStyle.Render(strings.Repeat("·", width))
 }
 
+// RenderProgressBar returns a bar width cells wide, percent of it filled
+func RenderProgressBar(percent, width int) string {
+	filled := width * min(max(percent, 0), 100) / 100
+	return ProgressStyle.Render(strings.Repeat("█", filled)) +
+		SeparatorStyle.Render(strings.Repeat("░", width-filled))
+}
+
 // RenderTitleBar renders the inverted title bar with logo on left and view name on right
 func RenderTitleBar(logo, viewName string, width int) string {
 	// Account for padding in AppStyle (1 on each side)
@@ -534,6 +608,31 @@ func FormatPiIcon(state string, animationFrame int, waitDuration time.Duration,
 	return style.Render(char)
 }
 
+// FormatAgentIcon formats the status icon of any agent kind. Pi has its own
+// palette entries; Claude's are shared by all other kinds, except that a
+// declared kind's glyph and color replace the spinner and its color. The
+// attention icons (?, !, Z) look the same in every column.
+func FormatAgentIcon(kind agent.Kind, state string, animationFrame int, waitDuration time.Duration, selected bool) string {
+	switch {
+	case kind.Name == "pi":
+		return FormatPiIcon(state, animationFrame, waitDuration, selected)
+	case state != "working" || (kind.Glyph == "" && kind.Color == ""):
+		return FormatClaudeIcon(state, animationFrame, waitDuration, selected)
+	}
+	char := StatusIconChar(state, animationFrame, waitDuration)
+	if kind.Glyph != "" {
+		char = kind.Glyph
+	}
+	style := ClaudeWorkingStyle
+	if kind.Color != "" {
+		style = style.Foreground(lipgloss.Color(kind.Color))
+	}
+	if selected {
+		style = selectedBase(style)
+	}
+	return style.Render(char)
+}
+
 // GitStatusColumnWidth is the fixed width for the git status column
 const GitStatusColumnWidth = 20 // fits "99 files +99 -99"
 
@@ -613,6 +712,38 @@ func GitStatusWidth(dirty, additions, deletions int) int {
 	return len(strings.Join(parts, " "))
 }
 
+// FormatGitSync formats commits ahead of/behind the upstream as "↑2↓1".
+// Returns "" when the branch is in sync.
+func FormatGitSync(ahead, behind int, selected bool) string {
+	aheadStyle := GitAheadStyle
+	behindStyle := GitBehindStyle
+	if selected {
+		aheadStyle = selectedBase(aheadStyle)
+		behindStyle = selectedBase(behindStyle)
+	}
+
+	var parts []string
+	if ahead > 0 {
+		parts = append(parts, aheadStyle.Render(fmt.Sprintf("↑%d", ahead)))
+	}
+	if behind > 0 {
+		parts = append(parts, behindStyle.Render(fmt.Sprintf("↓%d", behind)))
+	}
+	return strings.Join(parts, "")
+}
+
+// GitSyncWidth returns the visual width of FormatGitSync's output
+func GitSyncWidth(ahead, behind int) int {
+	width := 0
+	if ahead > 0 {
+		width += 1 + len(strconv.Itoa(ahead))
+	}
+	if behind > 0 {
+		width += 1 + len(strconv.Itoa(behind))
+	}
+	return width
+}
+
 // ScrollbarChars returns scrollbar characters for each visible line
 // totalItems: total number of items in the list
 // visibleItems: number of items currently visible
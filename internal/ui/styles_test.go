@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
 )
 
 func TestFormatClaudeIcon(t *testing.T) {
@@ -138,6 +140,28 @@ func TestFormatClaudeIcon(t *testing.T) {
 	}
 }
 
+func TestFormatAgentIcon(t *testing.T) {
+	codex := agent.Kind{Name: "codex", Glyph: "◆", Color: "#ff8800"}
+	tests := []struct {
+		name     string
+		kind     agent.Kind
+		state    string
+		contains string
+	}{
+		{"glyph replaces the spinner", codex, "working", "◆"},
+		{"attention icon kept", codex, "waiting", "?"},
+		{"spinner without glyph", agent.Kind{Name: "aider", Color: "#ff8800"}, "working", ClaudeSpinnerFrames[0]},
+		{"idle", codex, "", " "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatAgentIcon(tt.kind, tt.state, 0, 0, false); !strings.Contains(got, tt.contains) {
+				t.Errorf("FormatAgentIcon(%q) = %q, should contain %q", tt.state, got, tt.contains)
+			}
+		})
+	}
+}
+
 func TestScrollbarChars(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -244,3 +268,23 @@ func TestRenderBorder(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderProgressBar(t *testing.T) {
+	tests := []struct {
+		percent    int
+		wantFilled int
+	}{
+		{0, 0},
+		{45, 4},
+		{100, 10},
+		{150, 10}, // clamped
+		{-5, 0},
+	}
+	for _, tt := range tests {
+		result := RenderProgressBar(tt.percent, 10)
+		filled, empty := strings.Count(result, "█"), strings.Count(result, "░")
+		if filled != tt.wantFilled || filled+empty != 10 {
+			t.Errorf("RenderProgressBar(%d, 10) = %d filled, %d empty, want %d filled of 10", tt.percent, filled, empty, tt.wantFilled)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Package watch turns agent status changes into attention events — an
+// agent going from working to waiting, or waiting past a threshold — and
+// delivers them as tmux messages and a configurable notify command. It
+// backs the `helm watch` daemon, which runs without the TUI open.
+package watch
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+	"github.com/black-atom-industries/helm/internal/tmux"
+)
+
+// Event kinds
+const (
+	EventWaiting = "waiting" // working → waiting transition
+	EventOverdue = "overdue" // waiting longer than the threshold
+)
+
+// Event is one notification-worthy change of an agent instance.
+type Event struct {
+	Kind    string // EventWaiting or EventOverdue
+	Session string
+	Agent   string // Agent kind name
+	Tool    string
+	Since   time.Time // When the agent started waiting
+}
+
+// Message returns the human-readable notification text.
+func (e Event) Message(now time.Time) string {
+	msg := fmt.Sprintf("%s in %s is waiting", e.Agent, e.Session)
+	if e.Kind == EventOverdue {
+		msg += " for " + formatWait(now.Sub(e.Since))
+	}
+	if e.Tool != "" {
+		msg += " (" + e.Tool + ")"
+	}
+	return msg
+}
+
+// instance identifies one agent instance across polls.
+type instance struct {
+	kind, session, id string
+}
+
+// seen is what the tracker remembers about an instance.
+type seen struct {
+	state    string
+	since    time.Time
+	notified bool // Overdue event already sent for this waiting period
+}
+
+// Tracker compares successive status snapshots and emits events.
+type Tracker struct {
+	threshold time.Duration
+	last      map[instance]seen
+}
+
+// NewTracker returns a tracker emitting overdue events after threshold;
+// 0 disables them.
+func NewTracker(threshold time.Duration) *Tracker {
+	return &Tracker{threshold: threshold, last: make(map[instance]seen)}
+}
+
+// Update takes the current statuses — kind name → session → instances —
+// and returns the events since the previous call. Agents already waiting
+// on the first call only produce overdue events, so starting the daemon
+// doesn't replay old transitions.
+func (t *Tracker) Update(now time.Time, statuses map[string]map[string][]agent.Status) []Event {
+	var events []Event
+	next := make(map[instance]seen)
+
+	for kind, sessions := range statuses {
+		for session, instances := range sessions {
+			for i, s := range instances {
+				id := s.SessionID
+				if id == "" {
+					id = fmt.Sprintf("#%d", i) // legacy status files carry no id
+				}
+				key := instance{kind: kind, session: session, id: id}
+				prev, known := t.last[key]
+				cur := seen{state: s.State, since: s.Timestamp}
+
+				if s.State == "waiting" {
+					ev := Event{Session: session, Agent: kind, Tool: s.Tool, Since: s.Timestamp}
+					switch {
+					case known && prev.state == "working":
+						ev.Kind = EventWaiting
+						events = append(events, ev)
+					case known && prev.state == "waiting" && prev.since.Equal(s.Timestamp):
+						cur.notified = prev.notified
+					}
+					if !cur.notified && t.threshold > 0 && now.Sub(s.Timestamp) >= t.threshold {
+						ev.Kind = EventOverdue
+						events = append(events, ev)
+						cur.notified = true
+					}
+				}
+				next[key] = cur
+			}
+		}
+	}
+
+	t.last = next
+	return events
+}
+
+// Notify delivers an event: a tmux message and/or the notify command.
+func Notify(cfg config.NotificationsConfig, e Event, now time.Time) error {
+	msg := e.Message(now)
+	var errs []string
+	if cfg.Tmux {
+		if err := tmux.ShowMessage("helm: " + msg); err != nil {
+			errs = append(errs, fmt.Sprintf("tmux: %v", err))
+		}
+	}
+	if cfg.NotifyCommand != "" {
+		cmd := exec.Command("sh", "-c", ExpandCommand(cfg.NotifyCommand))
+		cmd.Env = append(os.Environ(), commandEnv(e, msg, now)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			errs = append(errs, fmt.Sprintf("notify_command: %v: %s", err, strings.TrimSpace(string(out))))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// placeholders maps notify command placeholders to the environment
+// variables carrying their values.
+var placeholders = map[string]string{
+	"{session}": "HELM_SESSION",
+	"{agent}":   "HELM_AGENT",
+	"{tool}":    "HELM_TOOL",
+	"{event}":   "HELM_EVENT",
+	"{waited}":  "HELM_WAITED",
+	"{message}": "HELM_MESSAGE",
+}
+
+// ExpandCommand rewrites placeholders to shell variable references.
+// Session and tool names come from outside helm, so values are never
+// spliced into the command text: "{message}" becomes "${HELM_MESSAGE}",
+// which the shell expands without re-parsing.
+func ExpandCommand(command string) string {
+	for placeholder, env := range placeholders {
+		command = strings.ReplaceAll(command, placeholder, "${"+env+"}")
+	}
+	return command
+}
+
+// commandEnv returns the placeholder values as environment entries.
+func commandEnv(e Event, message string, now time.Time) []string {
+	return []string{
+		"HELM_SESSION=" + e.Session,
+		"HELM_AGENT=" + e.Agent,
+		"HELM_TOOL=" + e.Tool,
+		"HELM_EVENT=" + e.Kind,
+		"HELM_WAITED=" + formatWait(now.Sub(e.Since)),
+		"HELM_MESSAGE=" + message,
+	}
+}
+
+// formatWait formats a waiting duration as "4m" or "1h05m".
+func formatWait(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,95 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/black-atom-industries/helm/internal/agent"
+	"github.com/black-atom-industries/helm/internal/config"
+)
+
+func TestTrackerUpdate(t *testing.T) {
+	start := time.Now()
+	statuses := func(state string, ts time.Time) map[string]map[string][]agent.Status {
+		return map[string]map[string][]agent.Status{
+			"claude": {"api": {{State: state, Timestamp: ts, SessionID: "s1", Tool: "Bash"}}},
+		}
+	}
+
+	tr := NewTracker(5 * time.Minute)
+
+	// Already waiting when the daemon starts: no transition event
+	if ev := tr.Update(start, statuses("waiting", start.Add(-time.Minute))); len(ev) != 0 {
+		t.Fatalf("first poll events = %+v, want none", ev)
+	}
+
+	tr.Update(start, statuses("working", start))
+	waitingSince := start.Add(time.Second)
+	ev := tr.Update(start.Add(2*time.Second), statuses("waiting", waitingSince))
+	if len(ev) != 1 || ev[0].Kind != EventWaiting || ev[0].Session != "api" || ev[0].Tool != "Bash" {
+		t.Fatalf("working→waiting events = %+v, want one waiting event", ev)
+	}
+
+	// Still waiting, below threshold: nothing
+	if ev := tr.Update(start.Add(time.Minute), statuses("waiting", waitingSince)); len(ev) != 0 {
+		t.Errorf("events below threshold = %+v, want none", ev)
+	}
+
+	// Past the threshold: one overdue event, not repeated
+	late := waitingSince.Add(6 * time.Minute)
+	if ev := tr.Update(late, statuses("waiting", waitingSince)); len(ev) != 1 || ev[0].Kind != EventOverdue {
+		t.Errorf("events past threshold = %+v, want one overdue event", ev)
+	}
+	if ev := tr.Update(late.Add(time.Minute), statuses("waiting", waitingSince)); len(ev) != 0 {
+		t.Errorf("repeated overdue events = %+v, want none", ev)
+	}
+
+	// A vanished instance is forgotten
+	tr.Update(late, map[string]map[string][]agent.Status{})
+	if len(tr.last) != 0 {
+		t.Errorf("tracker kept %d instances, want 0", len(tr.last))
+	}
+}
+
+func TestTrackerThresholdDisabled(t *testing.T) {
+	now := time.Now()
+	tr := NewTracker(0)
+	statuses := map[string]map[string][]agent.Status{
+		"pi": {"web": {{State: "waiting", Timestamp: now.Add(-time.Hour)}}},
+	}
+	if ev := tr.Update(now, statuses); len(ev) != 0 {
+		t.Errorf("events with threshold 0 = %+v, want none", ev)
+	}
+}
+
+func TestEventMessage(t *testing.T) {
+	now := time.Now()
+	e := Event{Kind: EventOverdue, Session: "api", Agent: "claude", Tool: "Edit", Since: now.Add(-65 * time.Minute)}
+	if got, want := e.Message(now), "claude in api is waiting for 1h05m (Edit)"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+}
+
+func TestNotifyCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	cfg := config.NotificationsConfig{
+		NotifyCommand: `printf '%s|%s|%s' "{session}" "{event}" "{message}" > ` + out,
+	}
+	now := time.Now()
+	// A hostile session name must not be executed
+	e := Event{Kind: EventWaiting, Session: "$(touch pwned)", Agent: "claude", Since: now}
+
+	if err := Notify(cfg, e, now); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "$(touch pwned)|waiting|claude in $(touch pwned) is waiting"
+	if string(data) != want {
+		t.Errorf("command output = %q, want %q", data, want)
+	}
+}